@@ -0,0 +1,155 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jvzantvoort/bundle/checksum"
+	"github.com/jvzantvoort/bundle/lock"
+	"github.com/jvzantvoort/bundle/metadata"
+	"github.com/jvzantvoort/bundle/state"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// AddFiles copies files into the root of the bundle at path and updates
+// its metadata, holding the bundle lock.
+//
+// Only the added files are hashed: their records are added to the
+// manifest, the bundle checksum in META.json is recomputed from all
+// records and the size in STATE.json grows by their size. Title, author,
+// created_at, tags and verification history are kept. Since the bundle
+// checksum identifies the bundle, adding files gives it a new identity; a
+// pool stores it under the old checksum until it is imported again.
+//
+// All files are checked before anything is copied: each must be a regular
+// file, and its name must not exist in the bundle root yet. When copying
+// or hashing fails, the files copied so far are removed again.
+//
+// Example:
+//
+//	b, err := bundle.AddFiles("/path/to/bundle", []string{"/tmp/notes.txt"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(b.Metadata.BundleChecksum) // the new bundle checksum
+//
+// Parameters:
+//   - path: absolute or relative path to the bundle directory
+//   - files: paths of the files to add
+//
+// Returns:
+//   - *Bundle: the updated bundle
+//   - error: utils.ErrNotABundle without .bundle/, utils.ErrInvalidPath for
+//     a missing, non-regular or already present file,
+//     utils.ErrBundleLocked if another process holds the lock, or I/O errors
+func AddFiles(path string, files []string) (*Bundle, error) {
+	if !utils.IsBundleDir(path) {
+		return nil, utils.ErrNotABundle
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: no files to add", utils.ErrInvalidPath)
+	}
+
+	bundleLock, err := lock.AcquireLockWait(path, lock.WaitTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := bundleLock.Release(); err != nil {
+			log.Errorf("failed to release lock: %v", err)
+		}
+	}()
+
+	meta, err := metadata.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := loadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check every file before copying any
+	names := make(map[string]string, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", utils.ErrInvalidPath, err)
+		}
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("%w: %s is not a regular file", utils.ErrInvalidPath, file)
+		}
+		name := filepath.Base(file)
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("%w: %s and %s have the same name", utils.ErrInvalidPath, other, file)
+		}
+		if _, err := os.Lstat(filepath.Join(path, name)); err == nil {
+			return nil, fmt.Errorf("%w: %s already exists in the bundle", utils.ErrInvalidPath, name)
+		}
+		if _, ok := manifest.Lookup(name); ok {
+			return nil, fmt.Errorf("%w: %s is already in the manifest", utils.ErrInvalidPath, name)
+		}
+		names[name] = file
+	}
+
+	// removeAdded undoes the copies after a failure
+	var added []string
+	removeAdded := func() {
+		for _, name := range added {
+			if err := os.Remove(filepath.Join(path, name)); err != nil {
+				log.Warnf("failed to remove %s: %v", name, err)
+			}
+		}
+	}
+
+	var addedSize int64
+	for _, file := range files {
+		name := filepath.Base(file)
+		dest := filepath.Join(path, name)
+		if err := utils.CopyFile(file, dest); err != nil {
+			removeAdded()
+			return nil, fmt.Errorf("failed to copy %s: %w", file, err)
+		}
+		added = append(added, name)
+
+		sum, err := checksum.ComputeFileHash(dest, manifest.Algorithm)
+		if err != nil {
+			removeAdded()
+			return nil, fmt.Errorf("failed to compute checksum of %s: %w", name, err)
+		}
+		info, err := os.Stat(dest)
+		if err != nil {
+			removeAdded()
+			return nil, err
+		}
+		manifest.Records = append(manifest.Records, checksum.ChecksumRecord{
+			Checksum: sum,
+			FilePath: name,
+			Size:     info.Size(),
+			ModTime:  info.ModTime().UTC(),
+		})
+		addedSize += info.Size()
+		log.Debugf("Added %s (%s)", name, sum)
+	}
+
+	meta.BundleChecksum = manifestChecksum(manifest)
+	if err := manifest.Save(path); err != nil {
+		return nil, fmt.Errorf("failed to save checksums: %w", err)
+	}
+	if err := meta.Save(path); err != nil {
+		return nil, fmt.Errorf("failed to save metadata: %w", err)
+	}
+
+	bundleState, err := state.Load(path)
+	if err != nil {
+		bundleState = &state.State{Replicas: []string{}, History: []state.VerificationEvent{}}
+	}
+	bundleState.SizeBytes += addedSize
+	if err := bundleState.Save(path); err != nil {
+		return nil, fmt.Errorf("failed to save state: %w", err)
+	}
+
+	return Load(path)
+}
@@ -0,0 +1,98 @@
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+func TestAddFiles(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("UTC+2", 2*60*60)
+	defer func() { time.Local = local }()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.txt": "a"})
+	orig, err := Create(dir, "Grows")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	extra := t.TempDir()
+	writeFiles(t, extra, map[string]string{"b.txt": "bb", "c.txt": "ccc"})
+	b, err := AddFiles(dir, []string{filepath.Join(extra, "b.txt"), filepath.Join(extra, "c.txt")})
+	if err != nil {
+		t.Fatalf("AddFiles failed: %v", err)
+	}
+
+	// The new checksum is the one a fresh bundle of the same files gets
+	fresh := t.TempDir()
+	writeFiles(t, fresh, map[string]string{"a.txt": "a", "b.txt": "bb", "c.txt": "ccc"})
+	want, err := Create(fresh, "Fresh")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if b.Metadata.BundleChecksum != want.Metadata.BundleChecksum {
+		t.Errorf("checksum = %s, want %s", b.Metadata.BundleChecksum, want.Metadata.BundleChecksum)
+	}
+	if b.Metadata.BundleChecksum == orig.Metadata.BundleChecksum {
+		t.Error("checksum unchanged after adding files")
+	}
+	if b.Metadata.Title != "Grows" || !b.Metadata.CreatedAt.Equal(orig.Metadata.CreatedAt) {
+		t.Errorf("metadata = %+v, want title and created_at kept", b.Metadata)
+	}
+	if b.State.SizeBytes != 6 {
+		t.Errorf("size = %d, want 6", b.State.SizeBytes)
+	}
+	if verified, corrupted, err := Verify(dir); err != nil || !verified {
+		t.Errorf("Verify() = %v, %v, %v, want verified", verified, corrupted, err)
+	}
+
+	// Records of added files carry UTC modification times like scanned ones
+	raw, err := os.ReadFile(filepath.Join(dir, ".bundle", "INDEX.json"))
+	if err != nil {
+		t.Fatalf("read INDEX.json: %v", err)
+	}
+	var index struct {
+		Files map[string]struct {
+			ModTime time.Time `json:"mod_time"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(raw, &index); err != nil {
+		t.Fatalf("parse INDEX.json: %v", err)
+	}
+	for _, name := range []string{"b.txt", "c.txt"} {
+		if mt := index.Files[name].ModTime; mt.IsZero() || mt.Location() != time.UTC {
+			t.Errorf("INDEX.json mod_time of %s = %v, want UTC", name, mt)
+		}
+	}
+
+	// Names already in the bundle, missing files and directories are refused
+	for _, files := range [][]string{
+		{filepath.Join(extra, "b.txt")},
+		{filepath.Join(extra, "missing.txt")},
+		{extra},
+	} {
+		if _, err := AddFiles(dir, files); !errors.Is(err, utils.ErrInvalidPath) {
+			t.Errorf("AddFiles(%v) error = %v, want ErrInvalidPath", files, err)
+		}
+	}
+
+	// A bad file among good ones leaves the bundle unchanged
+	writeFiles(t, extra, map[string]string{"d.txt": "d"})
+	if _, err := AddFiles(dir, []string{filepath.Join(extra, "d.txt"), filepath.Join(extra, "missing.txt")}); err == nil {
+		t.Error("AddFiles() with a missing file succeeded, want error")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "d.txt")); !os.IsNotExist(err) {
+		t.Errorf("d.txt copied despite the failure: %v", err)
+	}
+
+	if _, err := AddFiles(t.TempDir(), []string{filepath.Join(extra, "d.txt")}); !errors.Is(err, utils.ErrNotABundle) {
+		t.Errorf("AddFiles() on non-bundle error = %v, want ErrNotABundle", err)
+	}
+}
@@ -0,0 +1,280 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jvzantvoort/bundle/checksum"
+	"github.com/jvzantvoort/bundle/lock"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// gzipMagic starts every gzip stream; archives starting with it are
+// decompressed before reading the tar.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ImportArchiveOptions holds the optional settings for
+// ImportArchiveWithOptions.
+//
+// Fields:
+//   - SkipVerify: do not verify the unpacked bundle
+type ImportArchiveOptions struct {
+	SkipVerify bool
+}
+
+// ImportArchive unpacks a tar or tar.gz bundle archive into destDir and
+// verifies it.
+//
+// It is ImportArchiveWithOptions with the default options.
+//
+// Example:
+//
+//	b, err := bundle.ImportArchive("/tmp/photos.tar.gz", "/data/photos")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("Imported: %s\n", b.Metadata.Title)
+//
+// Parameters:
+//   - archivePath: path to the tar or tar.gz archive
+//   - destDir: directory to unpack into; must not exist or be empty
+//
+// Returns:
+//   - *Bundle: the unpacked bundle with all metadata loaded
+//   - error: see ImportArchiveWithOptions
+func ImportArchive(archivePath, destDir string) (*Bundle, error) {
+	return ImportArchiveWithOptions(archivePath, destDir, ImportArchiveOptions{})
+}
+
+// ImportArchiveWithOptions unpacks a tar or tar.gz bundle archive into
+// destDir, then loads and, unless opts.SkipVerify is set, verifies it.
+//
+// The archive holds the bundle root, either directly (.bundle/META.json at
+// the top) or inside a single top-level directory, which is stripped.
+// Compression is detected from the content, not the file name. Only
+// directories and regular files are unpacked, except stale .bundle/.lock*
+// lock files.
+// Entries with absolute paths or ".." components are rejected before
+// anything is written, and links and device files are rejected, so an
+// archive cannot write outside destDir.
+//
+// Verification recomputes every file checksum and the bundle checksum and
+// fails with utils.ErrCorruptedBundle when either differs from the stored
+// values. The unpacked files are left in place in that case so they can be
+// inspected.
+//
+// Example:
+//
+//	b, err := bundle.ImportArchiveWithOptions("/tmp/photos.tar", "/data/photos",
+//	    bundle.ImportArchiveOptions{SkipVerify: true})
+//
+// Parameters:
+//   - archivePath: path to the tar or tar.gz archive
+//   - destDir: directory to unpack into; must not exist or be empty
+//   - opts: import options
+//
+// Returns:
+//   - *Bundle: the unpacked bundle with all metadata loaded
+//   - error: utils.ErrInvalidPath for a non-empty destDir or an unsafe
+//     entry, utils.ErrNotABundle if the archive holds no bundle,
+//     utils.ErrCorruptedBundle if verification fails, or I/O errors
+func ImportArchiveWithOptions(archivePath, destDir string, opts ImportArchiveOptions) (*Bundle, error) {
+	if err := checkEmptyDir(destDir); err != nil {
+		return nil, err
+	}
+
+	// First pass: check the entry names and find the bundle root
+	var names []string
+	err := readArchive(archivePath, func(hdr *tar.Header, _ io.Reader) error {
+		name, err := entryName(hdr)
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := archiveBundlePrefix(names)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, archivePath)
+	}
+
+	// Second pass: unpack
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return nil, err
+	}
+	err = readArchive(archivePath, func(hdr *tar.Header, r io.Reader) error {
+		return unpackEntry(destDir, prefix, hdr, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := Load(destDir)
+	if err != nil {
+		return nil, err
+	}
+	if opts.SkipVerify {
+		return b, nil
+	}
+
+	report, err := VerifyWithOptions(destDir, VerifyOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if !report.Verified {
+		return nil, fmt.Errorf("%w: %d corrupted files: %v", utils.ErrCorruptedBundle, len(report.Corrupted), report.CorruptedPaths())
+	}
+	sums := make([]string, len(report.Recomputed.Records))
+	for i, record := range report.Recomputed.Records {
+		sums[i] = record.Checksum
+	}
+	if got := checksum.ComputeBundleHash(sums, report.Algorithm); got != b.Metadata.BundleChecksum {
+		return nil, fmt.Errorf("%w: bundle checksum %s does not match META.json %s", utils.ErrCorruptedBundle, got, b.Metadata.BundleChecksum)
+	}
+
+	// Verification updated STATE.json
+	return Load(destDir)
+}
+
+// checkEmptyDir returns an error unless dir does not exist or is an empty
+// directory.
+func checkEmptyDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("%w: destination %s is not empty", utils.ErrInvalidPath, dir)
+	}
+	return nil
+}
+
+// readArchive calls fn for every entry of the tar or tar.gz archive at
+// archivePath; r reads the content of the entry.
+func readArchive(archivePath string, fn func(hdr *tar.Header, r io.Reader) error) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var in io.Reader = bufio.NewReader(file)
+	if magic, err := in.(*bufio.Reader).Peek(len(gzipMagic)); err == nil && bytes.Equal(magic, gzipMagic) {
+		gz, err := gzip.NewReader(in)
+		if err != nil {
+			return fmt.Errorf("failed to decompress %s: %w", archivePath, err)
+		}
+		defer gz.Close()
+		in = gz
+	}
+
+	tr := tar.NewReader(in)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", archivePath, err)
+		}
+		if err := fn(hdr, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// archiveBundlePrefix returns the top-level directory, with trailing
+// slash, that holds the bundle in an archive with the given entry names
+// (as returned by entryName), or "" when the bundle is at the top.
+func archiveBundlePrefix(names []string) (string, error) {
+	for _, name := range names {
+		if name == ".bundle/META.json" {
+			return "", nil
+		}
+	}
+	for _, name := range names {
+		if dir, rest, ok := strings.Cut(name, "/"); ok && rest == ".bundle/META.json" {
+			return dir + "/", nil
+		}
+	}
+	return "", utils.ErrNotABundle
+}
+
+// entryName returns the cleaned, slash-separated name of an archive entry.
+// Absolute names and names with ".." components are rejected.
+func entryName(hdr *tar.Header) (string, error) {
+	name := strings.TrimPrefix(hdr.Name, "./")
+	if path.IsAbs(name) {
+		return "", fmt.Errorf("%w: archive entry with absolute path: %s", utils.ErrInvalidPath, hdr.Name)
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("%w: archive entry outside the bundle: %s", utils.ErrInvalidPath, hdr.Name)
+		}
+	}
+	return path.Clean(name), nil
+}
+
+// unpackEntry writes one archive entry below destDir, with prefix
+// stripped from its name.
+func unpackEntry(destDir, prefix string, hdr *tar.Header, r io.Reader) error {
+	name, err := entryName(hdr)
+	if err != nil {
+		return err
+	}
+	if prefix != "" {
+		if name+"/" == prefix {
+			return nil
+		}
+		if !strings.HasPrefix(name, prefix) {
+			log.Warnf("Skipping archive entry outside the bundle directory: %s", hdr.Name)
+			return nil
+		}
+		name = strings.TrimPrefix(name, prefix)
+	}
+	if name == "." || lock.IsLockFile(name) {
+		return nil
+	}
+	target := filepath.Join(destDir, filepath.FromSlash(name))
+
+	switch hdr.Typeflag {
+	case tar.TypeXGlobalHeader:
+		return nil
+	case tar.TypeDir:
+		return os.MkdirAll(target, 0755)
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, os.FileMode(hdr.Mode)&0777)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, r); err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+		return os.Chtimes(target, hdr.ModTime, hdr.ModTime)
+	default:
+		return fmt.Errorf("%w: unsupported archive entry type %q: %s", utils.ErrInvalidPath, hdr.Typeflag, hdr.Name)
+	}
+}
@@ -0,0 +1,161 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+// writeArchive writes the tree at dir to a tar archive, with every name
+// prefixed by prefix, and returns its path.
+func writeArchive(t *testing.T, dir, prefix string, compress bool) string {
+	t.Helper()
+	archivePath := filepath.Join(t.TempDir(), "bundle.tar")
+	file, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("create archive: %v", err)
+	}
+	defer file.Close()
+
+	var out io.Writer = file
+	if compress {
+		gz := gzip.NewWriter(file)
+		defer gz.Close()
+		out = gz
+	}
+	tw := tar.NewWriter(out)
+	defer tw.Close()
+
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == dir {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = prefix + filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			_, err = tw.Write(data)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("write archive: %v", err)
+	}
+	return archivePath
+}
+
+func TestImportArchive(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "docs"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "docs", "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	created, err := Create(src, "Archived")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		prefix   string
+		compress bool
+	}{
+		{"tar", "", false},
+		{"tar.gz with top-level directory", "archived/", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			archivePath := writeArchive(t, src, tc.prefix, tc.compress)
+			dest := filepath.Join(t.TempDir(), "dest")
+			b, err := ImportArchive(archivePath, dest)
+			if err != nil {
+				t.Fatalf("ImportArchive failed: %v", err)
+			}
+			if b.Metadata.BundleChecksum != created.Metadata.BundleChecksum {
+				t.Errorf("checksum = %s, want %s", b.Metadata.BundleChecksum, created.Metadata.BundleChecksum)
+			}
+			if !b.State.Verified {
+				t.Errorf("imported bundle not marked verified")
+			}
+			if _, err := os.Stat(filepath.Join(dest, "docs", "a.txt")); err != nil {
+				t.Errorf("file not unpacked: %v", err)
+			}
+		})
+	}
+
+	// A non-empty destination is refused
+	if _, err := ImportArchive(writeArchive(t, src, "", false), src); !errors.Is(err, utils.ErrInvalidPath) {
+		t.Errorf("non-empty destination error = %v, want ErrInvalidPath", err)
+	}
+
+	// Tampered content fails verification unless skipped
+	if err := os.WriteFile(filepath.Join(src, "docs", "a.txt"), []byte("b"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	tampered := writeArchive(t, src, "", false)
+	if _, err := ImportArchive(tampered, filepath.Join(t.TempDir(), "dest")); !errors.Is(err, utils.ErrCorruptedBundle) {
+		t.Errorf("tampered archive error = %v, want ErrCorruptedBundle", err)
+	}
+	if _, err := ImportArchiveWithOptions(tampered, filepath.Join(t.TempDir(), "dest"), ImportArchiveOptions{SkipVerify: true}); err != nil {
+		t.Errorf("SkipVerify import failed: %v", err)
+	}
+}
+
+func TestImportArchivePathTraversal(t *testing.T) {
+	src := t.TempDir()
+	if _, err := Create(src, "Evil"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	archivePath := writeArchive(t, src, "", false)
+
+	// Append an entry escaping the destination
+	file, err := os.OpenFile(archivePath, os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	// Overwrite the two zero blocks ending the archive
+	if _, err := file.Seek(-2*512, io.SeekEnd); err != nil {
+		t.Fatalf("seek: %v", err)
+	}
+	tw := tar.NewWriter(file)
+	if err := tw.WriteHeader(&tar.Header{Name: "../escaped.txt", Mode: 0644, Size: 1, Typeflag: tar.TypeReg}); err != nil {
+		t.Fatalf("write header: %v", err)
+	}
+	if _, err := tw.Write([]byte("x")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	file.Close()
+
+	parent := t.TempDir()
+	dest := filepath.Join(parent, "dest")
+	if _, err := ImportArchive(archivePath, dest); !errors.Is(err, utils.ErrInvalidPath) {
+		t.Fatalf("traversal error = %v, want ErrInvalidPath", err)
+	}
+	if _, err := os.Stat(filepath.Join(parent, "escaped.txt")); !os.IsNotExist(err) {
+		t.Errorf("entry written outside the destination: %v", err)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("destination created for a rejected archive: %v", err)
+	}
+}
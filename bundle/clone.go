@@ -0,0 +1,119 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jvzantvoort/bundle/lock"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// Clone copies the bundle at srcPath to destPath and verifies the copy.
+//
+// All files, including the .bundle/ metadata, are copied with their
+// modification times, so the clone keeps the bundle checksum, author and
+// created_at of the original; unlike Create, nothing is recomputed. Lock
+// files held on the original are not copied.
+//
+// destPath must not exist or be an empty directory, and must not lie
+// inside srcPath. The clone is verified at destPath, which records the
+// result in its STATE.json. When the copy or the verification fails, the
+// partial copy is removed again; an existing empty destPath is kept,
+// empty.
+//
+// Example:
+//
+//	clone, err := bundle.Clone("/data/photos", "/backup/photos")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(clone.Metadata.BundleChecksum)
+//
+// Parameters:
+//   - srcPath: path to the bundle to copy
+//   - destPath: directory to copy the bundle to
+//
+// Returns:
+//   - *Bundle: the clone, loaded from destPath
+//   - error: utils.ErrNotABundle if srcPath is not a bundle,
+//     utils.ErrInvalidPath if destPath is not empty or inside srcPath,
+//     utils.ErrCorruptedBundle if the copy does not verify, or I/O errors
+func Clone(srcPath, destPath string) (*Bundle, error) {
+	if !utils.IsBundleDir(srcPath) {
+		return nil, utils.ErrNotABundle
+	}
+
+	// Copying into the source would copy the copy again
+	inside, err := utils.IsWithin(destPath, srcPath)
+	if err != nil {
+		return nil, err
+	}
+	if inside {
+		return nil, fmt.Errorf("%w: destination %s is inside the bundle %s", utils.ErrInvalidPath, destPath, srcPath)
+	}
+
+	existed, err := checkEmptyDest(destPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// removePartial undoes the copy after a failure
+	removePartial := func() {
+		if !existed {
+			if err := os.RemoveAll(destPath); err != nil {
+				log.Warnf("failed to remove partial copy %s: %v", destPath, err)
+			}
+			return
+		}
+		entries, _ := os.ReadDir(destPath)
+		for _, entry := range entries {
+			if err := os.RemoveAll(filepath.Join(destPath, entry.Name())); err != nil {
+				log.Warnf("failed to remove partial copy %s: %v", destPath, err)
+			}
+		}
+	}
+
+	log.Debugf("Cloning bundle %s to %s", srcPath, destPath)
+	stats := &utils.CopyStats{}
+	opts := utils.CopyOptions{
+		Overwrite:      utils.OverwriteAlways,
+		FollowSymlinks: true,
+		PreserveTimes:  true,
+		Skip:           lock.IsLockFile,
+		Stats:          stats,
+	}
+	if err := utils.CopyTree(srcPath, destPath, opts); err != nil {
+		removePartial()
+		return nil, fmt.Errorf("failed to copy bundle: %w", err)
+	}
+	log.Debugf("Bundle copied (%d files)", stats.Copied+stats.Overwritten)
+
+	verified, corrupted, err := Verify(destPath)
+	if err != nil {
+		removePartial()
+		return nil, fmt.Errorf("failed to verify cloned bundle: %w", err)
+	}
+	if !verified {
+		removePartial()
+		return nil, fmt.Errorf("%w: %d corrupted files: %v", utils.ErrCorruptedBundle, len(corrupted), corrupted)
+	}
+	return Load(destPath)
+}
+
+// checkEmptyDest returns whether dir exists, and an error unless it does
+// not exist or is an empty directory.
+func checkEmptyDest(dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if len(entries) > 0 {
+		return true, fmt.Errorf("%w: destination %s is not empty", utils.ErrInvalidPath, dir)
+	}
+	return true, nil
+}
@@ -0,0 +1,86 @@
+package bundle
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jvzantvoort/bundle/lock"
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+func TestClone(t *testing.T) {
+	src := t.TempDir()
+	writeFiles(t, src, map[string]string{"a.txt": "a", "b.txt": "b"})
+	orig, err := Create(src, "Original")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "clone")
+	clone, err := Clone(src, dest)
+	if err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+	if clone.Metadata.BundleChecksum != orig.Metadata.BundleChecksum {
+		t.Errorf("checksum = %s, want %s", clone.Metadata.BundleChecksum, orig.Metadata.BundleChecksum)
+	}
+	if clone.Metadata.Author != orig.Metadata.Author {
+		t.Errorf("author = %q, want %q", clone.Metadata.Author, orig.Metadata.Author)
+	}
+	if !clone.Metadata.CreatedAt.Equal(orig.Metadata.CreatedAt) {
+		t.Errorf("created_at = %v, want %v", clone.Metadata.CreatedAt, orig.Metadata.CreatedAt)
+	}
+	if !clone.State.Verified {
+		t.Error("clone not verified")
+	}
+
+	// Locks held on the source are not copied into the clone
+	reader, err := lock.AcquireSharedLock(src)
+	if err != nil {
+		t.Fatalf("AcquireSharedLock failed: %v", err)
+	}
+	locked := filepath.Join(t.TempDir(), "locked")
+	if _, err := Clone(src, locked); err != nil {
+		t.Errorf("Clone() of a bundle with a reader failed: %v", err)
+	}
+	reader.Release()
+	if matches, _ := filepath.Glob(filepath.Join(locked, ".bundle", ".lock*")); len(matches) != 0 {
+		t.Errorf("lock files copied into the clone: %v", matches)
+	}
+
+	// A non-empty destination is refused and left alone
+	if _, err := Clone(src, dest); !errors.Is(err, utils.ErrInvalidPath) {
+		t.Errorf("Clone() to non-empty dest error = %v, want ErrInvalidPath", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "a.txt")); err != nil {
+		t.Errorf("existing clone damaged: %v", err)
+	}
+
+	// A destination inside the source is refused before anything is copied
+	for _, inside := range []string{filepath.Join(src, "clone"), filepath.Join(src, "a", "b")} {
+		if _, err := Clone(src, inside); !errors.Is(err, utils.ErrInvalidPath) {
+			t.Errorf("Clone() into %s error = %v, want ErrInvalidPath", inside, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(src, "clone")); !os.IsNotExist(err) {
+		t.Errorf("Clone() into the source created it: %v", err)
+	}
+
+	if _, err := Clone(t.TempDir(), filepath.Join(t.TempDir(), "x")); !errors.Is(err, utils.ErrNotABundle) {
+		t.Errorf("Clone() of non-bundle error = %v, want ErrNotABundle", err)
+	}
+
+	// A corrupted source does not verify and leaves no partial copy
+	if err := os.WriteFile(filepath.Join(src, "a.txt"), []byte("bitrot"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	empty := t.TempDir()
+	if _, err := Clone(src, empty); !errors.Is(err, utils.ErrCorruptedBundle) {
+		t.Errorf("Clone() of corrupted bundle error = %v, want ErrCorruptedBundle", err)
+	}
+	if entries, _ := os.ReadDir(empty); len(entries) != 0 {
+		t.Errorf("partial copy left behind: %v", entries)
+	}
+}
@@ -0,0 +1,138 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jvzantvoort/bundle/checksum"
+	"github.com/jvzantvoort/bundle/metadata"
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+// CompareResult describes how a set of files differs from a bundle manifest.
+//
+// All paths are relative to the bundle root (or compared directory) and
+// sorted alphabetically.
+//
+// Fields:
+//   - Added: files present in the directory but not in the manifest
+//   - Removed: files in the manifest but missing from the directory
+//   - Modified: files present in both with different checksums
+type CompareResult struct {
+	Added    []string `json:"added"`
+	Removed  []string `json:"removed"`
+	Modified []string `json:"modified"`
+}
+
+// Identical reports whether no differences were found.
+func (r *CompareResult) Identical() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Modified) == 0
+}
+
+// Compare checks a live directory against a bundle's manifest.
+//
+// It hashes every file in dir (excluding .bundle/) and compares the result
+// with the checksums stored in the bundle's manifest. The bundle itself
+// is not modified; in particular STATE.json is left untouched.
+//
+// For a bundle created with a modification time window, files in dir that
+// are not in the manifest are only reported as added when their
+// modification time falls inside the recorded window. Paths matching the
+// exclude patterns recorded in META.json are skipped in dir.
+//
+// Example:
+//
+//	result, err := bundle.Compare("/path/to/bundle", "/restore/target")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if !result.Identical() {
+//	    fmt.Printf("Modified: %v\n", result.Modified)
+//	}
+//
+// Parameters:
+//   - bundlePath: path to the bundle providing the manifest
+//   - dir: path to the directory to compare
+//
+// Returns:
+//   - *CompareResult: added, removed and modified files
+//   - error: if the manifest cannot be loaded or dir cannot be scanned;
+//     utils.ErrInvalidPath if dir is not a directory
+func Compare(bundlePath, dir string) (*CompareResult, error) {
+	manifest, err := loadManifest(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if info, err := os.Stat(dir); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%w: not a directory: %s", utils.ErrInvalidPath, dir)
+	}
+
+	current := &checksum.ChecksumFile{Algorithm: manifest.Algorithm, Exclude: manifest.Exclude}
+	if err := current.Compute(dir); err != nil {
+		return nil, fmt.Errorf("failed to compute checksums: %w", err)
+	}
+
+	result := compareRecords(manifest.Records, current.Records)
+	if meta, err := metadata.Load(bundlePath); err == nil {
+		after, before := meta.ModTimeFilter()
+		result.Added = filterAdded(dir, result.Added, checksum.ModTimeFilter{After: after, Before: before})
+	}
+	return result, nil
+}
+
+// filterAdded drops the added paths whose modification time is outside
+// filter; those were never candidates for the bundle.
+func filterAdded(dir string, added []string, filter checksum.ModTimeFilter) []string {
+	if filter.IsZero() {
+		return added
+	}
+	kept := []string{}
+	for _, relPath := range added {
+		info, err := os.Stat(filepath.Join(dir, relPath))
+		if err != nil || filter.Match(info.ModTime()) {
+			kept = append(kept, relPath)
+		}
+	}
+	return kept
+}
+
+// compareRecords diffs two record sets by relative path.
+func compareRecords(from, to []checksum.ChecksumRecord) *CompareResult {
+	result := &CompareResult{
+		Added:    []string{},
+		Removed:  []string{},
+		Modified: []string{},
+	}
+
+	fromSums := make(map[string]string, len(from))
+	for _, record := range from {
+		fromSums[record.FilePath] = record.Checksum
+	}
+
+	seen := make(map[string]struct{}, len(to))
+	for _, record := range to {
+		seen[record.FilePath] = struct{}{}
+		sum, ok := fromSums[record.FilePath]
+		if !ok {
+			result.Added = append(result.Added, record.FilePath)
+		} else if sum != record.Checksum {
+			result.Modified = append(result.Modified, record.FilePath)
+		}
+	}
+
+	for _, record := range from {
+		if _, ok := seen[record.FilePath]; !ok {
+			result.Removed = append(result.Removed, record.FilePath)
+		}
+	}
+
+	sort.Strings(result.Added)
+	sort.Strings(result.Removed)
+	sort.Strings(result.Modified)
+	return result
+}
@@ -0,0 +1,62 @@
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+// ErrAlgorithmMismatch is returned by Diff for bundles hashed with
+// different checksum algorithms. It is utils.ErrAlgorithmMismatch, so it
+// maps to exit code 1 and the algorithm_mismatch error code.
+var ErrAlgorithmMismatch = utils.ErrAlgorithmMismatch
+
+// DiffResult describes how bundle B differs from bundle A.
+//
+// Added files are only in B, removed files only in A, and modified files
+// are in both with a different checksum. Paths are relative to the bundle
+// roots and sorted alphabetically.
+type DiffResult struct {
+	CompareResult
+}
+
+// Diff compares the manifests of two bundles.
+//
+// The records of both SHA256SUM.txt files are matched by relative path, so
+// no file is hashed and neither bundle needs its content on disk; this
+// makes it cheap to see what changed between successive snapshots. Both
+// bundles must use the same checksum algorithm.
+//
+// Example:
+//
+//	diff, err := bundle.Diff("/snapshots/2024-01", "/snapshots/2024-02")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("%d added, %d removed, %d modified\n",
+//	    len(diff.Added), len(diff.Removed), len(diff.Modified))
+//
+// Parameters:
+//   - pathA: path to the older bundle
+//   - pathB: path to the newer bundle
+//
+// Returns:
+//   - *DiffResult: files added, removed and modified from A to B
+//   - error: if a manifest cannot be loaded, or ErrAlgorithmMismatch if the
+//     algorithms differ
+func Diff(pathA, pathB string) (*DiffResult, error) {
+	manifestA, err := loadManifest(pathA)
+	if err != nil {
+		return nil, err
+	}
+	manifestB, err := loadManifest(pathB)
+	if err != nil {
+		return nil, err
+	}
+	if manifestA.Algorithm != manifestB.Algorithm {
+		return nil, fmt.Errorf("%w: %s and %s", ErrAlgorithmMismatch,
+			manifestA.Algorithm, manifestB.Algorithm)
+	}
+
+	return &DiffResult{*compareRecords(manifestA.Records, manifestB.Records)}, nil
+}
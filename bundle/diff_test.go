@@ -0,0 +1,73 @@
+package bundle
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jvzantvoort/bundle/checksum"
+)
+
+// writeFiles creates dir/name with the given content for each entry.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := t.TempDir()
+	writeFiles(t, a, map[string]string{"a.txt": "a", "b.txt": "b", "same.txt": "same"})
+	if _, err := Create(a, "January"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	b := t.TempDir()
+	writeFiles(t, b, map[string]string{"a.txt": "changed", "c.txt": "c", "same.txt": "same"})
+	if _, err := Create(b, "February"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// The manifests are compared, not the files on disk
+	if err := os.Remove(filepath.Join(b, "c.txt")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	diff, err := Diff(a, b)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	want := CompareResult{
+		Added:    []string{"c.txt"},
+		Removed:  []string{"b.txt"},
+		Modified: []string{"a.txt"},
+	}
+	if !reflect.DeepEqual(diff.CompareResult, want) {
+		t.Errorf("Diff() = %+v, want %+v", diff.CompareResult, want)
+	}
+
+	same, err := Diff(a, a)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if !same.Identical() {
+		t.Errorf("Diff(a, a) = %+v, want identical", same)
+	}
+
+	other := t.TempDir()
+	writeFiles(t, other, map[string]string{"a.txt": "a"})
+	if _, err := CreateWithOptions(other, CreateOptions{Title: "SHA512", HashAlgorithm: checksum.SHA512}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := Diff(a, other); !errors.Is(err, ErrAlgorithmMismatch) {
+		t.Errorf("Diff() across algorithms error = %v, want ErrAlgorithmMismatch", err)
+	}
+
+	if _, err := Diff(a, t.TempDir()); err == nil {
+		t.Error("Diff() with a non-bundle succeeded, want error")
+	}
+}
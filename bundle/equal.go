@@ -0,0 +1,80 @@
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/jvzantvoort/bundle/metadata"
+)
+
+// EqualResult is the result of Equal.
+//
+// Fields:
+//   - Equal: true if both bundles have the same content
+//   - ChecksumA, ChecksumB: the bundle checksums from META.json
+//   - Reason: why the bundles are not equal; empty when they are
+type EqualResult struct {
+	Equal     bool
+	ChecksumA string
+	ChecksumB string
+	Reason    string
+}
+
+// Equal reports whether two bundles have the same content.
+//
+// Bundles are content-addressable: the bundle checksum is computed from
+// the checksums of all files, so two bundles are identical exactly when
+// their checksums match. Only META.json is read; titles, tags and other
+// metadata do not take part.
+//
+// With deep set, both bundles are first verified on disk, as with Verify,
+// so a corrupted bundle whose metadata still matches is reported as not
+// equal. This records the verification in each bundle's STATE.json.
+//
+// Example:
+//
+//	result, err := bundle.Equal("/backup/photos", "/archive/photos", false)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if !result.Equal {
+//	    fmt.Println(result.Reason)
+//	}
+//
+// Parameters:
+//   - pathA, pathB: paths to the bundles to compare
+//   - deep: verify both bundles before comparing
+//
+// Returns:
+//   - *EqualResult: the checksums and whether they match
+//   - error: if metadata cannot be loaded or verification fails to run
+func Equal(pathA, pathB string, deep bool) (*EqualResult, error) {
+	metaA, err := metadata.Load(pathA)
+	if err != nil {
+		return nil, err
+	}
+	metaB, err := metadata.Load(pathB)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EqualResult{ChecksumA: metaA.BundleChecksum, ChecksumB: metaB.BundleChecksum}
+	if deep {
+		for _, path := range []string{pathA, pathB} {
+			report, err := VerifyWithOptions(path, VerifyOptions{})
+			if err != nil {
+				return nil, err
+			}
+			if !report.Verified {
+				result.Reason = fmt.Sprintf("%s failed verification: %d corrupted file(s)", path, len(report.Corrupted))
+				return result, nil
+			}
+		}
+	}
+
+	if result.ChecksumA != result.ChecksumB {
+		result.Reason = "bundle checksums differ"
+		return result, nil
+	}
+	result.Equal = true
+	return result, nil
+}
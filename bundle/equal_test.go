@@ -0,0 +1,62 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	files := map[string]string{"a.txt": "a", "b.txt": "b"}
+	a := t.TempDir()
+	writeFiles(t, a, files)
+	if _, err := Create(a, "Original"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	// Same content under another title
+	b := t.TempDir()
+	writeFiles(t, b, files)
+	if _, err := Create(b, "Copy"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	other := t.TempDir()
+	writeFiles(t, other, map[string]string{"a.txt": "changed"})
+	if _, err := Create(other, "Other"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	result, err := Equal(a, b, false)
+	if err != nil {
+		t.Fatalf("Equal failed: %v", err)
+	}
+	if !result.Equal || result.ChecksumA != result.ChecksumB || result.Reason != "" {
+		t.Errorf("Equal(a, b) = %+v, want equal", result)
+	}
+
+	result, err = Equal(a, other, false)
+	if err != nil {
+		t.Fatalf("Equal failed: %v", err)
+	}
+	if result.Equal || result.Reason == "" {
+		t.Errorf("Equal(a, other) = %+v, want not equal with a reason", result)
+	}
+
+	// A corrupted copy still has matching metadata; only --deep notices
+	if err := os.WriteFile(filepath.Join(b, "b.txt"), []byte("bitrot"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if result, err = Equal(a, b, false); err != nil || !result.Equal {
+		t.Errorf("Equal(a, b) = %+v, %v, want equal", result, err)
+	}
+	result, err = Equal(a, b, true)
+	if err != nil {
+		t.Fatalf("Equal deep failed: %v", err)
+	}
+	if result.Equal || result.Reason == "" {
+		t.Errorf("Equal(a, b, deep) = %+v, want not equal with a reason", result)
+	}
+
+	if _, err := Equal(a, t.TempDir(), false); err == nil {
+		t.Error("Equal() with a non-bundle succeeded, want error")
+	}
+}
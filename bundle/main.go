@@ -28,10 +28,13 @@
 package bundle
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/jvzantvoort/bundle/checksum"
@@ -39,6 +42,7 @@ import (
 	"github.com/jvzantvoort/bundle/metadata"
 	"github.com/jvzantvoort/bundle/state"
 	"github.com/jvzantvoort/bundle/tag"
+	"github.com/jvzantvoort/bundle/utils"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -62,7 +66,53 @@ type Bundle struct {
 	Metadata *metadata.Metadata     // Loaded from META.json
 	State    *state.State           // Loaded from STATE.json
 	Tags     *tag.Tags              // Loaded from TAGS.txt
-	Files    *checksum.ChecksumFile // Loaded from SHA256SUM.txt
+	Files    *checksum.ChecksumFile // Loaded from the SHA256SUM (or SHA512SUM) manifest
+}
+
+// CreateOptions holds the optional settings for CreateWithOptions.
+//
+// Fields:
+//   - Title: human-readable bundle title
+//   - Description: free-text notes, e.g. provenance; with Force the
+//     existing description is kept when empty
+//   - Author: recorded as the bundle author; the current OS user when
+//     empty, e.g. to name the person behind a CI or shared account
+//   - Strict: abort on the first unreadable path instead of skipping it
+//   - CompressManifest: store the manifest gzip-compressed (*.gz)
+//   - ManifestFormat: manifest serialization, text when empty
+//   - ScanOrder: order files are hashed and recorded in, walk order when empty
+//   - ModTimeFilter: only include files modified in this window (recorded
+//     in META.json); all files when zero
+//   - Exclude: gitignore-style patterns of paths to leave out (recorded in
+//     META.json so verify skips them too)
+//   - Force: recreate the bundle if path is already a bundle; files whose
+//     size and modification time are unchanged keep their checksum
+//   - ResetMetadata: with Force, discard the existing descriptive metadata
+//   - HashAlgorithm: algorithm of all checksums (recorded in META.json),
+//     SHA256 when empty
+//   - Progress: called after each file is hashed; may be nil
+//   - DryRun: scan and hash the files but write nothing: no lock, no
+//     .bundle/ directory, no metadata; the returned bundle shows what
+//     would be created
+//   - CreatedAt: creation time recorded in META.json instead of now, e.g.
+//     for reproducible bundles; stored in UTC to the second and may not be
+//     more than FutureTimestampTolerance ahead
+type CreateOptions struct {
+	Title            string
+	Description      string
+	Author           string
+	Strict           bool
+	CompressManifest bool
+	ManifestFormat   checksum.ManifestFormat
+	ScanOrder        checksum.ScanOrder
+	ModTimeFilter    checksum.ModTimeFilter
+	Exclude          []string
+	Force            bool
+	ResetMetadata    bool
+	HashAlgorithm    checksum.HashAlgorithm
+	Progress         checksum.ProgressFunc
+	DryRun           bool
+	CreatedAt        time.Time
 }
 
 // Create initializes a new bundle from a directory.
@@ -91,30 +141,138 @@ type Bundle struct {
 //   - *Bundle: the created bundle with all metadata loaded
 //   - error: lock errors, I/O errors, or checksum computation errors
 func Create(path string, title string) (*Bundle, error) {
+	return CreateWithOptions(path, CreateOptions{Title: title})
+}
+
+// CreateWithOptions initializes a new bundle from a directory using opts.
+//
+// Unless opts.Strict is set, files and directories that cannot be read are
+// skipped and reported in the returned bundle's Files.Errors rather than
+// failing the whole create.
+//
+// If path is already a bundle, CreateWithOptions fails with
+// utils.ErrAlreadyABundle unless opts.Force is set. A forced create only
+// recomputes what is derived from the content. The descriptive metadata
+// is carried over unless opts.ResetMetadata is also set: tags, the
+// original creation time, known replicas, the description, annotations,
+// and the title when opts.Title is empty.
+//
+// Example:
+//
+//	b, err := bundle.CreateWithOptions("/path/to/photos", bundle.CreateOptions{
+//	    Title: "Vacation 2024",
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, e := range b.Files.Errors {
+//	    fmt.Printf("skipped: %v\n", e)
+//	}
+//
+// Parameters:
+//   - path: absolute or relative path to the directory to bundle
+//   - opts: create options
+//
+// Returns:
+//   - *Bundle: the created bundle with all metadata loaded
+//   - error: lock errors, I/O errors, or checksum computation errors
+func CreateWithOptions(path string, opts CreateOptions) (*Bundle, error) {
+	title := opts.Title
 	log.Debugf("Creating bundle at path: %s with title: %s", path, title)
 	defer log.Debugf("Bundle creation completed for path: %s", path)
 	
-	// Acquire lock
-	bundleLock, err := lock.AcquireLock(path)
-	if err != nil {
-		return nil, err
+	if opts.CreatedAt.After(time.Now().Add(FutureTimestampTolerance)) {
+		return nil, fmt.Errorf("created_at is in the future: %s", opts.CreatedAt.UTC().Format(time.RFC3339))
 	}
-	defer func() {
-		if err := bundleLock.Release(); err != nil {
-			log.Errorf("failed to release lock: %v", err)
+
+	if opts.DryRun {
+		// Nothing is written, but the scan still needs a directory
+		if info, err := os.Stat(path); err != nil {
+			return nil, err
+		} else if !info.IsDir() {
+			return nil, fmt.Errorf("%w: not a directory: %s", utils.ErrInvalidPath, path)
+		}
+	} else {
+		// Fail before hashing if the metadata could not be saved afterwards
+		writeDir := path
+		if utils.IsBundleDir(path) {
+			writeDir = filepath.Join(path, ".bundle")
+		}
+		if err := utils.CheckWritable(writeDir); err != nil {
+			return nil, err
 		}
-	}()
 
-	// Create .bundle directory
-	bundleDir := filepath.Join(path, ".bundle")
-	if err := os.MkdirAll(bundleDir, 0755); err != nil {
-		return nil, err
+		// Acquire lock
+		bundleLock, err := lock.AcquireLockWait(path, lock.WaitTimeout)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := bundleLock.Release(); err != nil {
+				log.Errorf("failed to release lock: %v", err)
+			}
+		}()
+
+		// Create .bundle directory
+		bundleDir := filepath.Join(path, ".bundle")
+		if err := os.MkdirAll(bundleDir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	// Refuse to silently overwrite an existing bundle; checked under the
+	// lock so a concurrent create cannot slip in between
+	var previous *Bundle
+	if _, err := os.Stat(filepath.Join(path, ".bundle", "META.json")); err == nil {
+		if !opts.Force {
+			return nil, fmt.Errorf("%w: %s", utils.ErrAlreadyABundle, path)
+		}
+		if !opts.ResetMetadata {
+			previous = loadPrevious(path)
+		}
+	}
+	if title == "" && previous != nil && previous.Metadata != nil {
+		title = previous.Metadata.Title
 	}
 
 	// Scan and compute checksums
-	files := &checksum.ChecksumFile{}
-	if err := files.Compute(path); err != nil {
-		return nil, fmt.Errorf("failed to compute checksums: %w", err)
+	format := opts.ManifestFormat
+	if format == "" {
+		format = checksum.FormatText
+	}
+	algo := opts.HashAlgorithm
+	if algo == "" {
+		algo = checksum.SHA256
+	}
+	files := &checksum.ChecksumFile{
+		Compress:  opts.CompressManifest,
+		Format:    format,
+		Order:     opts.ScanOrder,
+		Filter:    opts.ModTimeFilter,
+		Exclude:   opts.Exclude,
+		Algorithm: algo,
+	}
+	// A forced create of an existing bundle only rehashes changed files
+	var computeErr error
+	switch {
+	case opts.Force && opts.Strict:
+		var rehashed []string
+		rehashed, computeErr = files.UpdateWithProgress(path, opts.Progress)
+		log.Debugf("Rehashed %d of %d files", len(rehashed), len(files.Records))
+	case opts.Force:
+		var rehashed []string
+		rehashed, computeErr = files.UpdateTolerantWithProgress(path, opts.Progress)
+		log.Debugf("Rehashed %d of %d files", len(rehashed), len(files.Records))
+	case opts.Strict:
+		computeErr = files.ComputeWithProgress(path, opts.Progress)
+	default:
+		computeErr = files.ComputeTolerantWithProgress(path, opts.Progress)
+	}
+	if computeErr != nil {
+		return nil, fmt.Errorf("failed to compute checksums: %w", computeErr)
+	}
+	for _, e := range files.Errors {
+		log.Warnf("Skipping unreadable path: %v", e)
 	}
 
 	// Compute bundle checksum - pre-allocate slice for better performance
@@ -122,22 +280,47 @@ func Create(path string, title string) (*Bundle, error) {
 	for i, record := range files.Records {
 		checksums[i] = record.Checksum
 	}
-	bundleChecksum := checksum.ComputeBundleChecksum(checksums)
+	bundleChecksum := checksum.ComputeBundleHash(checksums, algo)
 
-	// Get author from system user
-	currentUser, _ := user.Current()
-	author := "unknown"
-	if currentUser != nil {
-		author = currentUser.Username
+	// Get author from the options, or else from system user
+	author := strings.TrimSpace(opts.Author)
+	if author == "" {
+		author = "unknown"
+		if currentUser, _ := user.Current(); currentUser != nil {
+			author = currentUser.Username
+		}
+	}
+
+	createdAt := time.Now()
+	if !opts.CreatedAt.IsZero() {
+		createdAt = opts.CreatedAt.UTC().Truncate(time.Second)
+	} else if previous != nil && previous.Metadata != nil {
+		createdAt = previous.Metadata.CreatedAt
 	}
 
 	// Create metadata
 	meta := &metadata.Metadata{
 		Title:          title,
-		CreatedAt:      time.Now(),
+		CreatedAt:      createdAt,
 		BundleChecksum: bundleChecksum,
 		Author:         author,
 		Version:        1,
+		ManifestFormat: string(format),
+		HashAlgorithm:  string(algo),
+		Exclude:        opts.Exclude,
+	}
+	if after := opts.ModTimeFilter.After; !after.IsZero() {
+		meta.ModifiedAfter = &after
+	}
+	if before := opts.ModTimeFilter.Before; !before.IsZero() {
+		meta.ModifiedBefore = &before
+	}
+	if previous != nil && previous.Metadata != nil {
+		meta.Description = previous.Metadata.Description
+		meta.Annotations = previous.Metadata.Annotations
+	}
+	if description := strings.TrimSpace(opts.Description); description != "" {
+		meta.Description = description
 	}
 
 	// Create state with size already computed during checksum scan
@@ -146,10 +329,32 @@ func Create(path string, title string) (*Bundle, error) {
 		LastChecked: time.Now(),
 		Replicas:    []string{},
 		SizeBytes:   files.TotalSize,
+		History:     []state.VerificationEvent{},
+	}
+	if previous != nil && previous.State != nil && previous.State.Replicas != nil {
+		bundleState.Replicas = previous.State.Replicas
+	}
+	if previous != nil && previous.State != nil {
+		bundleState.History = previous.State.History
 	}
 
-	// Create empty tags
+	// Create empty tags, or keep those of the bundle being recreated
 	bundleTags := &tag.Tags{Tags: []string{}}
+	if previous != nil && previous.Tags != nil {
+		bundleTags = previous.Tags
+	}
+
+	result := &Bundle{
+		Path:     path,
+		Metadata: meta,
+		State:    bundleState,
+		Tags:     bundleTags,
+		Files:    files,
+	}
+	if opts.DryRun {
+		log.Debugf("Dry run, not saving bundle %s", bundleChecksum)
+		return result, nil
+	}
 
 	// Save all metadata
 	if err := meta.Save(path); err != nil {
@@ -165,20 +370,15 @@ func Create(path string, title string) (*Bundle, error) {
 		return nil, fmt.Errorf("failed to save tags: %w", err)
 	}
 
-	return &Bundle{
-		Path:     path,
-		Metadata: meta,
-		State:    bundleState,
-		Tags:     bundleTags,
-		Files:    files,
-	}, nil
+	return result, nil
 }
 
 // Verify checks bundle integrity by recomputing checksums.
 //
 // It recomputes SHA256 checksums for all files and compares them against the
 // stored checksums in .bundle/SHA256SUM.txt. Updates the bundle state with
-// verification results and timestamp.
+// verification results and timestamp, holding the bundle lock so that
+// concurrent verifications cannot interleave their STATE.json writes.
 //
 // Example:
 //
@@ -198,18 +398,130 @@ func Create(path string, title string) (*Bundle, error) {
 // Returns:
 //   - bool: true if all checksums match, false if any files are corrupted
 //   - []string: list of relative paths to corrupted or missing files
-//   - error: I/O errors or missing bundle metadata
+//   - error: utils.ErrBundleLocked if another process holds the lock, I/O
+//     errors or missing bundle metadata
 func Verify(path string) (bool, []string, error) {
-	// Load checksums
-	files := &checksum.ChecksumFile{}
-	if err := files.Load(path); err != nil {
+	report, err := VerifyWithOptions(path, VerifyOptions{})
+	if err != nil {
 		return false, nil, err
 	}
+	return report.Verified, report.CorruptedPaths(), nil
+}
 
-	// Verify
-	corrupted, err := files.Verify(path)
+// VerifyOptions holds the optional settings for VerifyWithOptions.
+//
+// Fields:
+//   - Resume: skip files already checked by an interrupted earlier run
+//   - Strict: files on disk that are not in the manifest make the bundle
+//     invalid, as they may indicate tampering
+//   - Context: stops the verification when cancelled, after saving the
+//     progress; nil means context.Background()
+type VerifyOptions struct {
+	Resume  bool
+	Strict  bool
+	Context context.Context
+}
+
+// VerifyReport is the detailed result of VerifyWithOptions.
+//
+// Fields:
+//   - Verified: true if all checksums match
+//   - FilesChecked: number of files in the manifest
+//   - Corrupted: one entry per corrupted or missing file, with expected
+//     and actual sizes
+//   - Recomputed: manifest of the tracked files as found on disk, missing
+//     files left out; untracked files are listed in Extra
+//   - Algorithm: hash algorithm the checksums were computed with
+//   - Missing, Mismatched, Extra: files missing from disk, with a different
+//     checksum, and present on disk but not in the manifest; extra files
+//     only make the bundle invalid with VerifyOptions.Strict
+type VerifyReport struct {
+	Verified     bool
+	FilesChecked int
+	Corrupted    []checksum.Corruption
+	Recomputed   *checksum.ChecksumFile
+	Algorithm    checksum.HashAlgorithm
+	Missing      []string
+	Mismatched   []string
+	Extra        []string
+}
+
+// CorruptedPaths returns the relative paths of the corrupted files.
+func (r *VerifyReport) CorruptedPaths() []string {
+	paths := make([]string, len(r.Corrupted))
+	for i, c := range r.Corrupted {
+		paths[i] = c.Path
+	}
+	return paths
+}
+
+// VerifyWithOptions checks bundle integrity using opts.
+//
+// Progress is saved to .bundle/VERIFY_PROGRESS.json while the verification
+// runs and removed once the result is recorded in STATE.json. With opts.Resume set, files recorded
+// there by an interrupted run are not hashed again, provided the manifest
+// and the recorded files are unchanged; otherwise verification starts over.
+//
+// Example:
+//
+//	report, err := bundle.VerifyWithOptions("/path/to/bundle",
+//	    bundle.VerifyOptions{Resume: true})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, c := range report.Corrupted {
+//	    fmt.Printf("%s: %s\n", c.Path, c.Reason)
+//	}
+//
+// Parameters:
+//   - path: absolute or relative path to the bundle directory
+//   - opts: verification options
+//
+// Returns:
+//   - *VerifyReport: verification result with per-file details
+//   - error: utils.ErrBundleLocked if another process holds the lock past
+//     lock.WaitTimeout, the error of opts.Context when cancelled, I/O
+//     errors or missing bundle metadata
+func VerifyWithOptions(path string, opts VerifyOptions) (*VerifyReport, error) {
+	// Load checksums
+	files, err := loadManifest(path)
 	if err != nil {
-		return false, nil, err
+		return nil, err
+	}
+
+	// Verification writes STATE.json and its progress file. A bundle on
+	// read-only storage can still be checked, just without the lock.
+	bundleLock, err := lock.AcquireLockWait(path, lock.WaitTimeout)
+	if errors.Is(err, utils.ErrBundleLocked) {
+		return nil, err
+	}
+	if err != nil {
+		log.Debugf("Verifying without lock: %v", err)
+	} else {
+		defer func() {
+			if err := bundleLock.Release(); err != nil {
+				log.Errorf("failed to release lock: %v", err)
+			}
+		}()
+	}
+
+	progress := checksum.NewVerifyProgress(path, files)
+	if opts.Resume {
+		progress = checksum.LoadVerifyProgress(path, files)
+		if n := len(progress.Files); n > 0 {
+			log.Infof("Resuming verification, %d of %d files already checked", n, len(files.Records))
+		}
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// Verify; the progress is saved when this stops early or fails
+	result, err := files.VerifyDetailedContext(ctx, path, progress)
+	if err != nil {
+		return nil, err
 	}
 
 	// Update state
@@ -219,20 +531,67 @@ func Verify(path string) (bool, []string, error) {
 		bundleState = &state.State{}
 	}
 
-	verified := len(corrupted) == 0
-	bundleState.MarkVerified(verified, time.Now())
+	corrupted := result.Corrupted
+	verified := result.OK() && (!opts.Strict || len(result.Extra) == 0)
+	bundleState.RecordVerification(verified, len(corrupted), time.Now())
 	if err := bundleState.Save(path); err != nil {
 		log.Warnf("failed to save verification state: %v", err)
 	}
+	if err := progress.Remove(); err != nil {
+		log.Warnf("failed to remove verification progress: %v", err)
+	}
 
-	return verified, corrupted, nil
+	return &VerifyReport{
+		Verified:     verified,
+		FilesChecked: len(files.Records),
+		Corrupted:    corrupted,
+		Recomputed:   files.Recomputed(corrupted),
+		Algorithm:    files.Algorithm,
+		Missing:      result.Missing,
+		Mismatched:   result.Mismatched,
+		Extra:        result.Extra,
+	}, nil
+}
+
+// VerifyFile checks the integrity of a single file in the bundle.
+//
+// Only that file is hashed, so it is much faster than VerifyWithOptions on
+// a large bundle. The bundle verification state is not updated, since the
+// rest of the bundle was not checked.
+//
+// Example:
+//
+//	ok, err := bundle.VerifyFile("/path/to/bundle", "docs/report.pdf")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if !ok {
+//	    fmt.Println("docs/report.pdf is corrupted")
+//	}
+//
+// Parameters:
+//   - path: absolute or relative path to the bundle directory
+//   - relPath: path of the file relative to the bundle root
+//
+// Returns:
+//   - bool: true if the checksum matches
+//   - error: utils.ErrFileNotTracked if relPath is not in the manifest, I/O
+//     errors, or missing bundle metadata
+func VerifyFile(path, relPath string) (bool, error) {
+	files, err := loadManifest(path)
+	if err != nil {
+		return false, err
+	}
+	return files.VerifyFile(path, relPath)
 }
 
 // Load reads all bundle metadata from disk.
 //
 // It loads metadata, state, tags, and checksums from the .bundle/ directory.
 // Returns an error if the directory is not a bundle (missing .bundle/) or if
-// any required metadata files cannot be read.
+// any required metadata files cannot be read. The metadata is validated
+// (see metadata.Metadata.Validate); use LoadUnchecked to load a bundle
+// whose META.json is invalid, e.g. to repair it.
 //
 // Example:
 //
@@ -250,12 +609,44 @@ func Verify(path string) (bool, []string, error) {
 //
 // Returns:
 //   - *Bundle: the loaded bundle with all metadata
-//   - error: if path is not a bundle or metadata files cannot be read
+//   - error: if path is not a bundle or metadata files cannot be read;
+//     utils.ErrIncompleteBundle, naming the invalid field, if META.json
+//     fails validation
 func Load(path string) (*Bundle, error) {
+	return load(path, true)
+}
+
+// LoadUnchecked is like Load but does not validate the metadata.
+//
+// It is meant for tooling that inspects or repairs damaged bundles; other
+// callers should use Load so invalid metadata is reported up front.
+//
+// Example:
+//
+//	b, err := bundle.LoadUnchecked("/path/to/bundle")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if err := b.Metadata.Validate(); err != nil {
+//	    fmt.Printf("Needs repair: %v\n", err)
+//	}
+//
+// Parameters:
+//   - path: absolute or relative path to the bundle directory
+//
+// Returns:
+//   - *Bundle: the loaded bundle with all metadata
+//   - error: if path is not a bundle or metadata files cannot be read
+func LoadUnchecked(path string) (*Bundle, error) {
+	return load(path, false)
+}
+
+// load implements Load and LoadUnchecked.
+func load(path string, validate bool) (*Bundle, error) {
 	// Check if .bundle exists
 	bundleDir := filepath.Join(path, ".bundle")
 	if _, err := os.Stat(bundleDir); os.IsNotExist(err) {
-		return nil, fmt.Errorf("directory is not a bundle (missing .bundle/)")
+		return nil, utils.ErrNotABundle
 	}
 
 	// Load all components
@@ -263,6 +654,11 @@ func Load(path string) (*Bundle, error) {
 	if err != nil {
 		return nil, err
 	}
+	if validate {
+		if err := meta.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: META.json: %w", utils.ErrIncompleteBundle, err)
+		}
+	}
 
 	bundleState, err := state.Load(path)
 	if err != nil {
@@ -274,7 +670,10 @@ func Load(path string) (*Bundle, error) {
 		return nil, err
 	}
 
-	files := &checksum.ChecksumFile{}
+	files := &checksum.ChecksumFile{
+		Format:    checksum.ManifestFormat(meta.ManifestFormat),
+		Algorithm: checksum.HashAlgorithm(meta.HashAlgorithm),
+	}
 	if err := files.Load(path); err != nil {
 		return nil, err
 	}
@@ -287,3 +686,48 @@ func Load(path string) (*Bundle, error) {
 		Files:    files,
 	}, nil
 }
+
+// loadManifest loads the checksum manifest of the bundle at path.
+//
+// The manifest format and hash algorithm recorded in META.json select the
+// reader; bundles without them (or without readable metadata) are probed.
+// The modification time window and exclude patterns of the bundle are set
+// on the manifest, so files left out at creation are not mistaken for
+// untracked ones.
+func loadManifest(path string) (*checksum.ChecksumFile, error) {
+	files := &checksum.ChecksumFile{}
+	if meta, err := metadata.Load(path); err == nil {
+		files.Format = checksum.ManifestFormat(meta.ManifestFormat)
+		files.Algorithm = checksum.HashAlgorithm(meta.HashAlgorithm)
+		after, before := meta.ModTimeFilter()
+		files.Filter = checksum.ModTimeFilter{After: after, Before: before}
+		files.Exclude = meta.Exclude
+	}
+	if err := files.Load(path); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// loadPrevious loads the descriptive metadata of an existing bundle that is
+// about to be recreated. Parts that cannot be read are left nil so the
+// create falls back to fresh values.
+func loadPrevious(path string) *Bundle {
+	previous := &Bundle{Path: path}
+	if meta, err := metadata.Load(path); err == nil {
+		previous.Metadata = meta
+	} else {
+		log.Warnf("Cannot preserve metadata of existing bundle: %v", err)
+	}
+	if tags, err := tag.Load(path); err == nil {
+		previous.Tags = tags
+	} else {
+		log.Warnf("Cannot preserve tags of existing bundle: %v", err)
+	}
+	if st, err := state.Load(path); err == nil {
+		previous.State = st
+	} else {
+		log.Debugf("No state to preserve for existing bundle: %v", err)
+	}
+	return previous
+}
@@ -1,9 +1,20 @@
 package bundle
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/jvzantvoort/bundle/checksum"
+	"github.com/jvzantvoort/bundle/lock"
+	"github.com/jvzantvoort/bundle/metadata"
+	"github.com/jvzantvoort/bundle/state"
+	"github.com/jvzantvoort/bundle/utils"
 )
 
 // TestCreateLoadVerify performs an end-to-end create, load, verify and corruption detection
@@ -83,3 +94,616 @@ func TestLoadNonBundle(t *testing.T) {
 		t.Fatalf("expected error loading non-bundle dir")
 	}
 }
+
+// TestCompare checks added, removed and modified detection against a live directory
+func TestCompare(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Create(dir, "Compare"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	other := t.TempDir()
+	if err := os.WriteFile(filepath.Join(other, "a.txt"), []byte("changed"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(other, "c.txt"), []byte("c"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	result, err := Compare(dir, other)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if result.Identical() {
+		t.Fatalf("expected differences")
+	}
+	if len(result.Added) != 1 || result.Added[0] != "c.txt" {
+		t.Errorf("added = %v, want [c.txt]", result.Added)
+	}
+	if len(result.Removed) != 1 || result.Removed[0] != "b.txt" {
+		t.Errorf("removed = %v, want [b.txt]", result.Removed)
+	}
+	if len(result.Modified) != 1 || result.Modified[0] != "a.txt" {
+		t.Errorf("modified = %v, want [a.txt]", result.Modified)
+	}
+
+	result, err = Compare(dir, dir)
+	if err != nil {
+		t.Fatalf("Compare self failed: %v", err)
+	}
+	if !result.Identical() {
+		t.Errorf("expected bundle to match its own directory: %+v", result)
+	}
+
+	if _, err := Compare(dir, filepath.Join(dir, "a.txt")); !errors.Is(err, utils.ErrInvalidPath) {
+		t.Errorf("Compare with a file: err = %v, want ErrInvalidPath", err)
+	}
+}
+
+func TestCreateJSONManifest(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	b, err := CreateWithOptions(dir, CreateOptions{Title: "JSON", ManifestFormat: checksum.FormatJSON})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if b.Metadata.ManifestFormat != "json" {
+		t.Errorf("ManifestFormat = %q, want json", b.Metadata.ManifestFormat)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".bundle", "SHA256SUM.json")); err != nil {
+		t.Errorf("expected SHA256SUM.json: %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Files.Records) != 1 {
+		t.Errorf("loaded %d records, want 1", len(loaded.Files.Records))
+	}
+
+	verified, corrupted, err := Verify(dir)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !verified {
+		t.Errorf("expected verified, corrupted = %v", corrupted)
+	}
+}
+
+func TestCreateSHA512(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	b, err := CreateWithOptions(dir, CreateOptions{Title: "SHA512", HashAlgorithm: checksum.SHA512})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if b.Metadata.HashAlgorithm != "sha512" {
+		t.Errorf("HashAlgorithm = %q, want sha512", b.Metadata.HashAlgorithm)
+	}
+	if len(b.Metadata.BundleChecksum) != 128 {
+		t.Errorf("bundle checksum has %d characters, want 128", len(b.Metadata.BundleChecksum))
+	}
+	if err := b.Metadata.Validate(); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".bundle", "SHA512SUM.txt")); err != nil {
+		t.Errorf("expected SHA512SUM.txt: %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Files.Algorithm != checksum.SHA512 || !checksum.SHA512.IsChecksum(loaded.Files.Records[0].Checksum) {
+		t.Errorf("loaded manifest is not sha512: %+v", loaded.Files)
+	}
+
+	verified, corrupted, err := Verify(dir)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !verified {
+		t.Errorf("expected verified, corrupted = %v", corrupted)
+	}
+
+	// Recreating with the default algorithm replaces the SHA512 manifest
+	if _, err := CreateWithOptions(dir, CreateOptions{Force: true}); err != nil {
+		t.Fatalf("recreate failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".bundle", "SHA512SUM.txt")); !os.IsNotExist(err) {
+		t.Errorf("stale SHA512SUM.txt left behind: %v", err)
+	}
+}
+
+func TestCreateDryRun(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.txt": "a", "b.txt": "bb"})
+
+	dry, err := CreateWithOptions(dir, CreateOptions{Title: "Dry", DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".bundle")); !os.IsNotExist(err) {
+		t.Fatalf(".bundle/ created by dry run: %v", err)
+	}
+	if len(dry.Files.Records) != 2 || dry.State.SizeBytes != 3 {
+		t.Errorf("dry run: %d files, %d bytes, want 2 and 3", len(dry.Files.Records), dry.State.SizeBytes)
+	}
+
+	// The checksum is the one a real create produces
+	b, err := Create(dir, "Real")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if dry.Metadata.BundleChecksum != b.Metadata.BundleChecksum {
+		t.Errorf("dry run checksum = %s, want %s", dry.Metadata.BundleChecksum, b.Metadata.BundleChecksum)
+	}
+
+	if _, err := CreateWithOptions(filepath.Join(dir, "missing"), CreateOptions{DryRun: true}); !os.IsNotExist(err) {
+		t.Errorf("dry run of missing directory error = %v, want not exist", err)
+	}
+}
+
+func TestCreateAuthor(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.txt": "a"})
+
+	b, err := CreateWithOptions(dir, CreateOptions{Title: "CI", Author: "  Jane Doe "})
+	if err != nil {
+		t.Fatalf("CreateWithOptions failed: %v", err)
+	}
+	if b.Metadata.Author != "Jane Doe" {
+		t.Errorf("author = %q, want %q", b.Metadata.Author, "Jane Doe")
+	}
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Metadata.Author != "Jane Doe" {
+		t.Errorf("saved author = %q, want %q", loaded.Metadata.Author, "Jane Doe")
+	}
+
+	// Without an author the current user is recorded
+	other := t.TempDir()
+	writeFiles(t, other, map[string]string{"a.txt": "a"})
+	b, err = Create(other, "Default")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if b.Metadata.Author == "" || b.Metadata.Author == "Jane Doe" {
+		t.Errorf("default author = %q, want the current user", b.Metadata.Author)
+	}
+}
+
+func TestCreateCreatedAt(t *testing.T) {
+	pinned := time.Date(2024, 3, 1, 12, 0, 0, 500, time.FixedZone("CET", 3600))
+	opts := CreateOptions{Title: "Release", Author: "ci", CreatedAt: pinned}
+
+	// Identical content with a pinned time gives identical META.json
+	var metas [][]byte
+	for i := 0; i < 2; i++ {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"a.txt": "a", "b.txt": "b"})
+		b, err := CreateWithOptions(dir, opts)
+		if err != nil {
+			t.Fatalf("CreateWithOptions failed: %v", err)
+		}
+		want := pinned.UTC().Truncate(time.Second)
+		if !b.Metadata.CreatedAt.Equal(want) || b.Metadata.CreatedAt.Location() != time.UTC {
+			t.Errorf("created_at = %v, want %v", b.Metadata.CreatedAt, want)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, ".bundle", "META.json"))
+		if err != nil {
+			t.Fatalf("reading META.json: %v", err)
+		}
+		metas = append(metas, data)
+	}
+	if string(metas[0]) != string(metas[1]) {
+		t.Errorf("META.json differs between runs:\n%s\n%s", metas[0], metas[1])
+	}
+
+	// Far future timestamps are rejected before anything is written
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.txt": "a"})
+	opts.CreatedAt = time.Now().Add(24 * time.Hour)
+	if _, err := CreateWithOptions(dir, opts); err == nil {
+		t.Error("expected an error for a creation time in the future")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".bundle")); !os.IsNotExist(err) {
+		t.Errorf("expected no .bundle directory, got %v", err)
+	}
+}
+
+func TestDefaultTitle(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/data/vacation_photos-2024", "Vacation Photos 2024"},
+		{"/data/photos/", "Photos"},
+		{"/data/Tax.Records", "Tax Records"},
+		{"/data/already Titled", "Already Titled"},
+		{"/data/__x__", "X"},
+		{"/", ""},
+	}
+	for _, tt := range tests {
+		if got := DefaultTitle(tt.path); got != tt.want {
+			t.Errorf("DefaultTitle(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCreateExistingBundle(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	first, err := Create(dir, "First")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	first.Tags.Add("keep")
+	if err := first.Tags.Save(dir); err != nil {
+		t.Fatalf("save tags: %v", err)
+	}
+
+	if _, err := Create(dir, "Second"); !errors.Is(err, utils.ErrAlreadyABundle) {
+		t.Fatalf("Create on existing bundle error = %v, want ErrAlreadyABundle", err)
+	}
+
+	forced, err := CreateWithOptions(dir, CreateOptions{Title: "Forced", Force: true})
+	if err != nil {
+		t.Fatalf("forced Create failed: %v", err)
+	}
+	if !forced.Metadata.CreatedAt.Equal(first.Metadata.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want preserved %v", forced.Metadata.CreatedAt, first.Metadata.CreatedAt)
+	}
+	if got := forced.Tags.List(); len(got) != 1 || got[0] != "keep" {
+		t.Errorf("tags after forced create = %v, want [keep]", got)
+	}
+
+	reset, err := CreateWithOptions(dir, CreateOptions{Title: "Reset", Force: true, ResetMetadata: true})
+	if err != nil {
+		t.Fatalf("reset Create failed: %v", err)
+	}
+	if len(reset.Tags.List()) != 0 {
+		t.Errorf("tags after reset = %v, want none", reset.Tags.List())
+	}
+	if reset.Metadata.CreatedAt.Equal(first.Metadata.CreatedAt) {
+		t.Errorf("CreatedAt should be fresh after reset")
+	}
+}
+
+func TestCreateForcePreservesMetadata(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	first, err := Create(dir, "Original Title")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	first.Tags.Add("travel", "photos")
+	if err := first.Tags.Save(dir); err != nil {
+		t.Fatalf("save tags: %v", err)
+	}
+	first.State.AddReplica("s3://bucket/path")
+	if err := first.State.Save(dir); err != nil {
+		t.Fatalf("save state: %v", err)
+	}
+	if err := metadata.UpdateDescription(dir, "Scanned from the family albums"); err != nil {
+		t.Fatalf("update description: %v", err)
+	}
+	if err := metadata.SetAnnotation(dir, "project", "x"); err != nil {
+		t.Fatalf("set annotation: %v", err)
+	}
+
+	// Content changes; descriptive metadata must survive the recreate
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	forced, err := CreateWithOptions(dir, CreateOptions{Force: true})
+	if err != nil {
+		t.Fatalf("forced Create failed: %v", err)
+	}
+	if forced.Metadata.BundleChecksum == first.Metadata.BundleChecksum {
+		t.Errorf("bundle checksum should reflect the new content")
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Metadata.Title != "Original Title" {
+		t.Errorf("title = %q, want preserved %q", loaded.Metadata.Title, "Original Title")
+	}
+	if got := loaded.Tags.List(); len(got) != 2 || got[0] != "photos" || got[1] != "travel" {
+		t.Errorf("tags = %v, want [photos travel]", got)
+	}
+	if len(loaded.State.Replicas) != 1 || loaded.State.Replicas[0] != "s3://bucket/path" {
+		t.Errorf("replicas = %v, want preserved", loaded.State.Replicas)
+	}
+	if loaded.Metadata.Description != "Scanned from the family albums" {
+		t.Errorf("description = %q, want preserved", loaded.Metadata.Description)
+	}
+	if got := loaded.Metadata.Annotations["project"]; got != "x" {
+		t.Errorf("annotation project = %q, want preserved %q", got, "x")
+	}
+
+	// A new description replaces the kept one
+	forced, err = CreateWithOptions(dir, CreateOptions{Force: true, Description: "Rescanned"})
+	if err != nil {
+		t.Fatalf("forced Create failed: %v", err)
+	}
+	if forced.Metadata.Description != "Rescanned" || forced.Metadata.Annotations["project"] != "x" {
+		t.Errorf("description = %q, annotations = %v", forced.Metadata.Description, forced.Metadata.Annotations)
+	}
+
+	// ResetMetadata discards them
+	reset, err := CreateWithOptions(dir, CreateOptions{Force: true, ResetMetadata: true})
+	if err != nil {
+		t.Fatalf("reset Create failed: %v", err)
+	}
+	if reset.Metadata.Description != "" || len(reset.Metadata.Annotations) != 0 {
+		t.Errorf("reset kept description %q and annotations %v", reset.Metadata.Description, reset.Metadata.Annotations)
+	}
+}
+
+func TestVerifyStrictExtraFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Create(dir, "Extra"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "added.txt"), []byte("added"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	report, err := VerifyWithOptions(dir, VerifyOptions{})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !report.Verified || len(report.Extra) != 1 || report.Extra[0] != "added.txt" {
+		t.Errorf("lenient verify = %v with extra %v, want valid with [added.txt]", report.Verified, report.Extra)
+	}
+
+	report, err = VerifyWithOptions(dir, VerifyOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if report.Verified {
+		t.Errorf("strict verify with an extra file is valid")
+	}
+}
+
+func TestFixTimestamps(t *testing.T) {
+	dir := t.TempDir()
+	b, err := Create(dir, "Skewed")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	now := time.Now()
+	if issues := b.FutureTimestamps(now); len(issues) != 0 {
+		t.Fatalf("fresh bundle has future timestamps: %v", issues)
+	}
+
+	// Within the tolerance is not reported
+	future := now.Add(24 * time.Hour)
+	b.Metadata.CreatedAt = now.Add(time.Minute)
+	b.State.LastChecked = future
+	if err := b.Metadata.Save(dir); err != nil {
+		t.Fatalf("save metadata: %v", err)
+	}
+	if err := b.State.Save(dir); err != nil {
+		t.Fatalf("save state: %v", err)
+	}
+
+	issues, err := FixTimestamps(dir, now)
+	if err != nil {
+		t.Fatalf("FixTimestamps failed: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Field != "last_checked" || !issues[0].Value.Equal(future) {
+		t.Fatalf("FixTimestamps issues = %v, want last_checked %v", issues, future)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !loaded.State.LastChecked.Equal(now) {
+		t.Errorf("last_checked = %v, want %v", loaded.State.LastChecked, now)
+	}
+	if issues := loaded.FutureTimestamps(now); len(issues) != 0 {
+		t.Errorf("future timestamps after fix: %v", issues)
+	}
+}
+
+func TestCreateExclude(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", ".DS_Store", "sub/b.txt~"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	exclude := []string{".DS_Store", "*~"}
+	b, err := CreateWithOptions(dir, CreateOptions{Title: "Excluded", Exclude: exclude})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if len(b.Files.Records) != 1 || b.Files.Records[0].FilePath != "a.txt" {
+		t.Fatalf("records = %v, want only a.txt", b.Files.Records)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Metadata.Exclude) != 2 {
+		t.Errorf("META.json exclude = %v, want %v", loaded.Metadata.Exclude, exclude)
+	}
+
+	// Excluded files are neither untracked nor added
+	if err := os.WriteFile(filepath.Join(dir, "new.txt~"), []byte("new"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	report, err := VerifyWithOptions(dir, VerifyOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !report.Verified || len(report.Extra) != 0 {
+		t.Errorf("strict verify = %v with extra %v, want valid without extra files", report.Verified, report.Extra)
+	}
+	result, err := Compare(dir, dir)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if !result.Identical() {
+		t.Errorf("Compare = %+v, want identical", result)
+	}
+}
+
+func TestCreateIgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt":         "a",
+		"debug.log":     "log",
+		"keep.log":      "keep",
+		"cache/x.bin":   "cache",
+		".bundleignore": "*.log\n!keep.log\ncache/\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	b, err := Create(dir, "Ignored")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	var got []string
+	for _, record := range b.Files.Records {
+		got = append(got, record.FilePath)
+	}
+	sort.Strings(got)
+	if strings.Join(got, ",") != "a.txt,keep.log" {
+		t.Fatalf("records = %v, want a.txt and keep.log", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "cache", "y.bin"), []byte("new"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	report, err := VerifyWithOptions(dir, VerifyOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !report.Verified || len(report.Extra) != 0 {
+		t.Errorf("strict verify = %v with extra %v, want valid without extra files", report.Verified, report.Extra)
+	}
+}
+
+func TestVerifyConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if _, err := Create(dir, "Concurrent"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// Every run either verifies or finds the bundle locked by another one
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _, errs[i] = Verify(dir)
+		}(i)
+	}
+	wg.Wait()
+	succeeded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, utils.ErrBundleLocked):
+			t.Errorf("Verify error = %v, want nil or ErrBundleLocked", err)
+		}
+	}
+	if succeeded == 0 {
+		t.Errorf("no Verify succeeded")
+	}
+
+	st, err := state.Load(dir)
+	if err != nil {
+		t.Fatalf("STATE.json unreadable after concurrent verifies: %v", err)
+	}
+	if !st.Verified {
+		t.Errorf("STATE.json not marked verified")
+	}
+
+	// A held lock is reported rather than ignored
+	held, err := lock.AcquireLock(dir)
+	if err != nil {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+	defer held.Release()
+	if _, _, err := Verify(dir); !errors.Is(err, utils.ErrBundleLocked) {
+		t.Errorf("Verify with held lock error = %v, want ErrBundleLocked", err)
+	}
+}
+
+func TestLoadValidatesMetadata(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	b, err := Create(dir, "Invalid")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	b.Metadata.Author = ""
+	if err := b.Metadata.Save(dir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	_, err = Load(dir)
+	if !errors.Is(err, utils.ErrIncompleteBundle) || !strings.Contains(err.Error(), "author") {
+		t.Errorf("Load() error = %v, want ErrIncompleteBundle naming author", err)
+	}
+	if code := utils.ExitCodeFromError(err); code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+
+	loaded, err := LoadUnchecked(dir)
+	if err != nil {
+		t.Fatalf("LoadUnchecked failed: %v", err)
+	}
+	if loaded.Metadata.Title != "Invalid" {
+		t.Errorf("Title = %q, want Invalid", loaded.Metadata.Title)
+	}
+}
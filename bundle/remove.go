@@ -0,0 +1,121 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jvzantvoort/bundle/lock"
+	"github.com/jvzantvoort/bundle/metadata"
+	"github.com/jvzantvoort/bundle/state"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// RemoveFiles deletes files from the bundle at path and updates its
+// metadata, holding the bundle lock.
+//
+// The records of the files are dropped from the manifest, the bundle
+// checksum in META.json is recomputed from the remaining records and the
+// size in STATE.json shrinks by their size; as with AddFiles, the bundle
+// gets a new identity. All paths are checked first: each must be in the
+// manifest. The metadata is saved before the files are deleted, so a file
+// that cannot be deleted is left behind untracked rather than corrupting
+// the bundle.
+//
+// Example:
+//
+//	b, err := bundle.RemoveFiles("/path/to/bundle", []string{"docs/old.pdf"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(b.Metadata.BundleChecksum) // the new bundle checksum
+//
+// Parameters:
+//   - path: absolute or relative path to the bundle directory
+//   - relPaths: paths of the files relative to the bundle root
+//
+// Returns:
+//   - *Bundle: the updated bundle
+//   - error: utils.ErrNotABundle without .bundle/, utils.ErrFileNotTracked
+//     if a path is not in the manifest, utils.ErrBundleLocked if another
+//     process holds the lock, or I/O errors
+func RemoveFiles(path string, relPaths []string) (*Bundle, error) {
+	if !utils.IsBundleDir(path) {
+		return nil, utils.ErrNotABundle
+	}
+	if len(relPaths) == 0 {
+		return nil, fmt.Errorf("%w: no files to remove", utils.ErrInvalidPath)
+	}
+
+	bundleLock, err := lock.AcquireLockWait(path, lock.WaitTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := bundleLock.Release(); err != nil {
+			log.Errorf("failed to release lock: %v", err)
+		}
+	}()
+
+	meta, err := metadata.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := loadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check every path before changing anything
+	for _, relPath := range relPaths {
+		if _, ok := manifest.Lookup(relPath); !ok {
+			return nil, fmt.Errorf("%w: %s", utils.ErrFileNotTracked, relPath)
+		}
+	}
+
+	var removedSize int64
+	var removed []string
+	for _, relPath := range relPaths {
+		record, ok := manifest.Remove(relPath)
+		if !ok {
+			// Already removed: the path was given twice
+			continue
+		}
+		file := filepath.Join(path, filepath.FromSlash(record.FilePath))
+		if info, err := os.Stat(file); err == nil {
+			removedSize += info.Size()
+		} else {
+			removedSize += record.Size
+		}
+		removed = append(removed, file)
+	}
+
+	meta.BundleChecksum = manifestChecksum(manifest)
+	if err := manifest.Save(path); err != nil {
+		return nil, fmt.Errorf("failed to save checksums: %w", err)
+	}
+	if err := meta.Save(path); err != nil {
+		return nil, fmt.Errorf("failed to save metadata: %w", err)
+	}
+
+	bundleState, err := state.Load(path)
+	if err != nil {
+		bundleState = &state.State{Replicas: []string{}, History: []state.VerificationEvent{}}
+	}
+	bundleState.SizeBytes -= removedSize
+	if bundleState.SizeBytes < 0 {
+		bundleState.SizeBytes = 0
+	}
+	if err := bundleState.Save(path); err != nil {
+		return nil, fmt.Errorf("failed to save state: %w", err)
+	}
+
+	for _, file := range removed {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			log.Warnf("failed to delete %s: %v", file, err)
+		}
+	}
+
+	return Load(path)
+}
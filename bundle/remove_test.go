@@ -0,0 +1,64 @@
+package bundle
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+func TestRemoveFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeFiles(t, dir, map[string]string{"a.txt": "a", "b.txt": "bb", "sub/c.txt": "ccc"})
+	orig, err := Create(dir, "Shrinks")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// A path that is not tracked is refused before anything changes
+	if _, err := RemoveFiles(dir, []string{"b.txt", "missing.txt"}); !errors.Is(err, utils.ErrFileNotTracked) {
+		t.Errorf("RemoveFiles() error = %v, want ErrFileNotTracked", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.txt")); err != nil {
+		t.Errorf("b.txt deleted despite the error: %v", err)
+	}
+
+	b, err := RemoveFiles(dir, []string{"b.txt", "./sub/c.txt"})
+	if err != nil {
+		t.Fatalf("RemoveFiles failed: %v", err)
+	}
+	for _, name := range []string{"b.txt", "sub/c.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s still present: %v", name, err)
+		}
+	}
+
+	// The new checksum is the one a fresh bundle of the remaining files gets
+	fresh := t.TempDir()
+	writeFiles(t, fresh, map[string]string{"a.txt": "a"})
+	want, err := Create(fresh, "Fresh")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if b.Metadata.BundleChecksum != want.Metadata.BundleChecksum {
+		t.Errorf("checksum = %s, want %s", b.Metadata.BundleChecksum, want.Metadata.BundleChecksum)
+	}
+	if !b.Metadata.CreatedAt.Equal(orig.Metadata.CreatedAt) {
+		t.Errorf("created_at = %v, want %v", b.Metadata.CreatedAt, orig.Metadata.CreatedAt)
+	}
+	if len(b.Files.Records) != 1 || b.State.SizeBytes != 1 {
+		t.Errorf("records = %d, size = %d, want 1 and 1", len(b.Files.Records), b.State.SizeBytes)
+	}
+	if verified, corrupted, err := Verify(dir); err != nil || !verified {
+		t.Errorf("Verify() = %v, %v, %v, want verified", verified, corrupted, err)
+	}
+
+	if _, err := RemoveFiles(t.TempDir(), []string{"a.txt"}); !errors.Is(err, utils.ErrNotABundle) {
+		t.Errorf("RemoveFiles() on non-bundle error = %v, want ErrNotABundle", err)
+	}
+}
@@ -0,0 +1,172 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jvzantvoort/bundle/checksum"
+	"github.com/jvzantvoort/bundle/lock"
+	"github.com/jvzantvoort/bundle/metadata"
+	"github.com/jvzantvoort/bundle/state"
+	"github.com/jvzantvoort/bundle/tag"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// RepairReport describes what Repair did.
+//
+// Fields:
+//   - Regenerated: .bundle/ files that were rewritten, e.g. "STATE.json"
+//   - Skipped: .bundle/ files that are damaged but were left alone because
+//     regenerating them would change the bundle identity
+//   - Verified: whether the files on disk match the bundle checksum in
+//     META.json
+//   - BundleChecksum: the bundle checksum recorded in META.json
+//   - ComputedChecksum: the bundle checksum of the files on disk
+type RepairReport struct {
+	Regenerated      []string `json:"regenerated"`
+	Skipped          []string `json:"skipped"`
+	Verified         bool     `json:"verified"`
+	BundleChecksum   string   `json:"bundle_checksum"`
+	ComputedChecksum string   `json:"computed_checksum"`
+}
+
+// Mismatch reports whether the files on disk no longer match the bundle
+// checksum in META.json.
+func (r *RepairReport) Mismatch() bool {
+	return r.ComputedChecksum != r.BundleChecksum
+}
+
+// Repair regenerates missing or damaged metadata files of the bundle at
+// path, holding the bundle lock.
+//
+// META.json identifies the bundle and is never rewritten: its title,
+// author, creation time and bundle checksum are kept, and it must be
+// readable. The files are rescanned and hashed with the settings recorded
+// in it. Then:
+//   - STATE.json is regenerated from the scan when it cannot be read, with
+//     the size of the files and whether they match the bundle checksum
+//   - TAGS.txt is recreated empty when it is missing or cannot be read
+//   - the checksum manifest is regenerated when it cannot be read or does
+//     not produce the bundle checksum, but only if the scanned files do;
+//     otherwise it is listed in Skipped, since writing it would silently
+//     give the bundle a new identity
+//
+// A readable STATE.json and TAGS.txt are left unchanged. Check
+// report.Mismatch to find bundles whose content changed; recreate them with
+// a forced create once the change is known to be wanted.
+//
+// Example:
+//
+//	report, err := bundle.Repair("/path/to/bundle")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, name := range report.Regenerated {
+//	    fmt.Printf("Regenerated %s\n", name)
+//	}
+//	if report.Mismatch() {
+//	    fmt.Println("Contents no longer match the bundle checksum")
+//	}
+//
+// Parameters:
+//   - path: absolute or relative path to the bundle directory
+//
+// Returns:
+//   - *RepairReport: what was regenerated and whether the files verify
+//   - error: utils.ErrNotABundle without .bundle/, utils.ErrIncompleteBundle
+//     when META.json cannot be read, or I/O errors
+func Repair(path string) (*RepairReport, error) {
+	if _, err := os.Stat(filepath.Join(path, ".bundle")); os.IsNotExist(err) {
+		return nil, utils.ErrNotABundle
+	}
+
+	bundleLock, err := lock.AcquireLockWait(path, lock.WaitTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := bundleLock.Release(); err != nil {
+			log.Errorf("failed to release lock: %v", err)
+		}
+	}()
+
+	meta, err := metadata.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: META.json cannot be repaired: %w", utils.ErrIncompleteBundle, err)
+	}
+
+	// Rescan with the settings the bundle was created with
+	after, before := meta.ModTimeFilter()
+	scanned := &checksum.ChecksumFile{
+		Format:    checksum.ManifestFormat(meta.ManifestFormat),
+		Algorithm: checksum.HashAlgorithm(meta.HashAlgorithm),
+		Filter:    checksum.ModTimeFilter{After: after, Before: before},
+		Exclude:   meta.Exclude,
+	}
+	if err := scanned.ComputeTolerant(path); err != nil {
+		return nil, fmt.Errorf("failed to compute checksums: %w", err)
+	}
+	for _, e := range scanned.Errors {
+		log.Warnf("Skipping unreadable path: %v", e)
+	}
+
+	report := &RepairReport{
+		Regenerated:      []string{},
+		Skipped:          []string{},
+		BundleChecksum:   meta.BundleChecksum,
+		ComputedChecksum: manifestChecksum(scanned),
+	}
+	report.Verified = !report.Mismatch()
+
+	// The manifest is intact when it reproduces the bundle identity
+	manifest, err := loadManifest(path)
+	if err == nil && manifestChecksum(manifest) == meta.BundleChecksum {
+		log.Debugf("Manifest %s is intact", manifest.FileName())
+	} else if report.Verified {
+		if err := scanned.Save(path); err != nil {
+			return nil, fmt.Errorf("failed to save checksums: %w", err)
+		}
+		report.Regenerated = append(report.Regenerated, scanned.FileName())
+	} else {
+		report.Skipped = append(report.Skipped, scanned.FileName())
+	}
+
+	if _, err := state.Load(path); err != nil {
+		log.Debugf("Regenerating STATE.json: %v", err)
+		bundleState := &state.State{
+			Verified:    report.Verified,
+			LastChecked: time.Now(),
+			Replicas:    []string{},
+			SizeBytes:   scanned.TotalSize,
+			History:     []state.VerificationEvent{},
+		}
+		if err := bundleState.Save(path); err != nil {
+			return nil, fmt.Errorf("failed to save state: %w", err)
+		}
+		report.Regenerated = append(report.Regenerated, "STATE.json")
+	}
+
+	tagsFile := filepath.Join(path, ".bundle", "TAGS.txt")
+	if _, err := os.ReadFile(tagsFile); err != nil {
+		log.Debugf("Regenerating TAGS.txt: %v", err)
+		bundleTags := &tag.Tags{Tags: []string{}}
+		if err := bundleTags.Save(path); err != nil {
+			return nil, fmt.Errorf("failed to save tags: %w", err)
+		}
+		report.Regenerated = append(report.Regenerated, "TAGS.txt")
+	}
+
+	return report, nil
+}
+
+// manifestChecksum returns the bundle checksum of the records in files.
+func manifestChecksum(files *checksum.ChecksumFile) string {
+	checksums := make([]string, len(files.Records))
+	for i, record := range files.Records {
+		checksums[i] = record.Checksum
+	}
+	return checksum.ComputeBundleHash(checksums, files.Algorithm)
+}
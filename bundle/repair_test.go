@@ -0,0 +1,93 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jvzantvoort/bundle/state"
+	"github.com/jvzantvoort/bundle/tag"
+)
+
+func TestRepair(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	b, err := Create(dir, "Repair Me")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	original := b.Metadata.BundleChecksum
+
+	// An intact bundle needs nothing
+	report, err := Repair(dir)
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if len(report.Regenerated) != 0 || !report.Verified {
+		t.Errorf("Repair of intact bundle = %+v, want nothing regenerated", report)
+	}
+
+	// Remove and truncate metadata files
+	bundleDir := filepath.Join(dir, ".bundle")
+	if err := os.Remove(filepath.Join(bundleDir, "STATE.json")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := os.Remove(filepath.Join(bundleDir, "TAGS.txt")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(bundleDir, "SHA256SUM.txt"), nil, 0644); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+	if _, err := Load(dir); err == nil {
+		t.Fatal("Load of damaged bundle succeeded")
+	}
+
+	report, err = Repair(dir)
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if len(report.Regenerated) != 3 || len(report.Skipped) != 0 || report.Mismatch() {
+		t.Errorf("Repair() = %+v, want manifest, STATE.json and TAGS.txt regenerated", report)
+	}
+	repaired, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load after repair failed: %v", err)
+	}
+	if repaired.Metadata.BundleChecksum != original || repaired.Metadata.Title != "Repair Me" {
+		t.Errorf("metadata changed: %+v", repaired.Metadata)
+	}
+	if !repaired.State.Verified || repaired.State.SizeBytes != 10 || len(repaired.Files.Records) != 2 {
+		t.Errorf("state = %+v with %d records", repaired.State, len(repaired.Files.Records))
+	}
+	if tags, err := tag.Load(dir); err != nil || len(tags.List()) != 0 {
+		t.Errorf("tags = %v, %v; want empty", tags, err)
+	}
+
+	// Changed content must not silently get a new manifest
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.Remove(filepath.Join(bundleDir, "SHA256SUM.txt")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := os.Remove(filepath.Join(bundleDir, "STATE.json")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	report, err = Repair(dir)
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if !report.Mismatch() || report.Verified || len(report.Skipped) != 1 || report.Skipped[0] != "SHA256SUM.txt" {
+		t.Errorf("Repair of changed bundle = %+v, want mismatch with manifest skipped", report)
+	}
+	if _, err := os.Stat(filepath.Join(bundleDir, "SHA256SUM.txt")); !os.IsNotExist(err) {
+		t.Errorf("manifest was regenerated despite the mismatch")
+	}
+	if st, err := state.Load(dir); err != nil || st.Verified {
+		t.Errorf("state = %+v, %v; want regenerated as not verified", st, err)
+	}
+}
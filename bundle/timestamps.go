@@ -0,0 +1,118 @@
+package bundle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jvzantvoort/bundle/lock"
+	log "github.com/sirupsen/logrus"
+)
+
+// FutureTimestampTolerance is how far a bundle timestamp may lie in the
+// future before it is reported, allowing for small clock differences
+// between machines.
+const FutureTimestampTolerance = 5 * time.Minute
+
+// TimestampIssue describes a bundle timestamp that lies in the future.
+//
+// Fields:
+//   - Field: the offending field, "created_at" (META.json) or
+//     "last_checked" (STATE.json)
+//   - Value: the recorded timestamp
+type TimestampIssue struct {
+	Field string    `json:"field"`
+	Value time.Time `json:"value"`
+}
+
+// String formats the issue for log messages.
+func (i TimestampIssue) String() string {
+	return fmt.Sprintf("%s is in the future: %s", i.Field, i.Value.UTC().Format(time.RFC3339))
+}
+
+// FutureTimestamps reports timestamps of b more than
+// FutureTimestampTolerance after now.
+//
+// Such timestamps come from clock skew or bad imports and silently break
+// age-based queries, e.g. a bundle that seems to have been verified
+// recently for the next months.
+//
+// Example:
+//
+//	b, _ := bundle.Load("/path/to/bundle")
+//	for _, issue := range b.FutureTimestamps(time.Now()) {
+//	    log.Warn(issue)
+//	}
+//
+// Parameters:
+//   - now: the current time
+//
+// Returns:
+//   - []TimestampIssue: one entry per offending field, empty if none
+func (b *Bundle) FutureTimestamps(now time.Time) []TimestampIssue {
+	limit := now.Add(FutureTimestampTolerance)
+	issues := []TimestampIssue{}
+	if b.Metadata != nil && b.Metadata.CreatedAt.After(limit) {
+		issues = append(issues, TimestampIssue{Field: "created_at", Value: b.Metadata.CreatedAt})
+	}
+	if b.State != nil && b.State.LastChecked.After(limit) {
+		issues = append(issues, TimestampIssue{Field: "last_checked", Value: b.State.LastChecked})
+	}
+	return issues
+}
+
+// FixTimestamps re-stamps timestamps of the bundle at path that lie in the
+// future with now.
+//
+// The bundle is locked while META.json and STATE.json are rewritten; files
+// without a future timestamp are left untouched. The bundle checksum does
+// not depend on the timestamps and is unchanged.
+//
+// Example:
+//
+//	fixed, err := bundle.FixTimestamps("/path/to/bundle", time.Now())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("Fixed %d timestamps\n", len(fixed))
+//
+// Parameters:
+//   - path: absolute or relative path to the bundle directory
+//   - now: the time to stamp the offending fields with
+//
+// Returns:
+//   - []TimestampIssue: the fields that were fixed, with their old values
+//   - error: lock errors, or if the bundle cannot be loaded or saved
+func FixTimestamps(path string, now time.Time) ([]TimestampIssue, error) {
+	bundleLock, err := lock.AcquireLockWait(path, lock.WaitTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := bundleLock.Release(); err != nil {
+			log.Errorf("failed to release lock: %v", err)
+		}
+	}()
+
+	// A repair must not depend on the rest of the metadata being valid
+	b, err := LoadUnchecked(path)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := b.FutureTimestamps(now)
+	for _, issue := range issues {
+		switch issue.Field {
+		case "created_at":
+			b.Metadata.CreatedAt = now
+			if err := b.Metadata.Save(path); err != nil {
+				return nil, err
+			}
+		case "last_checked":
+			b.State.LastChecked = now
+			if err := b.State.Save(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return issues, nil
+}
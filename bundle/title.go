@@ -0,0 +1,43 @@
+package bundle
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultTitle derives a human-readable title from a directory path.
+//
+// It takes the base name of the cleaned absolute path, turns underscores,
+// hyphens and dots into spaces and capitalizes the first letter of each
+// word. The CLI uses it when create is run without --title.
+//
+// Example:
+//
+//	title := bundle.DefaultTitle("/data/vacation_photos-2024")
+//	// title = "Vacation Photos 2024"
+//
+// Parameters:
+//   - path: absolute or relative path to the bundle directory
+//
+// Returns:
+//   - string: the derived title, or "" if no name can be derived (e.g. "/")
+func DefaultTitle(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	base := filepath.Base(filepath.Clean(path))
+	if base == "." || base == string(filepath.Separator) {
+		return ""
+	}
+
+	words := strings.FieldsFunc(base, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.' || unicode.IsSpace(r)
+	})
+	for i, word := range words {
+		r, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(r)) + word[size:]
+	}
+	return strings.Join(words, " ")
+}
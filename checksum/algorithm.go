@@ -0,0 +1,169 @@
+package checksum
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+// HashAlgorithm selects the hash used for file and bundle checksums.
+//
+// The algorithm of a bundle is recorded in META.json as hash_algorithm
+// and names its manifest (SHA256SUM.txt, SHA512SUM.txt). The empty value
+// means SHA256, so bundles created before the algorithm was recorded keep
+// loading and verifying unchanged.
+//
+// Example:
+//
+//	files := &checksum.ChecksumFile{Algorithm: checksum.SHA512}
+//	files.Compute("/path/to/files")
+//	files.Save("/path/to/bundle") // writes .bundle/SHA512SUM.txt
+type HashAlgorithm string
+
+const (
+	// SHA256 is the default algorithm (64 hex characters)
+	SHA256 HashAlgorithm = "sha256"
+
+	// SHA512 produces 128 hex characters, as sha512sum(1) does
+	SHA512 HashAlgorithm = "sha512"
+)
+
+// hashAlgorithms lists the supported algorithms in manifest probe order.
+var hashAlgorithms = []HashAlgorithm{SHA256, SHA512}
+
+// ParseHashAlgorithm converts a string to a HashAlgorithm.
+//
+// An empty string selects SHA256.
+//
+// Example:
+//
+//	algo, err := checksum.ParseHashAlgorithm("sha512")
+//
+// Parameters:
+//   - s: "sha256" or "sha512"
+//
+// Returns:
+//   - HashAlgorithm: the parsed algorithm
+//   - error: if s is not a supported algorithm
+func ParseHashAlgorithm(s string) (HashAlgorithm, error) {
+	switch HashAlgorithm(strings.ToLower(s)) {
+	case "", SHA256:
+		return SHA256, nil
+	case SHA512:
+		return SHA512, nil
+	}
+	if strings.HasPrefix(strings.ToLower(s), "blake2") {
+		return "", fmt.Errorf("unsupported hash algorithm %q: BLAKE2 is not available (expected sha256 or sha512)", s)
+	}
+	return "", fmt.Errorf("invalid hash algorithm %q (expected sha256 or sha512)", s)
+}
+
+// orDefault returns a, or SHA256 when a is empty.
+func (a HashAlgorithm) orDefault() HashAlgorithm {
+	if a == "" {
+		return SHA256
+	}
+	return a
+}
+
+// Validate reports whether a is a supported algorithm; the empty value
+// is SHA256.
+//
+// Example:
+//
+//	algo := checksum.HashAlgorithm(meta.HashAlgorithm)
+//	if err := algo.Validate(); err != nil {
+//	    return err
+//	}
+//
+// Returns:
+//   - error: if a is not empty, SHA256 or SHA512
+func (a HashAlgorithm) Validate() error {
+	switch a {
+	case "", SHA256, SHA512:
+		return nil
+	}
+	return fmt.Errorf("unsupported hash algorithm %q (expected sha256 or sha512)", string(a))
+}
+
+// New returns a new hash.Hash for a, SHA256 when a is empty.
+//
+// New panics for an unsupported algorithm rather than hash with another
+// one; ParseHashAlgorithm and Validate check a value first. The
+// ChecksumFile methods and ComputeFileHash do so and return an error.
+func (a HashAlgorithm) New() hash.Hash {
+	switch a.orDefault() {
+	case SHA256:
+		return sha256.New()
+	case SHA512:
+		return sha512.New()
+	}
+	panic(a.Validate())
+}
+
+// HexLen returns the length of a checksum in hex characters.
+func (a HashAlgorithm) HexLen() int {
+	return a.New().Size() * 2
+}
+
+// IsChecksum reports whether s is a lowercase hex checksum of algorithm a.
+//
+// Example:
+//
+//	if !checksum.SHA256.IsChecksum(arg) {
+//	    return fmt.Errorf("not a sha256 checksum: %s", arg)
+//	}
+func (a HashAlgorithm) IsChecksum(s string) bool {
+	if len(s) != a.HexLen() || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// manifestPrefix returns the upper-case manifest name prefix, e.g. SHA256SUM.
+func (a HashAlgorithm) manifestPrefix() string {
+	return strings.ToUpper(string(a.orDefault())) + "SUM"
+}
+
+// ComputeFileHash computes the checksum of a file with algo using
+// streaming I/O.
+//
+// Example:
+//
+//	sum, err := checksum.ComputeFileHash("/path/to/largefile.iso", checksum.SHA512)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - filePath: absolute or relative path to the file
+//   - algo: hash algorithm, SHA256 when empty
+//
+// Returns:
+//   - string: checksum as lowercase hex
+//   - error: if algo is not supported or the file cannot be opened or read
+func ComputeFileHash(filePath string, algo HashAlgorithm) (string, error) {
+	if err := algo.Validate(); err != nil {
+		return "", err
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", utils.WrapPathError(filePath, err)
+	}
+	defer file.Close()
+
+	h := algo.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
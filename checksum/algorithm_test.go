@@ -0,0 +1,131 @@
+package checksum
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestComputeFileHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "abc.txt")
+	if err := os.WriteFile(path, []byte("abc"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	tests := []struct {
+		algo HashAlgorithm
+		want string
+	}{
+		{SHA256, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{"", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{SHA512, "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a" +
+			"2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f"},
+	}
+	for _, tt := range tests {
+		got, err := ComputeFileHash(path, tt.algo)
+		if err != nil {
+			t.Fatalf("ComputeFileHash(%q) failed: %v", tt.algo, err)
+		}
+		if got != tt.want {
+			t.Errorf("ComputeFileHash(%q) = %s, want %s", tt.algo, got, tt.want)
+		}
+		if !tt.algo.IsChecksum(got) {
+			t.Errorf("%q.IsChecksum(%s) = false", tt.algo, got)
+		}
+	}
+
+	// The default keeps producing the historical SHA256 bundle checksum
+	sums := []string{"b", "a"}
+	if ComputeBundleHash(sums, SHA256) != ComputeBundleChecksum(sums) {
+		t.Errorf("ComputeBundleHash(SHA256) differs from ComputeBundleChecksum")
+	}
+	if got := ComputeBundleHash(sums, SHA512); len(got) != 128 {
+		t.Errorf("SHA512 bundle checksum has %d characters, want 128", len(got))
+	}
+}
+
+func TestChecksumFile_LoadProbesAlgorithm(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, ".bundle"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	files := &ChecksumFile{Algorithm: SHA512}
+	if err := files.Compute(dir); err != nil {
+		t.Fatalf("Compute failed: %v", err)
+	}
+	if err := files.Save(dir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Without a recorded algorithm the manifest name tells
+	loaded := &ChecksumFile{}
+	if err := loaded.Load(dir); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Algorithm != SHA512 {
+		t.Errorf("Algorithm = %q, want sha512", loaded.Algorithm)
+	}
+	corrupted, err := loaded.Verify(dir)
+	if err != nil || len(corrupted) != 0 {
+		t.Errorf("Verify = %v, %v; want no corruption", corrupted, err)
+	}
+
+	// A recorded algorithm without a matching manifest is not found
+	if err := (&ChecksumFile{Algorithm: SHA256}).Load(dir); !os.IsNotExist(err) {
+		t.Errorf("Load with SHA256: err = %v, want not-exist", err)
+	}
+}
+
+func TestParseHashAlgorithm(t *testing.T) {
+	for _, s := range []string{"", "sha256", "SHA256", "sha512"} {
+		if _, err := ParseHashAlgorithm(s); err != nil {
+			t.Errorf("ParseHashAlgorithm(%q) failed: %v", s, err)
+		}
+	}
+	if _, err := ParseHashAlgorithm("md5"); err == nil {
+		t.Errorf("ParseHashAlgorithm(md5) should fail")
+	}
+	if _, err := ParseHashAlgorithm("blake2b"); err == nil || !strings.Contains(err.Error(), "BLAKE2") {
+		t.Errorf("ParseHashAlgorithm(blake2b) error = %v, want BLAKE2 not available", err)
+	}
+}
+
+func TestHashAlgorithm_Unsupported(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, ".bundle"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	md5 := HashAlgorithm("md5")
+	if err := md5.Validate(); err == nil {
+		t.Error("Validate(md5) succeeded")
+	}
+	if _, err := ComputeFileHash(filepath.Join(dir, "a.txt"), md5); err == nil {
+		t.Error("ComputeFileHash(md5) succeeded, want error")
+	}
+	files := &ChecksumFile{Algorithm: md5}
+	if err := files.Compute(dir); err == nil {
+		t.Error("Compute with md5 succeeded, want error")
+	}
+	if err := files.Save(dir); err == nil {
+		t.Error("Save with md5 succeeded, want error")
+	}
+	if err := files.Load(dir); err == nil || os.IsNotExist(err) {
+		t.Errorf("Load with md5: err = %v, want unsupported algorithm", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("New(md5) did not panic")
+		}
+	}()
+	md5.New()
+}
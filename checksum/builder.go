@@ -0,0 +1,56 @@
+package checksum
+
+// BundleChecksumBuilder computes a bundle checksum incrementally.
+//
+// File checksums can be added one at a time as they are computed; Finalize
+// returns the same deterministic result as ComputeBundleChecksum for the same
+// set of checksums, regardless of the order in which they were added.
+//
+// Example:
+//
+//	builder := checksum.NewBundleChecksumBuilder()
+//	for _, path := range paths {
+//	    sum, err := checksum.ComputeFileSHA256(path)
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    builder.Add(sum)
+//	}
+//	bundleChecksum := builder.Finalize()
+//
+// Set Algorithm to combine checksums of another algorithm, e.g. SHA512.
+type BundleChecksumBuilder struct {
+	Algorithm HashAlgorithm // Algorithm of the bundle checksum (SHA256 when empty)
+
+	checksums []string
+}
+
+// NewBundleChecksumBuilder returns an empty BundleChecksumBuilder.
+func NewBundleChecksumBuilder() *BundleChecksumBuilder {
+	return &BundleChecksumBuilder{checksums: []string{}}
+}
+
+// Add records a file checksum.
+//
+// Parameters:
+//   - fileChecksum: checksum of a single file, computed with b.Algorithm
+func (b *BundleChecksumBuilder) Add(fileChecksum string) {
+	b.checksums = append(b.checksums, fileChecksum)
+}
+
+// Len returns the number of checksums added so far.
+func (b *BundleChecksumBuilder) Len() int {
+	return len(b.checksums)
+}
+
+// Finalize returns the bundle checksum of all checksums added so far, as
+// ComputeBundleHash computes it.
+//
+// The builder is not reset; more checksums may be added and Finalize called
+// again.
+//
+// Returns:
+//   - string: hash of sorted, concatenated checksums as lowercase hex
+func (b *BundleChecksumBuilder) Finalize() string {
+	return ComputeBundleHash(b.checksums, b.Algorithm)
+}
@@ -0,0 +1,193 @@
+package checksum
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+// Reasons reported in Corruption.Reason.
+const (
+	// ReasonMissing means the file no longer exists
+	ReasonMissing = "missing"
+
+	// ReasonSizeChanged means the file size differs from the manifest,
+	// typically truncation or an append
+	ReasonSizeChanged = "size-changed"
+
+	// ReasonContentChanged means the size matches but the content differs
+	ReasonContentChanged = "content-changed"
+
+	// ReasonChecksumMismatch means the checksum differs and the manifest
+	// records no size to compare against
+	ReasonChecksumMismatch = "checksum-mismatch"
+)
+
+// Corruption describes one file that failed verification.
+//
+// ExpectedSize is nil when the manifest does not record sizes (the text
+// format); ActualSize is nil when the file is missing.
+//
+// Example JSON:
+//
+//	{
+//	  "path": "photos/img001.jpg",
+//	  "reason": "size-changed",
+//	  "expected_size": 2048000,
+//	  "actual_size": 1048576,
+//	  "actual_checksum": "9f86d081..."
+//	}
+type Corruption struct {
+	Path           string `json:"path"`                      // Relative path from bundle root
+	Reason         string `json:"reason"`                    // One of the Reason* constants
+	ExpectedSize   *int64 `json:"expected_size"`             // Size recorded in the manifest
+	ActualSize     *int64 `json:"actual_size"`               // Size on disk
+	ActualChecksum string `json:"actual_checksum,omitempty"` // Checksum on disk, empty when missing
+}
+
+// newCorruption classifies a failed record.
+func newCorruption(record ChecksumRecord, sizesKnown, missing bool, actual int64, actualSum string) Corruption {
+	c := Corruption{Path: record.FilePath, ActualChecksum: actualSum}
+	if sizesKnown {
+		expected := record.Size
+		c.ExpectedSize = &expected
+	}
+
+	switch {
+	case missing:
+		c.Reason = ReasonMissing
+		return c
+	case c.ExpectedSize == nil:
+		c.Reason = ReasonChecksumMismatch
+	case *c.ExpectedSize != actual:
+		c.Reason = ReasonSizeChanged
+	default:
+		c.Reason = ReasonContentChanged
+	}
+	c.ActualSize = &actual
+	return c
+}
+
+// Recomputed returns the manifest of the tracked files as found on disk by
+// a verification.
+//
+// Records are copied from cf with the checksum and size of each corrupted
+// file replaced by the recomputed values; missing files are left out.
+// Files that are on disk but not in cf are not included, so the bundle
+// checksum of the result says whether the tracked content still matches;
+// use WithUntracked to add them for a manifest of everything on disk. The
+// stored manifest is not touched.
+//
+// Example:
+//
+//	corrupted, _ := files.VerifyCorruptions("/path/to/bundle", nil)
+//	current := files.Recomputed(corrupted)
+//	current.Export("/tmp/current.sha256")
+//
+// Parameters:
+//   - corrupted: the result of VerifyCorruptions for cf
+//
+// Returns:
+//   - *ChecksumFile: manifest of the files on disk
+func (cf *ChecksumFile) Recomputed(corrupted []Corruption) *ChecksumFile {
+	byPath := make(map[string]Corruption, len(corrupted))
+	for _, c := range corrupted {
+		byPath[c.Path] = c
+	}
+
+	current := &ChecksumFile{Records: make([]ChecksumRecord, 0, len(cf.Records)), Algorithm: cf.Algorithm}
+	for _, record := range cf.Records {
+		if c, ok := byPath[record.FilePath]; ok {
+			if c.Reason == ReasonMissing {
+				continue
+			}
+			record.Checksum = c.ActualChecksum
+			if c.ActualSize != nil {
+				record.Size = *c.ActualSize
+			}
+		}
+		current.Records = append(current.Records, record)
+		current.TotalSize += record.Size
+	}
+	return current
+}
+
+// WithUntracked returns a copy of cf with records for the given untracked
+// files appended.
+//
+// Each file is hashed with the algorithm of cf. Together with Recomputed
+// this gives a manifest of all data on disk, e.g. for verify
+// --emit-manifest.
+//
+// Example:
+//
+//	result, _ := files.VerifyDetailed("/path/to/bundle")
+//	current, err := files.Recomputed(result.Corrupted).WithUntracked("/path/to/bundle", result.Extra)
+//
+// Parameters:
+//   - bundlePath: path to the bundle directory
+//   - untracked: paths relative to bundlePath, as in VerifyResult.Extra
+//
+// Returns:
+//   - *ChecksumFile: cf with the untracked files added
+//   - error: if an untracked file cannot be read
+func (cf *ChecksumFile) WithUntracked(bundlePath string, untracked []string) (*ChecksumFile, error) {
+	current := &ChecksumFile{Records: make([]ChecksumRecord, len(cf.Records), len(cf.Records)+len(untracked)), Algorithm: cf.Algorithm, TotalSize: cf.TotalSize}
+	copy(current.Records, cf.Records)
+	for _, rel := range untracked {
+		path := filepath.Join(bundlePath, filepath.FromSlash(rel))
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, utils.WrapPathError(path, err)
+		}
+		sum, err := ComputeFileHash(path, cf.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		current.Records = append(current.Records, ChecksumRecord{
+			Checksum: sum,
+			FilePath: rel,
+			Size:     info.Size(),
+			ModTime:  info.ModTime().UTC(),
+		})
+		current.TotalSize += info.Size()
+	}
+	return current, nil
+}
+
+// Export writes the records to path in sha256sum(1) format (sha512sum(1)
+// for SHA512 bundles; the line format is the same).
+//
+// Unlike Save it writes an arbitrary file rather than the bundle manifest,
+// always as uncompressed text. Records are sorted by checksum like the
+// stored manifest so the two can be compared with diff.
+//
+// Example:
+//
+//	err := files.Export("/tmp/current.sha256")
+//	// sha256sum -c /tmp/current.sha256 (from the bundle directory)
+//
+// Parameters:
+//   - path: file to create or overwrite
+//
+// Returns:
+//   - error: if the file cannot be written
+func (cf *ChecksumFile) Export(path string) error {
+	records := make([]ChecksumRecord, len(cf.Records))
+	copy(records, cf.Records)
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Checksum < records[j].Checksum
+	})
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := FormatText.encode(file, records); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
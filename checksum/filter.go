@@ -0,0 +1,78 @@
+package checksum
+
+import (
+	"fmt"
+	"time"
+)
+
+// filterDateLayouts are the accepted ParseFilterDate formats, most specific first.
+var filterDateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+// ModTimeFilter restricts a scan to files modified within a time window.
+//
+// After is inclusive and Before exclusive; a zero bound is unbounded, so the
+// zero ModTimeFilter matches every file. Only regular files are filtered;
+// directories are always descended into.
+//
+// Example:
+//
+//	after, _ := checksum.ParseFilterDate("2024-01-01")
+//	before, _ := checksum.ParseFilterDate("2024-06-01")
+//	files := &checksum.ChecksumFile{Filter: checksum.ModTimeFilter{After: after, Before: before}}
+//	files.Compute("/path/to/files") // only files modified in H1 2024
+type ModTimeFilter struct {
+	After  time.Time // Include files modified at or after this time
+	Before time.Time // Include files modified before this time
+}
+
+// IsZero reports whether f matches every file.
+func (f ModTimeFilter) IsZero() bool {
+	return f.After.IsZero() && f.Before.IsZero()
+}
+
+// Match reports whether a file modified at modTime is inside the window.
+func (f ModTimeFilter) Match(modTime time.Time) bool {
+	if !f.After.IsZero() && modTime.Before(f.After) {
+		return false
+	}
+	if !f.Before.IsZero() && !modTime.Before(f.Before) {
+		return false
+	}
+	return true
+}
+
+// Validate checks that the window is not empty.
+//
+// Returns:
+//   - error: if Before is not after After
+func (f ModTimeFilter) Validate() error {
+	if !f.After.IsZero() && !f.Before.IsZero() && !f.Before.After(f.After) {
+		return fmt.Errorf("modified-before (%s) must be later than modified-after (%s)",
+			f.Before.Format(time.RFC3339), f.After.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ParseFilterDate parses a ModTimeFilter bound.
+//
+// Accepted formats are a date (2024-01-01, midnight local time), a local
+// date and time (2024-01-01T12:00:00) and RFC 3339 (2024-01-01T12:00:00Z).
+//
+// Example:
+//
+//	after, err := checksum.ParseFilterDate("2024-01-01")
+//
+// Parameters:
+//   - s: the date string
+//
+// Returns:
+//   - time.Time: the parsed time
+//   - error: if s is in none of the accepted formats
+func ParseFilterDate(s string) (time.Time, error) {
+	for _, layout := range filterDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD, YYYY-MM-DDTHH:MM:SS or RFC 3339)", s)
+}
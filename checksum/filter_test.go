@@ -0,0 +1,81 @@
+package checksum
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestModTimeFilter_Match(t *testing.T) {
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	filter := ModTimeFilter{After: after, Before: before}
+
+	tests := []struct {
+		name    string
+		modTime time.Time
+		want    bool
+	}{
+		{"before window", after.Add(-time.Second), false},
+		{"at lower bound", after, true},
+		{"inside", after.Add(24 * time.Hour), true},
+		{"at upper bound", before, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter.Match(tt.modTime); got != tt.want {
+				t.Errorf("Match(%v) = %v, want %v", tt.modTime, got, tt.want)
+			}
+		})
+	}
+
+	if !(ModTimeFilter{}).Match(time.Time{}) {
+		t.Error("zero filter should match everything")
+	}
+	if err := (ModTimeFilter{After: before, Before: after}).Validate(); err == nil {
+		t.Error("Validate() expected error for an empty window")
+	}
+}
+
+func TestParseFilterDate(t *testing.T) {
+	for _, s := range []string{"2024-01-01", "2024-01-01T12:30:00", "2024-01-01T12:30:00Z", "2024-01-01T12:30:00+02:00"} {
+		if _, err := ParseFilterDate(s); err != nil {
+			t.Errorf("ParseFilterDate(%q) error = %v", s, err)
+		}
+	}
+	for _, s := range []string{"", "2024-13-01", "01/02/2024", "yesterday"} {
+		if _, err := ParseFilterDate(s); err == nil {
+			t.Errorf("ParseFilterDate(%q) expected error", s)
+		}
+	}
+}
+
+func TestChecksumFile_ComputeFiltered(t *testing.T) {
+	tmpDir := t.TempDir()
+	window := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for name, modTime := range map[string]time.Time{
+		"old.txt":    window.AddDate(-1, 0, 0),
+		"inside.txt": window,
+		"new.txt":    window.AddDate(1, 0, 0),
+	} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("failed to set mtime: %v", err)
+		}
+	}
+
+	cf := &ChecksumFile{Filter: ModTimeFilter{After: window.AddDate(0, -1, 0), Before: window.AddDate(0, 1, 0)}}
+	if err := cf.Compute(tmpDir); err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+	if len(cf.Records) != 1 || cf.Records[0].FilePath != "inside.txt" {
+		t.Errorf("Compute() records = %v, want only inside.txt", cf.Records)
+	}
+	if cf.TotalSize != int64(len("inside.txt")) {
+		t.Errorf("TotalSize = %d, want %d", cf.TotalSize, len("inside.txt"))
+	}
+}
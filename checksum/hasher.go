@@ -0,0 +1,100 @@
+package checksum
+
+import (
+	"encoding/hex"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+// hashChunkSize is the number of bytes read before a hash slot is taken.
+const hashChunkSize = 1 << 20
+
+// fileHasher computes file checksums with reading and hashing bounded
+// separately.
+//
+// Callers read files from utils.IOConcurrency goroutines; only the hashing
+// of each chunk takes one of the hash slots. On high-latency storage (NFS,
+// SMB) many files can then wait on I/O at once while CPU hashing stays
+// bounded. With equal limits the slots never block and this behaves like
+// ComputeFileHash.
+type fileHasher struct {
+	algo  HashAlgorithm
+	slots chan struct{}
+	open  func(path string) (io.ReadCloser, error)
+}
+
+// newFileHasher returns a fileHasher for algo allowing hashers concurrent
+// hash operations (values < 1 mean 1).
+func newFileHasher(algo HashAlgorithm, hashers int) *fileHasher {
+	if hashers < 1 {
+		hashers = 1
+	}
+	return &fileHasher{
+		algo:  algo,
+		slots: make(chan struct{}, hashers),
+		open: func(path string) (io.ReadCloser, error) {
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, utils.WrapPathError(path, err)
+			}
+			return file, nil
+		},
+	}
+}
+
+// sum returns the checksum of the file at path as lowercase hex.
+func (h *fileHasher) sum(path string) (string, error) {
+	file, err := h.open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := h.algo.New()
+	buf := make([]byte, hashChunkSize)
+	for {
+		n, err := io.ReadFull(file, buf)
+		if n > 0 {
+			h.slots <- struct{}{}
+			hash.Write(buf[:n])
+			<-h.slots
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// progressReporter counts finished files and serializes calls to a
+// ProgressFunc from concurrent workers.
+type progressReporter struct {
+	mu       sync.Mutex
+	finished int
+	total    int
+	cb       ProgressFunc
+}
+
+// newProgressReporter returns a reporter for total files. A nil cb is
+// replaced by a no-op so workers never call a nil function.
+func newProgressReporter(total int, cb ProgressFunc) *progressReporter {
+	if cb == nil {
+		cb = func(int, int, string) {}
+	}
+	return &progressReporter{total: total, cb: cb}
+}
+
+// done records that relPath has finished and reports the new count.
+func (r *progressReporter) done(relPath string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finished++
+	r.cb(r.finished, r.total, relPath)
+}
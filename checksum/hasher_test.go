@@ -0,0 +1,81 @@
+package checksum
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+func TestFileHasher_MatchesComputeFileSHA256(t *testing.T) {
+	dir := t.TempDir()
+	sizes := []int{0, 1, hashChunkSize - 1, hashChunkSize, hashChunkSize*2 + 7}
+	h := newFileHasher(SHA256, 1)
+	for _, size := range sizes {
+		path := filepath.Join(dir, fmt.Sprintf("f%d", size))
+		if err := os.WriteFile(path, bytes.Repeat([]byte("x"), size), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		want, err := ComputeFileSHA256(path)
+		if err != nil {
+			t.Fatalf("ComputeFileSHA256 failed: %v", err)
+		}
+		got, err := h.sum(path)
+		if err != nil {
+			t.Fatalf("sum failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("size %d: sum = %s, want %s", size, got, want)
+		}
+	}
+
+	if _, err := h.sum(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("sum of missing file: err = %v, want not-exist", err)
+	}
+}
+
+// slowReader simulates a high-latency mount by sleeping before every read.
+type slowReader struct {
+	io.Reader
+	latency time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	time.Sleep(r.latency)
+	return r.Reader.Read(p)
+}
+
+func (r *slowReader) Close() error { return nil }
+
+// BenchmarkFileHasher_Latency hashes 64 small files behind 5ms of simulated
+// read latency with 4 hashers, reading 4 or 32 files at once. On such storage
+// the time per run drops roughly with the number of concurrent readers:
+//
+//	go test ./checksum -run '^$' -bench FileHasher_Latency
+func BenchmarkFileHasher_Latency(b *testing.B) {
+	const files = 64
+	data := bytes.Repeat([]byte("x"), 64<<10)
+
+	for _, readers := range []int{4, 32} {
+		b.Run(fmt.Sprintf("io=%d/hash=4", readers), func(b *testing.B) {
+			h := newFileHasher(SHA256, 4)
+			h.open = func(string) (io.ReadCloser, error) {
+				return &slowReader{Reader: bytes.NewReader(data), latency: 5 * time.Millisecond}, nil
+			}
+			for n := 0; n < b.N; n++ {
+				err := utils.ParallelFor(files, readers, func(i int) error {
+					_, err := h.sum("")
+					return err
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package checksum
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// indexName is the file in .bundle/ recording the size and modification
+// time each manifest record was hashed at.
+const indexName = "INDEX.json"
+
+// statIndex is the content of .bundle/INDEX.json.
+//
+// It lives beside the manifest so SHA256SUM.txt keeps its sha256sum(1)
+// format. The index is tied to one manifest through its digest and ignored
+// when the manifest changed without it, so a stale index can never cause
+// an old checksum to be reused.
+type statIndex struct {
+	ManifestDigest string                `json:"manifest_digest"` // Digest of the manifest records
+	Files          map[string]indexEntry `json:"files"`           // Stat data by relative path
+}
+
+// indexEntry is the stat data of one file when it was hashed.
+type indexEntry struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// saveIndex writes .bundle/INDEX.json for cf.Records. Nothing is written
+// when no record carries a modification time (e.g. records read from a
+// manifest without index); an existing index then no longer matches the
+// manifest digest and is ignored.
+func (cf *ChecksumFile) saveIndex(bundlePath string) error {
+	index := statIndex{
+		ManifestDigest: manifestDigest(cf.Records),
+		Files:          make(map[string]indexEntry, len(cf.Records)),
+	}
+	for _, record := range cf.Records {
+		if record.ModTime.IsZero() {
+			continue
+		}
+		index.Files[normalizeRelPath(record.FilePath)] = indexEntry{Size: record.Size, ModTime: record.ModTime}
+	}
+	if len(index.Files) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(bundlePath, ".bundle", indexName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// loadIndex fills Size and ModTime of cf.Records from .bundle/INDEX.json.
+// A missing or unreadable index, or one written for another manifest, is
+// ignored: records then have no modification time and Update rehashes
+// them. Sizes from the index do not make the manifest sizes known for
+// verification; they are only used to detect changed files.
+func (cf *ChecksumFile) loadIndex(bundlePath string) {
+	data, err := os.ReadFile(filepath.Join(bundlePath, ".bundle", indexName))
+	if err != nil {
+		return
+	}
+	var index statIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return
+	}
+	if index.ManifestDigest != manifestDigest(cf.Records) {
+		return
+	}
+	for i, record := range cf.Records {
+		if entry, ok := index.Files[normalizeRelPath(record.FilePath)]; ok {
+			cf.Records[i].ModTime = entry.ModTime
+			if !cf.sizesKnown {
+				cf.Records[i].Size = entry.Size
+			}
+		}
+	}
+}
+
+// Update brings the checksums up to date with the files in bundlePath,
+// rehashing only what changed.
+//
+// The existing manifest, in any format, and .bundle/INDEX.json are loaded
+// and the directory is scanned as Compute does; without a manifest every
+// file is hashed. A file keeps its recorded checksum when its
+// size and modification time equal those recorded at its last hash; new
+// and changed files are hashed, and records of deleted files are dropped.
+// Files without index data (bundles created before the index existed) are
+// rehashed. The result is not saved; call Save to write the manifest and
+// index.
+//
+// Example:
+//
+//	files := &checksum.ChecksumFile{}
+//	rehashed, err := files.Update("/path/to/bundle")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("%d files rehashed\n", len(rehashed))
+//	err = files.Save("/path/to/bundle")
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//
+// Returns:
+//   - []string: relative paths of the files that were hashed, sorted
+//   - error: if the manifest cannot be read or a file cannot be hashed
+func (cf *ChecksumFile) Update(bundlePath string) ([]string, error) {
+	return cf.update(bundlePath, true, nil)
+}
+
+// UpdateWithProgress is like Update but calls cb for every file that is
+// hashed; cb may be nil.
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//   - cb: progress callback, or nil
+//
+// Returns:
+//   - []string: relative paths of the files that were hashed, sorted
+//   - error: if the manifest cannot be read or a file cannot be hashed
+func (cf *ChecksumFile) UpdateWithProgress(bundlePath string, cb ProgressFunc) ([]string, error) {
+	return cf.update(bundlePath, true, cb)
+}
+
+// UpdateTolerantWithProgress is like Update but skips unreadable paths as
+// ComputeTolerant does and calls cb for every file that is hashed; cb may
+// be nil.
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//   - cb: progress callback, or nil
+//
+// Returns:
+//   - []string: relative paths of the files that were hashed, sorted
+//   - error: if the manifest cannot be read or the root cannot be walked
+func (cf *ChecksumFile) UpdateTolerantWithProgress(bundlePath string, cb ProgressFunc) ([]string, error) {
+	return cf.update(bundlePath, false, cb)
+}
+
+// update implements Update and its variants.
+func (cf *ChecksumFile) update(bundlePath string, strict bool, cb ProgressFunc) ([]string, error) {
+	previous := &ChecksumFile{}
+	if err := previous.Load(bundlePath); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if cf.Algorithm == "" {
+		cf.Algorithm = previous.Algorithm
+	}
+
+	known := make(map[string]ChecksumRecord, len(previous.Records))
+	if previous.Algorithm.orDefault() == cf.Algorithm.orDefault() {
+		for _, record := range previous.Records {
+			if !record.ModTime.IsZero() {
+				known[normalizeRelPath(record.FilePath)] = record
+			}
+		}
+	}
+
+	rehashed, err := cf.compute(bundlePath, strict, cb, known)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(rehashed)
+	return rehashed, nil
+}
@@ -0,0 +1,94 @@
+package checksum
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestChecksumFile_Update(t *testing.T) {
+	tmpDir, cf := setupProgressBundle(t)
+	if err := cf.Save(tmpDir); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".bundle", indexName)); err != nil {
+		t.Fatalf("index not written: %v", err)
+	}
+
+	// Nothing changed: nothing is rehashed
+	unchanged := &ChecksumFile{}
+	rehashed, err := unchanged.Update(tmpDir)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if len(rehashed) != 0 {
+		t.Errorf("Update() rehashed %v, want none", rehashed)
+	}
+	if len(unchanged.Records) != 2 {
+		t.Errorf("Update() records = %d, want 2", len(unchanged.Records))
+	}
+
+	// Change b.txt, add c.txt and remove a.txt
+	bPath := filepath.Join(tmpDir, "b.txt")
+	if err := os.WriteFile(bPath, []byte("B"), 0644); err != nil {
+		t.Fatalf("failed to modify test file: %v", err)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(bPath, later, later); err != nil {
+		t.Fatalf("failed to set mtime: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "c.txt"), []byte("c"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := os.Remove(filepath.Join(tmpDir, "a.txt")); err != nil {
+		t.Fatalf("failed to remove test file: %v", err)
+	}
+
+	updated := &ChecksumFile{}
+	rehashed, err = updated.Update(tmpDir)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if want := []string{"b.txt", "c.txt"}; !reflect.DeepEqual(rehashed, want) {
+		t.Errorf("Update() rehashed %v, want %v", rehashed, want)
+	}
+
+	full := &ChecksumFile{}
+	if err := full.Compute(tmpDir); err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+	for _, record := range full.Records {
+		if sum, ok := updated.Lookup(record.FilePath); !ok || sum != record.Checksum {
+			t.Errorf("Update() %s = %q, want %q", record.FilePath, sum, record.Checksum)
+		}
+	}
+	if len(updated.Records) != len(full.Records) {
+		t.Errorf("Update() records = %d, want %d", len(updated.Records), len(full.Records))
+	}
+}
+
+func TestChecksumFile_UpdateStaleIndex(t *testing.T) {
+	tmpDir, cf := setupProgressBundle(t)
+	if err := cf.Save(tmpDir); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// A manifest rewritten without the index must not reuse old checksums
+	cf.Records[0].ModTime = time.Time{}
+	cf.Records[1].ModTime = time.Time{}
+	cf.Records[0].Checksum = cf.Records[1].Checksum
+	if err := cf.Save(tmpDir); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	updated := &ChecksumFile{}
+	rehashed, err := updated.Update(tmpDir)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if len(rehashed) != 2 {
+		t.Errorf("Update() rehashed %v, want all files", rehashed)
+	}
+}
@@ -0,0 +1,105 @@
+package checksum
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// pathIndex maps normalized relative paths to checksums for Lookup.
+//
+// It remembers the Records slice it was built from so a reassigned or
+// resized slice is detected and the index rebuilt.
+type pathIndex struct {
+	sums  map[string]string
+	first *ChecksumRecord
+	count int
+}
+
+// stale reports whether the index no longer matches records.
+func (idx *pathIndex) stale(records []ChecksumRecord) bool {
+	if idx == nil || idx.count != len(records) {
+		return true
+	}
+	return len(records) > 0 && idx.first != &records[0]
+}
+
+// normalizeRelPath converts a relative path to the form used as index key.
+//
+// Separators are converted to forward slashes, a leading "./" is dropped
+// and duplicate or trailing separators are cleaned up.
+func normalizeRelPath(relPath string) string {
+	p := path.Clean(filepath.ToSlash(relPath))
+	return strings.TrimPrefix(p, "./")
+}
+
+// Lookup returns the stored checksum for a file in the bundle.
+//
+// The first call builds a map from path to checksum so later lookups are
+// O(1) instead of scanning Records. The map is rebuilt automatically when
+// Records is reassigned or changes length (Load, Compute, append); call
+// Reindex after editing records in place.
+//
+// Example:
+//
+//	files := &checksum.ChecksumFile{}
+//	files.Load("/path/to/bundle")
+//	if sum, ok := files.Lookup("docs/report.pdf"); ok {
+//	    fmt.Println(sum)
+//	}
+//
+// Parameters:
+//   - relPath: path relative to the bundle root; "./" prefixes and
+//     duplicate separators are accepted
+//
+// Returns:
+//   - string: the SHA256 checksum (64 hex characters)
+//   - bool: false if the path is not in the manifest
+func (cf *ChecksumFile) Lookup(relPath string) (string, bool) {
+	if cf.index.stale(cf.Records) {
+		cf.Reindex()
+	}
+	sum, ok := cf.index.sums[normalizeRelPath(relPath)]
+	return sum, ok
+}
+
+// Reindex rebuilds the path index used by Lookup from Records.
+func (cf *ChecksumFile) Reindex() {
+	idx := &pathIndex{
+		sums:  make(map[string]string, len(cf.Records)),
+		count: len(cf.Records),
+	}
+	if len(cf.Records) > 0 {
+		idx.first = &cf.Records[0]
+	}
+	for _, record := range cf.Records {
+		idx.sums[normalizeRelPath(record.FilePath)] = record.Checksum
+	}
+	cf.index = idx
+}
+
+// Remove drops the record of a file from the manifest.
+//
+// Example:
+//
+//	if record, ok := files.Remove("docs/old.pdf"); ok {
+//	    fmt.Printf("Removed %s\n", record.Checksum)
+//	}
+//
+// Parameters:
+//   - relPath: path relative to the bundle root, as for Lookup
+//
+// Returns:
+//   - ChecksumRecord: the removed record
+//   - bool: false if the path is not in the manifest
+func (cf *ChecksumFile) Remove(relPath string) (ChecksumRecord, bool) {
+	key := normalizeRelPath(relPath)
+	for i, record := range cf.Records {
+		if normalizeRelPath(record.FilePath) == key {
+			cf.Records = append(cf.Records[:i], cf.Records[i+1:]...)
+			cf.index = nil
+			return record, true
+		}
+	}
+	return ChecksumRecord{}, false
+}
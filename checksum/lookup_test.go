@@ -0,0 +1,91 @@
+package checksum
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestChecksumFile_Lookup(t *testing.T) {
+	cf := &ChecksumFile{
+		Records: []ChecksumRecord{
+			{Checksum: "aaa", FilePath: "file1.txt"},
+			{Checksum: "bbb", FilePath: filepath.Join("dir", "file2.txt")},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{"plain", "file1.txt", "aaa", true},
+		{"nested", "dir/file2.txt", "bbb", true},
+		{"dot prefix", "./dir/file2.txt", "bbb", true},
+		{"double separator", "dir//file2.txt", "bbb", true},
+		{"os separator", filepath.Join("dir", "file2.txt"), "bbb", true},
+		{"absent", "missing.txt", "", false},
+		{"directory", "dir", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := cf.Lookup(tt.path)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Lookup(%q) = %q, %v; want %q, %v", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestChecksumFile_LookupRebuildsIndex(t *testing.T) {
+	cf := &ChecksumFile{Records: []ChecksumRecord{{Checksum: "aaa", FilePath: "a.txt"}}}
+	if _, ok := cf.Lookup("a.txt"); !ok {
+		t.Fatalf("Lookup(a.txt) not found")
+	}
+
+	// Appending changes the length
+	cf.Records = append(cf.Records, ChecksumRecord{Checksum: "bbb", FilePath: "b.txt"})
+	if sum, ok := cf.Lookup("b.txt"); !ok || sum != "bbb" {
+		t.Errorf("Lookup(b.txt) after append = %q, %v", sum, ok)
+	}
+
+	// Reassigning a slice of the same length
+	cf.Records = []ChecksumRecord{{Checksum: "ccc", FilePath: "c.txt"}, {Checksum: "ddd", FilePath: "d.txt"}}
+	if _, ok := cf.Lookup("a.txt"); ok {
+		t.Errorf("Lookup(a.txt) should fail after Records was replaced")
+	}
+	if sum, ok := cf.Lookup("c.txt"); !ok || sum != "ccc" {
+		t.Errorf("Lookup(c.txt) = %q, %v", sum, ok)
+	}
+
+	// In-place edits need an explicit Reindex
+	cf.Records[0].Checksum = "eee"
+	cf.Reindex()
+	if sum, _ := cf.Lookup("c.txt"); sum != "eee" {
+		t.Errorf("Lookup(c.txt) after Reindex = %q, want eee", sum)
+	}
+}
+
+func TestChecksumFile_Remove(t *testing.T) {
+	cf := &ChecksumFile{Records: []ChecksumRecord{
+		{Checksum: "aaa", FilePath: "a.txt"},
+		{Checksum: "bbb", FilePath: "./dir/b.txt"},
+	}}
+	if _, ok := cf.Lookup("dir/b.txt"); !ok {
+		t.Fatalf("Lookup(dir/b.txt) not found")
+	}
+
+	record, ok := cf.Remove("dir//b.txt")
+	if !ok || record.Checksum != "bbb" {
+		t.Errorf("Remove(dir//b.txt) = %+v, %v, want the b.txt record", record, ok)
+	}
+	if _, ok := cf.Lookup("dir/b.txt"); ok {
+		t.Error("Lookup(dir/b.txt) still found after Remove")
+	}
+	if len(cf.Records) != 1 || cf.Records[0].FilePath != "a.txt" {
+		t.Errorf("Records = %+v, want only a.txt", cf.Records)
+	}
+	if _, ok := cf.Remove("missing.txt"); ok {
+		t.Error("Remove(missing.txt) = true, want false")
+	}
+}
@@ -28,14 +28,20 @@
 package checksum
 
 import (
-	"bufio"
-	"crypto/sha256"
+	"compress/gzip"
+	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
+
+	"github.com/jvzantvoort/bundle/scanner"
+	"github.com/jvzantvoort/bundle/utils"
 )
 
 // ChecksumRecord represents a single file checksum entry.
@@ -51,8 +57,11 @@ import (
 //	    FilePath: "documents/report.pdf",
 //	}
 type ChecksumRecord struct {
-	Checksum string // SHA256 hash (64 hex characters)
-	FilePath string // Relative path from bundle root
+	Checksum string `json:"checksum"`       // SHA256 hash (64 hex characters)
+	FilePath string `json:"path"`           // Relative path from bundle root
+	Size     int64  `json:"size,omitempty"` // File size in bytes (not stored in text format)
+
+	ModTime time.Time `json:"-"` // Modification time when hashed, kept in .bundle/INDEX.json
 }
 
 // ChecksumFile represents the entire SHA256SUM.txt file.
@@ -70,7 +79,34 @@ type ChecksumRecord struct {
 //	}
 type ChecksumFile struct {
 	Records   []ChecksumRecord
-	TotalSize int64 // Total size of all files in bytes
+	TotalSize int64            // Total size of all files in bytes
+	Errors    []ScanError      // Paths skipped by ComputeTolerant
+	Compress  bool             // Save gzip-compressed with a .gz suffix
+	Format    ManifestFormat   // Manifest serialization (text when empty)
+	Order     ScanOrder        // Record order produced by Compute (walk when empty)
+	Filter    ModTimeFilter    // Files Compute includes by modification time (all when zero)
+	Exclude   scanner.Excludes // Gitignore-style patterns of paths Compute leaves out, besides those in .bundleignore
+	Algorithm HashAlgorithm    // Hash of all checksums (SHA256 when empty; set by Load)
+
+	index      *pathIndex // Built lazily by Lookup
+	sizesKnown bool       // Record sizes are valid (computed or JSON manifest)
+}
+
+// ScanError records a path that could not be read during a tolerant scan.
+//
+// Example:
+//
+//	for _, e := range files.Errors {
+//	    fmt.Printf("skipped %s: %v\n", e.Path, e.Err)
+//	}
+type ScanError struct {
+	Path string // Relative path from bundle root
+	Err  error  // Underlying error
+}
+
+// Error implements the error interface.
+func (e ScanError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
 }
 
 // ComputeBundleChecksum generates a deterministic bundle checksum from file checksums.
@@ -99,28 +135,55 @@ type ChecksumFile struct {
 // Returns:
 //   - string: SHA256 hash of sorted, concatenated checksums (64 hex characters)
 func ComputeBundleChecksum(checksums []string) string {
+	return ComputeBundleHash(checksums, SHA256)
+}
+
+// ComputeBundleHash is ComputeBundleChecksum with a selectable algorithm.
+//
+// The file checksums are sorted and joined as for ComputeBundleChecksum and
+// the result is hashed with algo, so a SHA512 bundle has a 128 character
+// bundle checksum.
+//
+// Example:
+//
+//	bundleChecksum := checksum.ComputeBundleHash(checksums, checksum.SHA512)
+//
+// Parameters:
+//   - checksums: file checksums, all computed with algo
+//   - algo: hash algorithm, SHA256 when empty
+//
+// Returns:
+//   - string: hash of the sorted, concatenated checksums as lowercase hex
+func ComputeBundleHash(checksums []string, algo HashAlgorithm) string {
 	// Sort checksums for determinism
 	sorted := make([]string, len(checksums))
 	copy(sorted, checksums)
 	sort.Strings(sorted)
 
-	// Concatenate with Unix newlines
-	combined := strings.Join(sorted, "\n")
-
-	// Compute SHA256 of concatenated string
-	hash := sha256.Sum256([]byte(combined))
-	return hex.EncodeToString(hash[:])
+	// Hash the checksums joined with Unix newlines
+	h := algo.New()
+	h.Write([]byte(strings.Join(sorted, "\n")))
+	return hex.EncodeToString(h.Sum(nil))
 }
 
-// Load reads SHA256SUM.txt and parses checksum records.
+// Load reads the checksum manifest and parses checksum records.
 //
-// The file format is compatible with sha256sum(1):
+// The default text format is compatible with sha256sum(1):
 //
 //	<checksum>  ./<relative_path>
 //
 // Each line contains a 64-character hex checksum, two spaces, and a file path.
 // Paths are relative to the bundle root and prefixed with "./".
 //
+// When cf.Format is set only that format is read; otherwise SHA256SUM.txt
+// and SHA256SUM.json are probed in that order. Likewise cf.Algorithm selects
+// the manifest name (SHA512SUM.txt for SHA512); when empty SHA256 is probed
+// first, then SHA512, and cf.Algorithm is set from the manifest found so
+// older bundles without a recorded algorithm load unchanged. A
+// gzip-compressed manifest (*.gz) is decompressed transparently. cf.Format
+// and cf.Compress are set from what was found so a later Save keeps the
+// same layout.
+//
 // Example SHA256SUM.txt:
 //
 //	e3b0c44...  ./file1.txt
@@ -139,34 +202,57 @@ func ComputeBundleChecksum(checksums []string) string {
 //   - bundlePath: absolute or relative path to the bundle directory
 //
 // Returns:
-//   - error: if .bundle/SHA256SUM.txt cannot be read or parsed
+//   - error: if cf.Algorithm is not supported or no manifest can be read
+//     or parsed
 func (cf *ChecksumFile) Load(bundlePath string) error {
-	sumFile := filepath.Join(bundlePath, ".bundle", "SHA256SUM.txt")
-	file, err := os.Open(sumFile)
+	file, name, err := cf.openManifest(bundlePath)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	cf.Records = []ChecksumRecord{}
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		parts := strings.Fields(line)
-		if len(parts) >= 2 {
-			cf.Records = append(cf.Records, ChecksumRecord{
-				Checksum: parts[0],
-				FilePath: strings.TrimPrefix(parts[1], "./"),
-			})
+	var reader io.Reader = file
+	if name.compressed {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("failed to decompress %s: %w", name, err)
 		}
+		defer gz.Close()
+		reader = gz
 	}
-	return scanner.Err()
+	cf.Format = name.format
+	cf.Compress = name.compressed
+	cf.Algorithm = name.algo
+
+	records, err := name.format.decode(reader)
+	if err != nil {
+		return err
+	}
+	cf.Records = records
+	cf.sizesKnown = name.format == FormatJSON
+	cf.loadIndex(bundlePath)
+	return nil
+}
+
+// FileName returns the name of the manifest in .bundle/ that Save writes,
+// e.g. "SHA256SUM.txt", given cf.Format, cf.Algorithm and cf.Compress.
+func (cf *ChecksumFile) FileName() string {
+	format := cf.Format
+	if format == "" {
+		format = FormatText
+	}
+	return format.fileName(cf.Algorithm, cf.Compress)
 }
 
-// Save writes checksums to SHA256SUM.txt in sorted order.
+// Save writes checksums to the manifest in sorted order.
+//
+// Records are sorted by checksum for deterministic output. The manifest is
+// written in cf.Format (text when empty); the text format is compatible
+// with sha256sum(1) for verification.
 //
-// Records are sorted by checksum for deterministic output. The file format
-// is compatible with sha256sum(1) for verification.
+// When cf.Compress is set the manifest is written gzip-compressed with a
+// .gz suffix. Any manifest in another format or compression is removed so
+// only one manifest exists. Neither choice affects the bundle checksum.
 //
 // Example:
 //
@@ -185,9 +271,18 @@ func (cf *ChecksumFile) Load(bundlePath string) error {
 //   - bundlePath: absolute or relative path to the bundle directory
 //
 // Returns:
-//   - error: if .bundle/SHA256SUM.txt cannot be created or written
+//   - error: if cf.Algorithm is not supported or the manifest cannot be
+//     created or written
 func (cf *ChecksumFile) Save(bundlePath string) error {
-	sumFile := filepath.Join(bundlePath, ".bundle", "SHA256SUM.txt")
+	if err := cf.Algorithm.Validate(); err != nil {
+		return err
+	}
+	format := cf.Format
+	if format == "" {
+		format = FormatText
+	}
+	name := cf.FileName()
+	sumFile := filepath.Join(bundlePath, ".bundle", name)
 
 	// Sort by checksum for determinism
 	sort.Slice(cf.Records, func(i, j int) bool {
@@ -200,18 +295,47 @@ func (cf *ChecksumFile) Save(bundlePath string) error {
 	}
 	defer file.Close()
 
-	writer := bufio.NewWriter(file)
-	for _, record := range cf.Records {
-		fmt.Fprintf(writer, "%s  ./%s\n", record.Checksum, record.FilePath)
+	var out io.Writer = file
+	var gz *gzip.Writer
+	if cf.Compress {
+		gz = gzip.NewWriter(file)
+		out = gz
+	}
+
+	if err := format.encode(out, cf.Records); err != nil {
+		return err
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return err
+		}
+	}
+
+	// Remove stale manifests in other formats, algorithms or compression
+	for _, algo := range hashAlgorithms {
+		for _, other := range manifestFormats {
+			for _, compressed := range []bool{false, true} {
+				stale := other.fileName(algo, compressed)
+				if stale == name {
+					continue
+				}
+				if err := os.Remove(filepath.Join(bundlePath, ".bundle", stale)); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+		}
 	}
-	return writer.Flush()
+	return cf.saveIndex(bundlePath)
 }
 
 // Compute scans a directory and computes checksums for all files.
 //
 // It walks the directory tree, excluding the .bundle/ subdirectory, and computes
 // SHA256 checksums for all regular files using streaming I/O. Symlinks are
-// not followed.
+// not followed. Files are read in parallel, bounded by utils.IOConcurrency,
+// and hashed by at most utils.HashConcurrency goroutines at a time. Paths
+// matching cf.Exclude or a .bundleignore file in the root are left out, as
+// is the .bundleignore file itself (see scanner.IgnorePatterns).
 //
 // Example:
 //
@@ -231,56 +355,204 @@ func (cf *ChecksumFile) Save(bundlePath string) error {
 // Returns:
 //   - error: if directory cannot be walked or checksums cannot be computed
 func (cf *ChecksumFile) Compute(bundlePath string) error {
+	_, err := cf.compute(bundlePath, true, nil, nil)
+	return err
+}
+
+// ProgressFunc receives progress updates while checksums are computed.
+//
+// done is the number of files finished so far, total the number of files
+// found by the scan and currentFile the path, relative to the scanned
+// directory, of the file that just finished. Calls are serialized, so the
+// function does not need to be safe for concurrent use.
+type ProgressFunc func(done, total int, currentFile string)
+
+// ComputeWithProgress is like Compute but calls cb after each file is hashed.
+//
+// The directory is scanned before hashing starts, so total is known and
+// stays the same for every call. A nil cb behaves like Compute.
+//
+// Example:
+//
+//	files := &checksum.ChecksumFile{}
+//	err := files.ComputeWithProgress("/path/to/files", func(done, total int, file string) {
+//	    fmt.Printf("\r%d/%d %s", done, total, file)
+//	})
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the directory to scan
+//   - cb: progress callback, or nil
+//
+// Returns:
+//   - error: if directory cannot be walked or checksums cannot be computed
+func (cf *ChecksumFile) ComputeWithProgress(bundlePath string, cb ProgressFunc) error {
+	_, err := cf.compute(bundlePath, true, cb, nil)
+	return err
+}
+
+// ComputeTolerant is like Compute but skips paths that cannot be read.
+//
+// Instead of aborting on the first unreadable file or directory, the error
+// is recorded in cf.Errors and the scan continues. Only a failure to read
+// the root directory itself is returned as an error.
+//
+// Example:
+//
+//	files := &checksum.ChecksumFile{}
+//	if err := files.ComputeTolerant("/path/to/files"); err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("%d files, %d skipped\n", len(files.Records), len(files.Errors))
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the directory to scan
+//
+// Returns:
+//   - error: if the root directory cannot be walked
+func (cf *ChecksumFile) ComputeTolerant(bundlePath string) error {
+	_, err := cf.compute(bundlePath, false, nil, nil)
+	return err
+}
+
+// ComputeTolerantWithProgress is like ComputeTolerant but calls cb after
+// each file is hashed, as ComputeWithProgress does. Files that cannot be
+// read count as done. A nil cb behaves like ComputeTolerant.
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the directory to scan
+//   - cb: progress callback, or nil
+//
+// Returns:
+//   - error: if the root directory cannot be walked
+func (cf *ChecksumFile) ComputeTolerantWithProgress(bundlePath string, cb ProgressFunc) error {
+	_, err := cf.compute(bundlePath, false, cb, nil)
+	return err
+}
+
+// compute implements Compute, ComputeTolerant, Update and their variants;
+// cb and known may be nil.
+//
+// The directory is scanned first with scanner.ScanDirectoryInfoWithOptions,
+// whose sizes and modification times are used as is; the checksums are
+// then computed by a fileHasher, reading up to utils.IOConcurrency files at
+// once while hashing at most utils.HashConcurrency chunks. A file whose
+// normalized path is in known with the same size and modification time
+// keeps that checksum instead. Records keep the walk order unless cf.Order
+// is OrderPath. The relative paths of the hashed files are returned.
+func (cf *ChecksumFile) compute(bundlePath string, strict bool, cb ProgressFunc, known map[string]ChecksumRecord) ([]string, error) {
+	if err := cf.Algorithm.Validate(); err != nil {
+		return nil, err
+	}
 	cf.Records = []ChecksumRecord{}
+	cf.sizesKnown = true
 	cf.TotalSize = 0
+	cf.Errors = nil
 
-	err := filepath.Walk(bundlePath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Skip .bundle subdirectory
-		if info.IsDir() {
-			if info.Name() == ".bundle" {
-				return filepath.SkipDir
+	scanned, err := scanner.ScanDirectoryInfoWithOptions(bundlePath, scanner.ScanOptions{
+		Exclude: cf.Exclude,
+		// Catch paths over the configured limits before the OS does
+		Check: utils.CheckPathLength,
+		// Stop when strict, otherwise record the error and skip the path
+		OnError: func(relPath string, err error) error {
+			err = utils.WrapPathError(filepath.Join(bundlePath, relPath), err)
+			if strict {
+				return err
 			}
+			cf.Errors = append(cf.Errors, ScanError{Path: relPath, Err: err})
 			return nil
+		},
+	})
+	if err != nil {
+		var tooLong *utils.PathTooLongError
+		if !errors.As(err, &tooLong) {
+			err = utils.WrapPathError(bundlePath, err)
 		}
+		return nil, err
+	}
 
-		// Skip if path contains .bundle (for nested cases)
-		if strings.Contains(path, ".bundle") {
-			return nil
+	type pendingFile struct {
+		path    string
+		relPath string
+		size    int64
+		modTime time.Time
+		reuse   string // Known checksum of an unchanged file
+	}
+	var pending []pendingFile
+
+	for _, scannedFile := range scanned {
+		if !cf.Filter.Match(scannedFile.ModTime) {
+			continue
 		}
 
-		// Compute checksum
-		checksum, err := ComputeFileSHA256(path)
-		if err != nil {
-			return fmt.Errorf("failed to compute checksum for %s: %w", path, err)
+		file := pendingFile{path: scannedFile.Path, relPath: scannedFile.RelPath, size: scannedFile.Size, modTime: scannedFile.ModTime.UTC()}
+		if record, ok := known[normalizeRelPath(file.relPath)]; ok && record.Size == file.size && record.ModTime.Equal(file.modTime) {
+			file.reuse = record.Checksum
 		}
+		pending = append(pending, file)
+	}
 
-		// Get relative path
-		relPath, err := filepath.Rel(bundlePath, path)
-		if err != nil {
-			return fmt.Errorf("failed to get relative path for %s: %w", path, err)
+	// Sort before hashing so progress follows the same order as Records
+	if cf.Order == OrderPath {
+		sortNatural(len(pending),
+			func(i int) string { return filepath.ToSlash(pending[i].relPath) },
+			func(i, j int) { pending[i], pending[j] = pending[j], pending[i] })
+	}
+
+	// Compute checksums of new and changed files, reading and hashing
+	// bounded separately
+	sums := make([]string, len(pending))
+	sumErrs := make([]error, len(pending))
+	var hashed []int
+	for i, file := range pending {
+		if file.reuse != "" {
+			sums[i] = file.reuse
+		} else {
+			hashed = append(hashed, i)
+		}
+	}
+	hasher := newFileHasher(cf.Algorithm, utils.HashConcurrency())
+	report := newProgressReporter(len(hashed), cb)
+	err = utils.ParallelFor(len(hashed), utils.IOConcurrency(), func(n int) error {
+		i := hashed[n]
+		sums[i], sumErrs[i] = hasher.sum(pending[i].path)
+		report.done(pending[i].relPath)
+		if sumErrs[i] != nil && strict {
+			return fmt.Errorf("failed to compute checksum for %s: %w", pending[i].path, sumErrs[i])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rehashed := make([]string, len(hashed))
+	for n, i := range hashed {
+		rehashed[n] = pending[i].relPath
+	}
+
+	for i, file := range pending {
+		if sumErrs[i] != nil {
+			cf.Errors = append(cf.Errors, ScanError{Path: file.relPath, Err: sumErrs[i]})
+			continue
 		}
 
 		cf.Records = append(cf.Records, ChecksumRecord{
-			Checksum: checksum,
-			FilePath: relPath,
+			Checksum: sums[i],
+			FilePath: file.relPath,
+			Size:     file.size,
+			ModTime:  file.modTime,
 		})
-		
-		// Track total size
-		cf.TotalSize += info.Size()
 
-		return nil
-	})
+		// Track total size
+		cf.TotalSize += file.size
+	}
 
-	return err
+	return rehashed, nil
 }
 
 // Verify recomputes checksums and compares against stored values.
 //
-// It recomputes the SHA256 checksum for each file and compares it against
+// It recomputes the checksum for each file, with cf.Algorithm, and compares it against
 // the stored checksum. Files that are missing or have mismatched checksums
 // are returned in the corrupted list.
 //
@@ -308,28 +580,182 @@ func (cf *ChecksumFile) Compute(bundlePath string) error {
 //   - []string: list of relative paths to corrupted or missing files
 //   - error: if checksums cannot be computed or files cannot be read
 func (cf *ChecksumFile) Verify(bundlePath string) ([]string, error) {
-	corrupted := []string{}
+	return cf.VerifyWithProgress(bundlePath, nil)
+}
 
-	for _, record := range cf.Records {
+// VerifyWithProgress is like Verify but records results in progress.
+//
+// Files already recorded in progress are not hashed again; their recorded
+// result is used. Every newly checked file is added to progress, which is
+// saved periodically so an interrupted run can be resumed with
+// LoadVerifyProgress. A nil progress behaves exactly like Verify.
+// It is also saved once every file is checked, and when the run fails.
+//
+// Example:
+//
+//	progress := checksum.LoadVerifyProgress("/path/to/bundle", files)
+//	corrupted, err := files.VerifyWithProgress("/path/to/bundle", progress)
+//	if err != nil {
+//	    progress.Save()
+//	    log.Fatal(err)
+//	}
+//	progress.Remove()
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//   - progress: results of an earlier, interrupted run, or nil
+//
+// Returns:
+//   - []string: list of relative paths to corrupted or missing files
+//   - error: if checksums cannot be computed or files cannot be read
+func (cf *ChecksumFile) VerifyWithProgress(bundlePath string, progress *VerifyProgress) ([]string, error) {
+	details, err := cf.VerifyCorruptions(bundlePath, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	corrupted := make([]string, len(details))
+	for i, c := range details {
+		corrupted[i] = c.Path
+	}
+	return corrupted, nil
+}
+
+// VerifyCorruptions is like VerifyWithProgress but describes each failure.
+//
+// For every corrupted or missing file it reports the expected size from the
+// manifest (when the manifest records sizes) and the actual size on disk,
+// so truncation can be told apart from edits of the same length.
+//
+// Example:
+//
+//	details, err := files.VerifyCorruptions("/path/to/bundle", nil)
+//	for _, c := range details {
+//	    fmt.Printf("%s: %s\n", c.Path, c.Reason)
+//	}
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//   - progress: results of an earlier, interrupted run, or nil
+//
+// Returns:
+//   - []Corruption: one entry per corrupted or missing file, in manifest order
+//   - error: if checksums cannot be computed or files cannot be read
+func (cf *ChecksumFile) VerifyCorruptions(bundlePath string, progress *VerifyProgress) ([]Corruption, error) {
+	return cf.verifyCorruptions(context.Background(), bundlePath, progress)
+}
+
+// verifyCorruptions implements VerifyCorruptions, starting no new files
+// once ctx is cancelled. Besides the periodic saves, progress is saved when
+// the last file is checked and when the run stops early.
+func (cf *ChecksumFile) verifyCorruptions(ctx context.Context, bundlePath string, progress *VerifyProgress) ([]Corruption, error) {
+	if err := cf.Algorithm.Validate(); err != nil {
+		return nil, err
+	}
+	bad := make([]bool, len(cf.Records))
+	actual := make([]int64, len(cf.Records))
+	actualSums := make([]string, len(cf.Records))
+	missing := make([]bool, len(cf.Records))
+
+	// Recompute checksums in parallel, bounded by utils.IOConcurrency and
+	// utils.HashConcurrency
+	hasher := newFileHasher(cf.Algorithm, utils.HashConcurrency())
+	err := utils.ParallelFor(len(cf.Records), utils.IOConcurrency(), func(i int) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		record := cf.Records[i]
 		filePath := filepath.Join(bundlePath, record.FilePath)
 
+		if progress != nil {
+			// Entries from older runs may lack the checksum of a bad file
+			if entry, ok := progress.lookup(record.FilePath); ok && (entry.OK || entry.Checksum != "") {
+				bad[i] = !entry.OK
+				actual[i] = entry.Size
+				actualSums[i] = entry.Checksum
+				if entry.OK {
+					actualSums[i] = record.Checksum
+				}
+				return nil
+			}
+		}
+
 		// Check if file exists
-		if _, err := os.Stat(filePath); os.IsNotExist(err) {
-			corrupted = append(corrupted, record.FilePath)
-			continue
+		info, err := os.Stat(filePath)
+		if os.IsNotExist(err) {
+			bad[i] = true
+			missing[i] = true
+			return nil
 		}
 
 		// Recompute checksum
-		checksum, err := ComputeFileSHA256(filePath)
+		checksum, err := hasher.sum(filePath)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		// Compare
-		if checksum != record.Checksum {
-			corrupted = append(corrupted, record.FilePath)
+		bad[i] = checksum != record.Checksum
+		actual[i] = info.Size()
+		actualSums[i] = checksum
+		if progress != nil {
+			progress.record(record.FilePath, info, checksum, !bad[i])
+		}
+		return nil
+	})
+	if progress != nil {
+		// Best effort, like the periodic saves
+		_ = progress.Save()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	details := []Corruption{}
+	for i, record := range cf.Records {
+		if !bad[i] {
+			continue
 		}
+		details = append(details, newCorruption(record, cf.sizesKnown, missing[i], actual[i], actualSums[i]))
 	}
 
-	return corrupted, nil
+	return details, nil
+}
+
+// VerifyFile recomputes the checksum of one file and compares it against
+// the stored value, without checking the rest of the bundle.
+//
+// A tracked file that no longer exists does not match.
+//
+// Example:
+//
+//	files := &checksum.ChecksumFile{}
+//	files.Load("/path/to/bundle")
+//	ok, err := files.VerifyFile("/path/to/bundle", "docs/report.pdf")
+//	if errors.Is(err, utils.ErrFileNotTracked) {
+//	    fmt.Println("not part of the bundle")
+//	}
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//   - relPath: path of the file relative to the bundle root
+//
+// Returns:
+//   - bool: true if the checksum matches
+//   - error: utils.ErrFileNotTracked if relPath has no record, or an I/O
+//     error if the file cannot be read
+func (cf *ChecksumFile) VerifyFile(bundlePath, relPath string) (bool, error) {
+	expected, ok := cf.Lookup(relPath)
+	if !ok {
+		return false, fmt.Errorf("%w: %s", utils.ErrFileNotTracked, relPath)
+	}
+
+	actual, err := ComputeFileHash(filepath.Join(bundlePath, filepath.FromSlash(normalizeRelPath(relPath))), cf.Algorithm)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return actual == expected, nil
 }
@@ -1,10 +1,15 @@
 package checksum
 
 import (
+	"errors"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/jvzantvoort/bundle/utils"
+	"github.com/spf13/viper"
 )
 
 func TestComputeFileSHA256(t *testing.T) {
@@ -124,3 +129,451 @@ func TestChecksumFile_ComputeAndVerify(t *testing.T) {
 		t.Errorf("got %d corrupted files, want 1", len(corrupted))
 	}
 }
+
+func TestChecksumFile_ComputeWithProgress(t *testing.T) {
+	tmpDir := t.TempDir()
+	names := []string{"a.txt", "b.txt", filepath.Join("sub", "c.txt")}
+	for _, name := range names {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	var dones []int
+	seen := map[string]bool{}
+	cf := &ChecksumFile{}
+	err := cf.ComputeWithProgress(tmpDir, func(done, total int, file string) {
+		if total != len(names) {
+			t.Errorf("total = %d, want %d", total, len(names))
+		}
+		dones = append(dones, done)
+		seen[file] = true
+	})
+	if err != nil {
+		t.Fatalf("ComputeWithProgress() error = %v", err)
+	}
+	for i, done := range dones {
+		if done != i+1 {
+			t.Errorf("call %d: done = %d, want %d", i, done, i+1)
+		}
+	}
+	for _, name := range names {
+		if !seen[name] {
+			t.Errorf("no progress reported for %s", name)
+		}
+	}
+
+	// A nil callback behaves like Compute
+	plain := &ChecksumFile{}
+	if err := plain.Compute(tmpDir); err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+	silent := &ChecksumFile{}
+	if err := silent.ComputeWithProgress(tmpDir, nil); err != nil {
+		t.Fatalf("ComputeWithProgress(nil) error = %v", err)
+	}
+	if len(silent.Records) != len(plain.Records) || silent.TotalSize != plain.TotalSize {
+		t.Errorf("nil callback result differs from Compute")
+	}
+}
+
+func TestChecksumFile_ComputeTolerant(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "good.txt"), []byte("good"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	// A dangling symlink cannot be opened for hashing
+	if err := os.Symlink(filepath.Join(tmpDir, "missing"), filepath.Join(tmpDir, "broken")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	strict := &ChecksumFile{}
+	if err := strict.Compute(tmpDir); err == nil {
+		t.Fatal("Compute() expected error for unreadable path")
+	}
+
+	cf := &ChecksumFile{}
+	if err := cf.ComputeTolerant(tmpDir); err != nil {
+		t.Fatalf("ComputeTolerant() error = %v", err)
+	}
+	if len(cf.Records) != 1 {
+		t.Errorf("got %d records, want 1", len(cf.Records))
+	}
+	if len(cf.Errors) != 1 || cf.Errors[0].Path != "broken" {
+		t.Errorf("got errors %v, want one for broken", cf.Errors)
+	}
+}
+
+func TestChecksumFile_PathLengthLimit(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("max_name_length", 12)
+
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "short.txt"), []byte("ok"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "much-too-long-name.txt"), []byte("long"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	strict := &ChecksumFile{}
+	if err := strict.Compute(tmpDir); err == nil {
+		t.Fatal("Compute() expected error for over-long name")
+	}
+
+	cf := &ChecksumFile{}
+	if err := cf.ComputeTolerant(tmpDir); err != nil {
+		t.Fatalf("ComputeTolerant() error = %v", err)
+	}
+	if len(cf.Records) != 1 || cf.Records[0].FilePath != "short.txt" {
+		t.Errorf("got records %v, want only short.txt", cf.Records)
+	}
+	if len(cf.Errors) != 1 || cf.Errors[0].Path != "much-too-long-name.txt" {
+		t.Errorf("got errors %v, want one for the long name", cf.Errors)
+	}
+}
+
+func TestBundleChecksumBuilder_MatchesBatch(t *testing.T) {
+	checksums := []string{
+		"a1b2c3d4e5f67890123456789012345678901234567890123456789012345678",
+		"b2c3d4e5f67890123456789012345678901234567890123456789012345678a1",
+		"c3d4e5f67890123456789012345678901234567890123456789012345678a1b2",
+	}
+	want := ComputeBundleChecksum(checksums)
+
+	for i := 0; i < 20; i++ {
+		shuffled := make([]string, len(checksums))
+		copy(shuffled, checksums)
+		rand.Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+
+		builder := NewBundleChecksumBuilder()
+		for _, c := range shuffled {
+			builder.Add(c)
+		}
+		if got := builder.Finalize(); got != want {
+			t.Fatalf("iteration %d: builder = %s, batch = %s", i, got, want)
+		}
+	}
+
+	if got, want := NewBundleChecksumBuilder().Finalize(), ComputeBundleChecksum(nil); got != want {
+		t.Errorf("empty builder = %s, want %s", got, want)
+	}
+}
+
+func TestChecksumFile_CompressedManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "file1.txt"), []byte("content1"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(tmpDir, ".bundle"), 0755); err != nil {
+		t.Fatalf("failed to create bundle dir: %v", err)
+	}
+
+	cf := &ChecksumFile{}
+	if err := cf.Compute(tmpDir); err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+	if err := cf.Save(tmpDir); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	cf.Compress = true
+	if err := cf.Save(tmpDir); err != nil {
+		t.Fatalf("Save() compressed error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".bundle", "SHA256SUM.txt")); !os.IsNotExist(err) {
+		t.Errorf("plain manifest should be removed after compressed save")
+	}
+
+	cf2 := &ChecksumFile{}
+	if err := cf2.Load(tmpDir); err != nil {
+		t.Fatalf("Load() compressed error = %v", err)
+	}
+	if !cf2.Compress {
+		t.Errorf("Load() should mark manifest as compressed")
+	}
+	if len(cf2.Records) != 1 || cf2.Records[0].Checksum != cf.Records[0].Checksum || cf2.Records[0].FilePath != cf.Records[0].FilePath {
+		t.Errorf("loaded records = %v, want %v", cf2.Records, cf.Records)
+	}
+}
+
+func TestChecksumFile_JSONManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "file1.txt"), []byte("content1"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(tmpDir, ".bundle"), 0755); err != nil {
+		t.Fatalf("failed to create bundle dir: %v", err)
+	}
+
+	cf := &ChecksumFile{}
+	if err := cf.Compute(tmpDir); err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+	if err := cf.Save(tmpDir); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	cf.Format = FormatJSON
+	if err := cf.Save(tmpDir); err != nil {
+		t.Fatalf("Save() json error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".bundle", "SHA256SUM.txt")); !os.IsNotExist(err) {
+		t.Errorf("text manifest should be removed after json save")
+	}
+
+	// Probe without a format
+	cf2 := &ChecksumFile{}
+	if err := cf2.Load(tmpDir); err != nil {
+		t.Fatalf("Load() json error = %v", err)
+	}
+	if cf2.Format != FormatJSON {
+		t.Errorf("Load() format = %q, want %q", cf2.Format, FormatJSON)
+	}
+	if len(cf2.Records) != 1 || cf2.Records[0] != cf.Records[0] {
+		t.Errorf("loaded records = %v, want %v", cf2.Records, cf.Records)
+	}
+	if cf2.Records[0].Size != int64(len("content1")) {
+		t.Errorf("loaded size = %d, want %d", cf2.Records[0].Size, len("content1"))
+	}
+
+	// An explicit format only reads that format
+	cf3 := &ChecksumFile{Format: FormatText}
+	if err := cf3.Load(tmpDir); !os.IsNotExist(err) {
+		t.Errorf("Load() with text format error = %v, want not exist", err)
+	}
+}
+
+func TestChecksumFile_VerifyCorruptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		format       ManifestFormat
+		modify       func(dir string) error
+		wantReason   string
+		wantExpected bool
+		wantActual   bool
+	}{
+		{
+			name:   "truncated json",
+			format: FormatJSON,
+			modify: func(dir string) error {
+				return os.WriteFile(filepath.Join(dir, "file1.txt"), []byte("con"), 0644)
+			},
+			wantReason:   ReasonSizeChanged,
+			wantExpected: true,
+			wantActual:   true,
+		},
+		{
+			name:   "edited json",
+			format: FormatJSON,
+			modify: func(dir string) error {
+				return os.WriteFile(filepath.Join(dir, "file1.txt"), []byte("CONTENT1"), 0644)
+			},
+			wantReason:   ReasonContentChanged,
+			wantExpected: true,
+			wantActual:   true,
+		},
+		{
+			name:   "missing json",
+			format: FormatJSON,
+			modify: func(dir string) error {
+				return os.Remove(filepath.Join(dir, "file1.txt"))
+			},
+			wantReason:   ReasonMissing,
+			wantExpected: true,
+		},
+		{
+			name:   "truncated text",
+			format: FormatText,
+			modify: func(dir string) error {
+				return os.WriteFile(filepath.Join(dir, "file1.txt"), []byte("con"), 0644)
+			},
+			wantReason: ReasonChecksumMismatch,
+			wantActual: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, "file1.txt"), []byte("content1"), 0644); err != nil {
+				t.Fatalf("failed to create test file: %v", err)
+			}
+			if err := os.Mkdir(filepath.Join(tmpDir, ".bundle"), 0755); err != nil {
+				t.Fatalf("failed to create bundle dir: %v", err)
+			}
+
+			cf := &ChecksumFile{Format: tt.format}
+			if err := cf.Compute(tmpDir); err != nil {
+				t.Fatalf("Compute() error = %v", err)
+			}
+			if err := cf.Save(tmpDir); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+			if err := tt.modify(tmpDir); err != nil {
+				t.Fatalf("modify error = %v", err)
+			}
+
+			// Verify against the manifest on disk, not the computed records
+			loaded := &ChecksumFile{}
+			if err := loaded.Load(tmpDir); err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			details, err := loaded.VerifyCorruptions(tmpDir, nil)
+			if err != nil {
+				t.Fatalf("VerifyCorruptions() error = %v", err)
+			}
+			if len(details) != 1 {
+				t.Fatalf("VerifyCorruptions() = %v, want 1 entry", details)
+			}
+
+			got := details[0]
+			if got.Path != "file1.txt" || got.Reason != tt.wantReason {
+				t.Errorf("corruption = %s/%s, want file1.txt/%s", got.Path, got.Reason, tt.wantReason)
+			}
+			if (got.ExpectedSize != nil) != tt.wantExpected {
+				t.Errorf("ExpectedSize = %v, want set %v", got.ExpectedSize, tt.wantExpected)
+			}
+			if got.ExpectedSize != nil && *got.ExpectedSize != int64(len("content1")) {
+				t.Errorf("ExpectedSize = %d, want %d", *got.ExpectedSize, len("content1"))
+			}
+			if (got.ActualSize != nil) != tt.wantActual {
+				t.Errorf("ActualSize = %v, want set %v", got.ActualSize, tt.wantActual)
+			}
+		})
+	}
+}
+
+func TestChecksumFile_VerifyFile(t *testing.T) {
+	tmpDir, cf := setupProgressBundle(t)
+
+	if ok, err := cf.VerifyFile(tmpDir, "./a.txt"); err != nil || !ok {
+		t.Errorf("VerifyFile() intact = %v, %v; want true, nil", ok, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "b.txt"), []byte("B"), 0644); err != nil {
+		t.Fatalf("failed to modify test file: %v", err)
+	}
+	if ok, err := cf.VerifyFile(tmpDir, "b.txt"); err != nil || ok {
+		t.Errorf("VerifyFile() modified = %v, %v; want false, nil", ok, err)
+	}
+
+	if err := os.Remove(filepath.Join(tmpDir, "a.txt")); err != nil {
+		t.Fatalf("failed to remove test file: %v", err)
+	}
+	if ok, err := cf.VerifyFile(tmpDir, "a.txt"); err != nil || ok {
+		t.Errorf("VerifyFile() missing = %v, %v; want false, nil", ok, err)
+	}
+
+	if _, err := cf.VerifyFile(tmpDir, "c.txt"); !errors.Is(err, utils.ErrFileNotTracked) {
+		t.Errorf("VerifyFile() untracked error = %v, want ErrFileNotTracked", err)
+	}
+}
+
+func TestChecksumFile_RecomputedExport(t *testing.T) {
+	tmpDir := t.TempDir()
+	for name, content := range map[string]string{"keep.txt": "keep", "edit.txt": "before", "gone.txt": "gone"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	cf := &ChecksumFile{}
+	if err := cf.Compute(tmpDir); err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "edit.txt"), []byte("after!"), 0644); err != nil {
+		t.Fatalf("failed to modify test file: %v", err)
+	}
+	if err := os.Remove(filepath.Join(tmpDir, "gone.txt")); err != nil {
+		t.Fatalf("failed to remove test file: %v", err)
+	}
+
+	corrupted, err := cf.VerifyCorruptions(tmpDir, nil)
+	if err != nil {
+		t.Fatalf("VerifyCorruptions() error = %v", err)
+	}
+	current := cf.Recomputed(corrupted)
+
+	// The recomputed manifest matches a fresh scan of the directory
+	fresh := &ChecksumFile{}
+	if err := fresh.Compute(tmpDir); err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+	want := map[string]string{}
+	for _, record := range fresh.Records {
+		want[record.FilePath] = record.Checksum
+	}
+	if len(current.Records) != len(want) {
+		t.Fatalf("Recomputed() has %d records, want %d", len(current.Records), len(want))
+	}
+	for _, record := range current.Records {
+		if want[record.FilePath] != record.Checksum {
+			t.Errorf("Recomputed() %s = %s, want %s", record.FilePath, record.Checksum, want[record.FilePath])
+		}
+	}
+
+	out := filepath.Join(t.TempDir(), "current.sha256")
+	if err := current.Export(out); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("failed to read export: %v", err)
+	}
+	if want := want["edit.txt"] + "  ./edit.txt\n"; !strings.Contains(string(data), want) {
+		t.Errorf("Export() = %q, want line %q", data, want)
+	}
+
+	// Untracked files are only added by WithUntracked
+	if err := os.WriteFile(filepath.Join(tmpDir, "new.txt"), []byte("new"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	withNew, err := current.WithUntracked(tmpDir, []string{"new.txt"})
+	if err != nil {
+		t.Fatalf("WithUntracked() error = %v", err)
+	}
+	if len(current.Records) != len(want) || len(withNew.Records) != len(want)+1 {
+		t.Fatalf("records = %d and %d, want %d and %d", len(current.Records), len(withNew.Records), len(want), len(want)+1)
+	}
+	newSum, _ := ComputeFileSHA256(filepath.Join(tmpDir, "new.txt"))
+	if last := withNew.Records[len(want)]; last.FilePath != "new.txt" || last.Checksum != newSum || last.Size != 3 {
+		t.Errorf("WithUntracked() record = %+v, want new.txt %s", last, newSum)
+	}
+	if withNew.TotalSize != current.TotalSize+3 {
+		t.Errorf("TotalSize = %d, want %d", withNew.TotalSize, current.TotalSize+3)
+	}
+	if _, err := current.WithUntracked(tmpDir, []string{"absent.txt"}); err == nil {
+		t.Error("WithUntracked() of a missing file succeeded, want error")
+	}
+}
+
+func TestParseManifestFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ManifestFormat
+		wantErr bool
+	}{
+		{"", FormatText, false},
+		{"text", FormatText, false},
+		{"JSON", FormatJSON, false},
+		{"sqlite", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseManifestFormat(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseManifestFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseManifestFormat(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package checksum
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Algorithm is the name of the default hash algorithm for file and bundle
+// checksums.
+//
+// The algorithm a bundle was created with is recorded in META.json and
+// reported by info and verify so consumers know how to interpret the
+// checksums; see HashAlgorithm.
+const Algorithm = string(SHA256)
+
+// ManifestFormat selects how checksum records are serialized in .bundle/.
+//
+// The format does not affect the bundle checksum; it only changes how the
+// per-file records are stored on disk.
+//
+// Example:
+//
+//	files := &checksum.ChecksumFile{Format: checksum.FormatJSON}
+//	files.Compute("/path/to/files")
+//	files.Save("/path/to/bundle") // writes .bundle/SHA256SUM.json
+type ManifestFormat string
+
+const (
+	// FormatText is the sha256sum(1) compatible SHA256SUM.txt (default)
+	FormatText ManifestFormat = "text"
+
+	// FormatJSON is SHA256SUM.json, an array of path/checksum/size records
+	FormatJSON ManifestFormat = "json"
+)
+
+// manifestFormats lists the supported formats in probe order.
+var manifestFormats = []ManifestFormat{FormatText, FormatJSON}
+
+// ParseManifestFormat converts a string to a ManifestFormat.
+//
+// An empty string selects FormatText.
+//
+// Example:
+//
+//	format, err := checksum.ParseManifestFormat("json")
+//
+// Parameters:
+//   - s: "text" or "json"
+//
+// Returns:
+//   - ManifestFormat: the parsed format
+//   - error: if s is not a supported format
+func ParseManifestFormat(s string) (ManifestFormat, error) {
+	switch ManifestFormat(strings.ToLower(s)) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("invalid manifest format %q (expected text or json)", s)
+}
+
+// fileName returns the manifest file name in .bundle/ for f and algo,
+// e.g. SHA256SUM.txt or SHA512SUM.json.gz.
+func (f ManifestFormat) fileName(algo HashAlgorithm, compressed bool) string {
+	name := algo.manifestPrefix() + ".txt"
+	if f == FormatJSON {
+		name = algo.manifestPrefix() + ".json"
+	}
+	if compressed {
+		name += ".gz"
+	}
+	return name
+}
+
+// decode reads records from r in format f.
+func (f ManifestFormat) decode(r io.Reader) ([]ChecksumRecord, error) {
+	records := []ChecksumRecord{}
+	if f == FormatJSON {
+		if err := json.NewDecoder(r).Decode(&records); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON manifest: %w", err)
+		}
+		return records, nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) >= 2 {
+			records = append(records, ChecksumRecord{
+				Checksum: parts[0],
+				FilePath: strings.TrimPrefix(parts[1], "./"),
+			})
+		}
+	}
+	return records, scanner.Err()
+}
+
+// encode writes records to w in format f.
+func (f ManifestFormat) encode(w io.Writer, records []ChecksumRecord) error {
+	if f == FormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	}
+
+	writer := bufio.NewWriter(w)
+	for _, record := range records {
+		fmt.Fprintf(writer, "%s  ./%s\n", record.Checksum, record.FilePath)
+	}
+	return writer.Flush()
+}
+
+// openManifest finds and opens the manifest in bundlePath/.bundle.
+//
+// When cf.Format or cf.Algorithm is set only that format or algorithm is
+// considered, otherwise every supported one is probed. Both the plain and
+// gzip-compressed forms are tried. If nothing is found the error for the
+// plain form of the first candidate is returned so os.IsNotExist still
+// applies.
+func (cf *ChecksumFile) openManifest(bundlePath string) (*os.File, manifestName, error) {
+	if err := cf.Algorithm.Validate(); err != nil {
+		return nil, manifestName{}, err
+	}
+	formats := manifestFormats
+	if cf.Format != "" {
+		formats = []ManifestFormat{cf.Format}
+	}
+	algos := hashAlgorithms
+	if cf.Algorithm != "" {
+		algos = []HashAlgorithm{cf.Algorithm}
+	}
+
+	var firstErr error
+	for _, algo := range algos {
+		for _, format := range formats {
+			for _, compressed := range []bool{false, true} {
+				name := manifestName{format: format, algo: algo, compressed: compressed}
+				file, err := os.Open(filepath.Join(bundlePath, ".bundle", name.String()))
+				if err == nil {
+					return file, name, nil
+				}
+				if !os.IsNotExist(err) {
+					return nil, manifestName{}, err
+				}
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+	}
+	return nil, manifestName{}, firstErr
+}
+
+// manifestName identifies one manifest file variant in .bundle/.
+type manifestName struct {
+	format     ManifestFormat
+	algo       HashAlgorithm
+	compressed bool
+}
+
+// String returns the file name of n.
+func (n manifestName) String() string {
+	return n.format.fileName(n.algo, n.compressed)
+}
@@ -0,0 +1,112 @@
+package checksum
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ScanOrder selects the order of the records produced by Compute.
+//
+// The order only affects the in-memory Records and therefore the order in
+// which files are hashed and reported. Save sorts the manifest and the
+// bundle checksum is order independent, so the saved result is the same for
+// every order.
+//
+// Example:
+//
+//	files := &checksum.ChecksumFile{Order: checksum.OrderPath}
+//	files.Compute("/path/to/files") // file2.txt before file10.txt
+type ScanOrder string
+
+const (
+	// OrderWalk keeps the filesystem walk order (default)
+	OrderWalk ScanOrder = "walk"
+
+	// OrderPath sorts by relative path in natural order, comparing runs of
+	// digits numerically
+	OrderPath ScanOrder = "path"
+)
+
+// ParseScanOrder converts a string to a ScanOrder.
+//
+// An empty string selects OrderWalk.
+//
+// Example:
+//
+//	order, err := checksum.ParseScanOrder("path")
+//
+// Parameters:
+//   - s: "walk" or "path"
+//
+// Returns:
+//   - ScanOrder: the parsed order
+//   - error: if s is not a supported order
+func ParseScanOrder(s string) (ScanOrder, error) {
+	switch ScanOrder(strings.ToLower(s)) {
+	case "", OrderWalk:
+		return OrderWalk, nil
+	case OrderPath:
+		return OrderPath, nil
+	}
+	return "", fmt.Errorf("invalid scan order %q (expected walk or path)", s)
+}
+
+// sortNatural sorts items by the key returned for each index, in natural
+// order.
+func sortNatural(n int, key func(i int) string, swap func(i, j int)) {
+	sort.Sort(naturalSorter{n: n, key: key, swap: swap})
+}
+
+// naturalSorter adapts sortNatural to sort.Interface.
+type naturalSorter struct {
+	n    int
+	key  func(i int) string
+	swap func(i, j int)
+}
+
+func (s naturalSorter) Len() int           { return s.n }
+func (s naturalSorter) Less(i, j int) bool { return naturalLess(s.key(i), s.key(j)) }
+func (s naturalSorter) Swap(i, j int)      { s.swap(i, j) }
+
+// naturalLess reports whether a sorts before b, comparing runs of ASCII
+// digits by numeric value and everything else bytewise. Numerically equal
+// runs with different leading zeros fall back to a bytewise comparison so
+// the order stays total.
+func naturalLess(a, b string) bool {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			startA, startB := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+			numA := strings.TrimLeft(a[startA:i], "0")
+			numB := strings.TrimLeft(b[startB:j], "0")
+			if len(numA) != len(numB) {
+				return len(numA) < len(numB)
+			}
+			if numA != numB {
+				return numA < numB
+			}
+			continue
+		}
+		if a[i] != b[j] {
+			return a[i] < b[j]
+		}
+		i++
+		j++
+	}
+	if len(a)-i != len(b)-j {
+		return len(a)-i < len(b)-j
+	}
+	return a < b
+}
+
+// isDigit reports whether c is an ASCII digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
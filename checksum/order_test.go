@@ -0,0 +1,59 @@
+package checksum
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNaturalLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"file2.txt", "file10.txt", true},
+		{"file10.txt", "file2.txt", false},
+		{"a/b", "a/c", true},
+		{"img", "img1", true},
+		{"img01", "img1", true},
+		{"img1", "img01", false},
+		{"same", "same", false},
+		{"v1.9", "v1.10", true},
+	}
+	for _, tt := range tests {
+		if got := naturalLess(tt.a, tt.b); got != tt.want {
+			t.Errorf("naturalLess(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestChecksumFile_PathOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"file10.txt", "file2.txt", "file1.txt"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	cf := &ChecksumFile{Order: OrderPath}
+	if err := cf.Compute(tmpDir); err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+	want := []string{"file1.txt", "file2.txt", "file10.txt"}
+	for i, record := range cf.Records {
+		if record.FilePath != want[i] {
+			t.Errorf("Records[%d] = %s, want %s", i, record.FilePath, want[i])
+		}
+	}
+}
+
+func TestParseScanOrder(t *testing.T) {
+	for _, s := range []string{"", "walk", "PATH"} {
+		if _, err := ParseScanOrder(s); err != nil {
+			t.Errorf("ParseScanOrder(%q) error = %v", s, err)
+		}
+	}
+	if _, err := ParseScanOrder("size"); err == nil {
+		t.Error("ParseScanOrder(\"size\") expected error")
+	}
+}
@@ -0,0 +1,174 @@
+package checksum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// progressName is the verification progress file in .bundle/
+	progressName = "VERIFY_PROGRESS.json"
+
+	// progressSaveInterval is the minimum time between progress saves
+	progressSaveInterval = 10 * time.Second
+)
+
+// VerifyProgress records which files a verification run has already checked.
+//
+// It is saved to .bundle/VERIFY_PROGRESS.json every progressSaveInterval
+// while a verification runs, when the run is cancelled or fails, and once
+// all files are checked, so an interrupted run can be resumed. The progress is
+// only reused when the manifest is unchanged and none of the recorded files
+// changed size or modification time since they were checked.
+//
+// Example:
+//
+//	progress := checksum.LoadVerifyProgress("/path/to/bundle", files)
+//	corrupted, err := files.VerifyWithProgress("/path/to/bundle", progress)
+//	if err == nil {
+//	    progress.Remove()
+//	}
+type VerifyProgress struct {
+	ManifestDigest string                   `json:"manifest_digest"` // Digest of the manifest records
+	Files          map[string]ProgressEntry `json:"files"`           // Checked files by relative path
+
+	path      string
+	mu        sync.Mutex
+	lastSaved time.Time
+}
+
+// ProgressEntry is the recorded result for one verified file.
+type ProgressEntry struct {
+	Size     int64     `json:"size"`               // File size when checked
+	ModTime  time.Time `json:"mod_time"`           // Modification time when checked
+	OK       bool      `json:"ok"`                 // Checksum matched
+	Checksum string    `json:"checksum,omitempty"` // Checksum computed for the file
+}
+
+// manifestDigest returns a digest identifying the set of manifest records.
+func manifestDigest(records []ChecksumRecord) string {
+	lines := make([]string, len(records))
+	for i, record := range records {
+		lines[i] = record.Checksum + "  " + normalizeRelPath(record.FilePath)
+	}
+	sort.Strings(lines)
+
+	hash := sha256.New()
+	for _, line := range lines {
+		hash.Write([]byte(line + "\n"))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// NewVerifyProgress returns empty progress for verifying cf in bundlePath.
+func NewVerifyProgress(bundlePath string, cf *ChecksumFile) *VerifyProgress {
+	return &VerifyProgress{
+		ManifestDigest: manifestDigest(cf.Records),
+		Files:          map[string]ProgressEntry{},
+		path:           filepath.Join(bundlePath, ".bundle", progressName),
+		lastSaved:      time.Now(),
+	}
+}
+
+// LoadVerifyProgress loads saved progress for verifying cf in bundlePath.
+//
+// Saved progress is discarded, and empty progress returned, when it is
+// missing or unreadable, when it was recorded for a different manifest, or
+// when any recorded file changed size or modification time since.
+//
+// Example:
+//
+//	progress := checksum.LoadVerifyProgress("/path/to/bundle", files)
+//	fmt.Printf("%d files already verified\n", len(progress.Files))
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//   - cf: the loaded manifest that will be verified
+//
+// Returns:
+//   - *VerifyProgress: the saved progress, or empty progress
+func LoadVerifyProgress(bundlePath string, cf *ChecksumFile) *VerifyProgress {
+	fresh := NewVerifyProgress(bundlePath, cf)
+
+	data, err := os.ReadFile(fresh.path)
+	if err != nil {
+		return fresh
+	}
+	saved := &VerifyProgress{}
+	if err := json.Unmarshal(data, saved); err != nil || saved.Files == nil {
+		return fresh
+	}
+	if saved.ManifestDigest != fresh.ManifestDigest {
+		return fresh
+	}
+	for relPath, entry := range saved.Files {
+		info, err := os.Stat(filepath.Join(bundlePath, relPath))
+		if err != nil || info.Size() != entry.Size || !info.ModTime().Equal(entry.ModTime) {
+			return fresh
+		}
+	}
+
+	fresh.Files = saved.Files
+	return fresh
+}
+
+// lookup returns the recorded result for relPath.
+func (p *VerifyProgress) lookup(relPath string) (ProgressEntry, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, ok := p.Files[relPath]
+	return entry, ok
+}
+
+// record stores the result and checksum for relPath and saves the progress file when
+// progressSaveInterval has passed since the last save. Save errors are
+// ignored; progress is best effort.
+func (p *VerifyProgress) record(relPath string, info os.FileInfo, sum string, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Files[relPath] = ProgressEntry{Size: info.Size(), ModTime: info.ModTime(), OK: ok, Checksum: sum}
+	if time.Since(p.lastSaved) >= progressSaveInterval {
+		_ = p.save()
+	}
+}
+
+// Save writes the progress to .bundle/VERIFY_PROGRESS.json.
+//
+// Returns:
+//   - error: if the file cannot be written
+func (p *VerifyProgress) Save() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.save()
+}
+
+// save writes the progress file atomically; the caller holds p.mu.
+func (p *VerifyProgress) save() error {
+	p.lastSaved = time.Now()
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	tmp := p.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.path)
+}
+
+// Remove deletes the progress file after a completed verification.
+//
+// Returns:
+//   - error: if the file exists but cannot be removed
+func (p *VerifyProgress) Remove() error {
+	if err := os.Remove(p.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package checksum
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func setupProgressBundle(t *testing.T) (string, *ChecksumFile) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	for name, content := range map[string]string{"a.txt": "a", "b.txt": "b"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(tmpDir, ".bundle"), 0755); err != nil {
+		t.Fatalf("failed to create bundle dir: %v", err)
+	}
+	cf := &ChecksumFile{}
+	if err := cf.Compute(tmpDir); err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+	return tmpDir, cf
+}
+
+func TestVerifyProgress_Resume(t *testing.T) {
+	tmpDir, cf := setupProgressBundle(t)
+
+	progress := NewVerifyProgress(tmpDir, cf)
+	if _, err := cf.VerifyWithProgress(tmpDir, progress); err != nil {
+		t.Fatalf("VerifyWithProgress() error = %v", err)
+	}
+	if len(progress.Files) != 2 {
+		t.Fatalf("recorded %d files, want 2", len(progress.Files))
+	}
+
+	// Pretend a.txt failed in the interrupted run; a resumed run must
+	// trust the recorded result instead of hashing the file again
+	entry := progress.Files["a.txt"]
+	entry.OK = false
+	progress.Files["a.txt"] = entry
+	if err := progress.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	resumed := LoadVerifyProgress(tmpDir, cf)
+	if len(resumed.Files) != 2 {
+		t.Fatalf("loaded %d files, want 2", len(resumed.Files))
+	}
+	corrupted, err := cf.VerifyWithProgress(tmpDir, resumed)
+	if err != nil {
+		t.Fatalf("VerifyWithProgress() resumed error = %v", err)
+	}
+	if len(corrupted) != 1 || corrupted[0] != "a.txt" {
+		t.Errorf("corrupted = %v, want [a.txt] from saved progress", corrupted)
+	}
+
+	if err := resumed.Remove(); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".bundle", progressName)); !os.IsNotExist(err) {
+		t.Errorf("progress file should be removed")
+	}
+}
+
+func TestVerifyProgress_Invalidation(t *testing.T) {
+	tmpDir, cf := setupProgressBundle(t)
+
+	progress := NewVerifyProgress(tmpDir, cf)
+	if _, err := cf.VerifyWithProgress(tmpDir, progress); err != nil {
+		t.Fatalf("VerifyWithProgress() error = %v", err)
+	}
+	if err := progress.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// A different manifest discards the progress
+	other := &ChecksumFile{Records: []ChecksumRecord{{Checksum: "abc", FilePath: "a.txt"}}}
+	if got := LoadVerifyProgress(tmpDir, other); len(got.Files) != 0 {
+		t.Errorf("progress for another manifest should be discarded, got %d files", len(got.Files))
+	}
+
+	// A changed file discards the progress
+	if err := os.WriteFile(filepath.Join(tmpDir, "b.txt"), []byte("changed"), 0644); err != nil {
+		t.Fatalf("failed to modify file: %v", err)
+	}
+	if got := LoadVerifyProgress(tmpDir, cf); len(got.Files) != 0 {
+		t.Errorf("progress after file change should be discarded, got %d files", len(got.Files))
+	}
+}
+
+func TestVerifyProgress_SavedOnCompletion(t *testing.T) {
+	tmpDir, cf := setupProgressBundle(t)
+
+	// Well within progressSaveInterval, so only the final save writes
+	progress := NewVerifyProgress(tmpDir, cf)
+	if _, err := cf.VerifyWithProgress(tmpDir, progress); err != nil {
+		t.Fatalf("VerifyWithProgress() error = %v", err)
+	}
+
+	saved := LoadVerifyProgress(tmpDir, cf)
+	if len(saved.Files) != 2 {
+		t.Errorf("saved %d files, want 2", len(saved.Files))
+	}
+}
+
+func TestVerifyProgress_SavedOnCancel(t *testing.T) {
+	tmpDir, cf := setupProgressBundle(t)
+
+	// Result of an earlier run that must survive the cancelled one
+	progress := NewVerifyProgress(tmpDir, cf)
+	if _, err := cf.VerifyWithProgress(tmpDir, progress); err != nil {
+		t.Fatalf("VerifyWithProgress() error = %v", err)
+	}
+	if err := progress.Remove(); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := cf.VerifyDetailedContext(ctx, tmpDir, progress)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("VerifyDetailedContext() error = %v, want context.Canceled", err)
+	}
+
+	saved := LoadVerifyProgress(tmpDir, cf)
+	if len(saved.Files) != 2 {
+		t.Errorf("saved %d files, want 2", len(saved.Files))
+	}
+}
@@ -0,0 +1,161 @@
+package checksum
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+
+	"github.com/jvzantvoort/bundle/scanner"
+)
+
+// VerifyResult sorts the outcome of a verification by kind of problem, so
+// a missing file can be treated differently from a corrupted one.
+//
+// All paths are relative to the bundle root and sorted. Extra files do not
+// make a bundle invalid; they are reported so they can be added with a
+// forced create or removed.
+//
+// Example JSON:
+//
+//	{
+//	  "missing": ["document.pdf"],
+//	  "mismatched": ["photo1.jpg"],
+//	  "extra": ["notes.txt"]
+//	}
+type VerifyResult struct {
+	Missing    []string     `json:"missing"`    // In the manifest, not on disk
+	Mismatched []string     `json:"mismatched"` // On disk with a different checksum
+	Extra      []string     `json:"extra"`      // On disk, not in the manifest
+	Corrupted  []Corruption `json:"-"`          // Details of missing and mismatched files
+}
+
+// OK reports whether no tracked file is missing or mismatched.
+func (r *VerifyResult) OK() bool {
+	return len(r.Missing) == 0 && len(r.Mismatched) == 0
+}
+
+// VerifyDetailed verifies the bundle and classifies the result into
+// missing, mismatched and extra files.
+//
+// Extra files are found with a walk that follows the rules of Compute:
+// .bundle/ and paths matching cf.Exclude or .bundleignore are skipped and,
+// when cf.Filter is set, only files modified in its window count.
+// Unreadable paths are not reported as extra.
+//
+// Example:
+//
+//	files := &checksum.ChecksumFile{}
+//	files.Load("/path/to/bundle")
+//	result, err := files.VerifyDetailed("/path/to/bundle")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("missing: %v, mismatched: %v, extra: %v\n",
+//	    result.Missing, result.Mismatched, result.Extra)
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//
+// Returns:
+//   - *VerifyResult: the classified result
+//   - error: if checksums cannot be computed or the bundle cannot be walked
+func (cf *ChecksumFile) VerifyDetailed(bundlePath string) (*VerifyResult, error) {
+	return cf.VerifyDetailedWithProgress(bundlePath, nil)
+}
+
+// VerifyDetailedWithProgress is VerifyDetailed resuming from the results
+// of an earlier, interrupted run, like VerifyWithProgress.
+//
+// Example:
+//
+//	progress := checksum.LoadVerifyProgress("/path/to/bundle", files)
+//	result, err := files.VerifyDetailedWithProgress("/path/to/bundle", progress)
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//   - progress: results of an earlier, interrupted run, or nil
+//
+// Returns:
+//   - *VerifyResult: the classified result
+//   - error: if checksums cannot be computed or the bundle cannot be walked
+func (cf *ChecksumFile) VerifyDetailedWithProgress(bundlePath string, progress *VerifyProgress) (*VerifyResult, error) {
+	return cf.VerifyDetailedContext(context.Background(), bundlePath, progress)
+}
+
+// VerifyDetailedContext is VerifyDetailedWithProgress stopping early when
+// ctx is cancelled.
+//
+// Files already being hashed are finished and recorded in progress, but no
+// new ones are started. The progress is saved before ctx.Err() is
+// returned, so the run can be resumed.
+//
+// Example:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+//	defer stop()
+//	result, err := files.VerifyDetailedContext(ctx, "/path/to/bundle", progress)
+//	if errors.Is(err, context.Canceled) {
+//	    fmt.Println("interrupted, run again to resume")
+//	}
+//
+// Parameters:
+//   - ctx: cancels the verification
+//   - bundlePath: absolute or relative path to the bundle directory
+//   - progress: results of an earlier, interrupted run, or nil
+//
+// Returns:
+//   - *VerifyResult: the classified result
+//   - error: ctx.Err() when cancelled, or if checksums cannot be computed
+//     or the bundle cannot be walked
+func (cf *ChecksumFile) VerifyDetailedContext(ctx context.Context, bundlePath string, progress *VerifyProgress) (*VerifyResult, error) {
+	corrupted, err := cf.verifyCorruptions(ctx, bundlePath, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	extra, err := cf.untracked(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VerifyResult{
+		Missing:    []string{},
+		Mismatched: []string{},
+		Extra:      extra,
+		Corrupted:  corrupted,
+	}
+	for _, c := range corrupted {
+		if c.Reason == ReasonMissing {
+			result.Missing = append(result.Missing, c.Path)
+		} else {
+			result.Mismatched = append(result.Mismatched, c.Path)
+		}
+	}
+	sort.Strings(result.Missing)
+	sort.Strings(result.Mismatched)
+	return result, nil
+}
+
+// untracked returns the sorted relative paths of the files in bundlePath
+// that Compute would include but that have no record in cf.
+func (cf *ChecksumFile) untracked(bundlePath string) ([]string, error) {
+	scanned, err := scanner.ScanDirectoryInfoWithOptions(bundlePath, scanner.ScanOptions{
+		Exclude: cf.Exclude,
+		// Unreadable paths are not extra files
+		OnError: func(string, error) error { return nil },
+	})
+	if err != nil {
+		return nil, err
+	}
+	extra := []string{}
+	for _, file := range scanned {
+		if !cf.Filter.Match(file.ModTime) {
+			continue
+		}
+		if _, ok := cf.Lookup(file.RelPath); !ok {
+			extra = append(extra, filepath.ToSlash(file.RelPath))
+		}
+	}
+	sort.Strings(extra)
+	return extra, nil
+}
@@ -0,0 +1,60 @@
+package checksum
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestChecksumFile_VerifyDetailed(t *testing.T) {
+	tmpDir, cf := setupProgressBundle(t)
+
+	if err := os.Remove(filepath.Join(tmpDir, "a.txt")); err != nil {
+		t.Fatalf("failed to remove test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "b.txt"), []byte("B"), 0644); err != nil {
+		t.Fatalf("failed to modify test file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "sub"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "sub", "c.txt"), []byte("c"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".bundle", "META.json"), []byte("{}"), 0644); err != nil {
+		t.Fatalf("failed to create metadata file: %v", err)
+	}
+
+	result, err := cf.VerifyDetailed(tmpDir)
+	if err != nil {
+		t.Fatalf("VerifyDetailed() error = %v", err)
+	}
+	if want := []string{"a.txt"}; !reflect.DeepEqual(result.Missing, want) {
+		t.Errorf("Missing = %v, want %v", result.Missing, want)
+	}
+	if want := []string{"b.txt"}; !reflect.DeepEqual(result.Mismatched, want) {
+		t.Errorf("Mismatched = %v, want %v", result.Mismatched, want)
+	}
+	if want := []string{"sub/c.txt"}; !reflect.DeepEqual(result.Extra, want) {
+		t.Errorf("Extra = %v, want %v", result.Extra, want)
+	}
+	if result.OK() || len(result.Corrupted) != 2 {
+		t.Errorf("OK() = %v with %d corrupted, want false with 2", result.OK(), len(result.Corrupted))
+	}
+
+	// Files outside the modification time window are not extra
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(tmpDir, "sub", "c.txt"), old, old); err != nil {
+		t.Fatalf("failed to set mtime: %v", err)
+	}
+	cf.Filter = ModTimeFilter{After: time.Now().Add(-24 * time.Hour)}
+	result, err = cf.VerifyDetailed(tmpDir)
+	if err != nil {
+		t.Fatalf("VerifyDetailed() error = %v", err)
+	}
+	if len(result.Extra) != 0 {
+		t.Errorf("Extra with filter = %v, want none", result.Extra)
+	}
+}
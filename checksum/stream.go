@@ -27,17 +27,11 @@
 //	bundleChecksum := checksum.ComputeBundleChecksum(checksums)
 package checksum
 
-import (
-	"crypto/sha256"
-	"encoding/hex"
-	"io"
-	"os"
-)
-
 // ComputeFileSHA256 computes the SHA256 checksum of a file using streaming I/O.
 //
-// It uses streaming I/O to avoid loading the entire file into memory, making it
-// suitable for large files. The file is read in chunks and hashed incrementally.
+// It is ComputeFileHash with SHA256, which uses streaming I/O to avoid
+// loading the entire file into memory, making it suitable for large files.
+// The file is read in chunks and hashed incrementally.
 //
 // Example:
 //
@@ -54,16 +48,5 @@ import (
 //   - string: SHA256 checksum as 64 hex characters
 //   - error: if file cannot be opened or read
 func ComputeFileSHA256(filePath string) (string, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "", err
-	}
-	defer file.Close()
-
-	hash := sha256.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		return "", err
-	}
-
-	return hex.EncodeToString(hash.Sum(nil)), nil
+	return ComputeFileHash(filePath, SHA256)
 }
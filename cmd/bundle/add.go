@@ -0,0 +1,92 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// AddCmd represents the add command.
+//
+// It copies files into an existing bundle and updates its manifest and
+// metadata. The bundle checksum changes, so --allow-rechecksum is required.
+//
+// Usage:
+//
+//	bundle add <bundle> <file>... --allow-rechecksum
+var AddCmd = &cobra.Command{
+	Use:   messages.GetUse("add"),
+	Short: messages.GetShort("add"),
+	Long:  messages.GetLong("add"),
+	Run:   handleAddCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(AddCmd)
+	AddCmd.Flags().Bool("allow-rechecksum", false, "accept that adding files changes the bundle checksum")
+}
+
+// handleAddCmd processes the add command.
+//
+// A missing --allow-rechecksum, a path that is not a bundle and files that
+// cannot be added exit 1; other errors exit 2.
+func handleAddCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) < 2 {
+		exitWithUsage(cmd, "Usage: bundle add <bundle> <file>... --allow-rechecksum")
+	}
+	path := args[0]
+	files := args[1:]
+
+	if allow, _ := cmd.Flags().GetBool("allow-rechecksum"); !allow {
+		exitWithError(1, nil, "Adding files changes the bundle checksum of %s; pass --allow-rechecksum to continue", path)
+	}
+
+	b, err := bundle.Load(path)
+	if err != nil {
+		exitIfInvalid(err)
+		exitWithError(utils.ExitCodeFromError(err), err, "Not a bundle: %v", err)
+	}
+	oldChecksum := b.Metadata.BundleChecksum
+
+	b, err = bundle.AddFiles(path, files)
+	if err != nil {
+		exitIfLocked(err)
+		exitWithError(utils.ExitCodeFromError(err), err, "Add failed: %v", err)
+	}
+
+	added := make([]string, len(files))
+	for i, file := range files {
+		added[i] = filepath.Base(file)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"status":       "added",
+			"path":         path,
+			"files":        added,
+			"old_checksum": oldChecksum,
+			"checksum":     b.Metadata.BundleChecksum,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	log.Infof("Added %d file(s) to %s", len(added), path)
+	log.Warnf("Bundle checksum changed: %s → %s", oldChecksum, b.Metadata.BundleChecksum)
+}
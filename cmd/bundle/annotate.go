@@ -0,0 +1,150 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/metadata"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// AnnotateCmd represents the annotate command.
+//
+// It sets and removes custom key/value annotations in .bundle/META.json.
+// Without changes it prints the current annotations.
+//
+// Usage:
+//
+//	bundle annotate <path> [key=value...] [--remove key...]
+//
+// Example:
+//
+//	bundle annotate ./photos project=apollo retention-class=7y
+//	bundle annotate ./photos --remove retention-class
+var AnnotateCmd = &cobra.Command{
+	Use:   messages.GetUse("annotate"),
+	Short: messages.GetShort("annotate"),
+	Long:  messages.GetLong("annotate"),
+	Run:   handleAnnotateCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(AnnotateCmd)
+	AnnotateCmd.Flags().StringArray("remove", nil, "remove the annotation with this key (repeatable)")
+}
+
+// handleAnnotateCmd processes the annotate command.
+//
+// All arguments are validated before the bundle is locked; removals are
+// applied before the new values.
+func handleAnnotateCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) < 1 {
+		exitWithUsage(cmd, "Usage: bundle annotate <path> [key=value...] [--remove key...]")
+	}
+
+	path := args[0]
+	removes, _ := cmd.Flags().GetStringArray("remove")
+	for _, key := range removes {
+		if err := metadata.ValidateAnnotationKey(key); err != nil {
+			exitWithError(1, err, "%v", err)
+		}
+	}
+	keys := []string{}
+	values := map[string]string{}
+	for _, arg := range args[1:] {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			exitWithError(1, nil, "invalid annotation '%s': must be key=value", arg)
+		}
+		if err := metadata.ValidateAnnotationKey(key); err != nil {
+			exitWithError(1, err, "%v", err)
+		}
+		if _, seen := values[key]; !seen {
+			keys = append(keys, key)
+		}
+		values[key] = value
+	}
+
+	// Verify bundle exists
+	b, err := bundle.Load(path)
+	if err != nil {
+		exitIfInvalid(err)
+		if os.IsNotExist(err) || strings.Contains(err.Error(), "not a bundle") {
+			exitWithError(1, err, "Not a bundle: %v", err)
+		}
+		exitWithError(2, err, "System error: %v", err)
+	}
+	if b.Metadata == nil {
+		exitWithError(2, nil, "bundle metadata missing")
+	}
+
+	annotations := b.Metadata.Annotations
+	changed := len(removes) > 0 || len(keys) > 0
+	if changed {
+		err = withBundleLock(path, func() error {
+			for _, key := range removes {
+				if err := metadata.RemoveAnnotation(path, key); err != nil {
+					return err
+				}
+			}
+			for _, key := range keys {
+				if err := metadata.SetAnnotation(path, key, values[key]); err != nil {
+					return err
+				}
+			}
+			meta, err := metadata.Load(path)
+			if err != nil {
+				return err
+			}
+			annotations = meta.Annotations
+			return nil
+		})
+		if err != nil {
+			exitIfLocked(err)
+			exitWithError(2, err, "Failed to update annotations: %v", err)
+		}
+		log.Debugf("Annotations updated: %d set, %d removed", len(keys), len(removes))
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"path":        path,
+			"annotations": annotations,
+		}
+		if changed {
+			out["status"] = "annotated"
+			out["removed"] = removes
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	names := make([]string, 0, len(annotations))
+	for key := range annotations {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	for _, key := range names {
+		fmt.Fprintf(stdout, "%s=%s\n", key, annotations[key])
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/pool"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// CheckoutCmd represents the checkout command.
+//
+// It copies a pooled bundle, given by checksum prefix, to a working
+// directory and verifies the copy.
+//
+// Usage:
+//
+//	bundle checkout <prefix> <dest> [--pool <name>]
+var CheckoutCmd = &cobra.Command{
+	Use:   messages.GetUse("checkout"),
+	Short: messages.GetShort("checkout"),
+	Long:  messages.GetLong("checkout"),
+	Run:   handleCheckoutCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(CheckoutCmd)
+	CheckoutCmd.Flags().StringP("pool", "p", "default", "pool name to check out from")
+}
+
+// handleCheckoutCmd processes the checkout command.
+//
+// Unknown or ambiguous prefixes, a non-empty destination and a copy that
+// fails verification exit 1; other errors exit 2.
+func handleCheckoutCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 2 {
+		exitWithUsage(cmd, "Usage: bundle checkout <prefix> <dest> [--pool <name>]")
+	}
+	dest := args[1]
+
+	poolName, _ := cmd.Flags().GetString("pool")
+	p, err := pool.GetPool(poolName)
+	if err != nil {
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+
+	meta, _, err := p.Get(args[0])
+	if err != nil {
+		exitWithError(utils.ExitCodeFromError(err), err, "%v", err)
+	}
+
+	if err := p.Export(meta.BundleChecksum, dest); err != nil {
+		exitWithError(utils.ExitCodeFromError(err), err, "Checkout failed: %v", err)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"status":   "checked_out",
+			"pool":     poolName,
+			"checksum": meta.BundleChecksum,
+			"title":    meta.Title,
+			"path":     dest,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	log.Infof("Checked out %s (%s) to %s", meta.Title, meta.BundleChecksum[:12], dest)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// CloneCmd represents the clone command.
+//
+// It copies a bundle, metadata included, to a new location and verifies
+// the copy, keeping the bundle's identity.
+//
+// Usage:
+//
+//	bundle clone <src> <dest>
+var CloneCmd = &cobra.Command{
+	Use:   messages.GetUse("clone"),
+	Short: messages.GetShort("clone"),
+	Long:  messages.GetLong("clone"),
+	Run:   handleCloneCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(CloneCmd)
+}
+
+// handleCloneCmd processes the clone command.
+//
+// A source that is not a bundle, a non-empty destination and a copy that
+// fails verification exit 1; other errors exit 2.
+func handleCloneCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 2 {
+		exitWithUsage(cmd, "Usage: bundle clone <src> <dest>")
+	}
+	src := args[0]
+	dest := args[1]
+
+	clone, err := bundle.Clone(src, dest)
+	if err != nil {
+		exitWithError(utils.ExitCodeFromError(err), err, "Clone failed: %v", err)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"status":   "cloned",
+			"source":   src,
+			"path":     dest,
+			"checksum": clone.Metadata.BundleChecksum,
+			"title":    clone.Metadata.Title,
+			"files":    len(clone.Files.Records),
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	log.Infof("Cloned %s (%s) to %s", clone.Metadata.Title, clone.Metadata.BundleChecksum[:12], dest)
+}
@@ -5,8 +5,18 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/checksum"
+	"github.com/jvzantvoort/bundle/lock"
+	"github.com/jvzantvoort/bundle/utils"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // GetString retrieves a string flag value from a cobra command.
@@ -36,3 +46,245 @@ func GetString(cmd cobra.Command, name string) string {
 	}
 	return retv
 }
+
+// csvHeader returns the CSV header row, or nil when --no-header is given.
+func csvHeader(cmd *cobra.Command, columns ...string) []string {
+	if noHeader, _ := cmd.Flags().GetBool("no-header"); noHeader {
+		return nil
+	}
+	return columns
+}
+
+// withBundleLock runs fn while holding the lock on the bundle at path.
+//
+// A lock held by another process is waited for up to --lock-timeout.
+//
+// Example:
+//
+//	err := withBundleLock(path, func() error {
+//	    return metadata.UpdateTitle(path, title)
+//	})
+//
+// Parameters:
+//   - path: bundle directory
+//   - fn: the write operation
+//
+// Returns:
+//   - error: utils.ErrBundleLocked if the lock stays held, or the error of fn
+func withBundleLock(path string, fn func() error) error {
+	bundleLock, err := lock.AcquireLockWait(path, lock.WaitTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := bundleLock.Release(); err != nil {
+			log.Errorf("failed to release lock: %v", err)
+		}
+	}()
+	return fn()
+}
+
+// loadShared loads the bundle at path while holding a shared lock on it,
+// so it is not read while another process rewrites its metadata.
+//
+// A writer holding the lock is waited for up to --lock-timeout. When the
+// shared lock cannot be created for another reason, e.g. on read-only
+// storage or for a directory that is not a bundle, the bundle is loaded
+// without it.
+//
+// Parameters:
+//   - path: bundle directory
+//
+// Returns:
+//   - *bundle.Bundle: the loaded bundle
+//   - error: utils.ErrBundleLocked if the lock stays held, or the error of
+//     bundle.Load
+func loadShared(path string) (*bundle.Bundle, error) {
+	sharedLock, err := lock.AcquireSharedLockWait(path, lock.WaitTimeout)
+	if errors.Is(err, utils.ErrBundleLocked) {
+		return nil, err
+	}
+	if err != nil {
+		log.Debugf("Reading without shared lock: %v", err)
+	} else {
+		defer func() {
+			if err := sharedLock.Release(); err != nil {
+				log.Errorf("failed to release lock: %v", err)
+			}
+		}()
+	}
+	return bundle.Load(path)
+}
+
+// exitIfLocked exits 1 when err means another process holds the bundle
+// lock; other errors are left to the caller.
+func exitIfLocked(err error) {
+	if errors.Is(err, utils.ErrBundleLocked) {
+		exitWithError(1, err, "%v (use --lock-timeout to wait for it)", err)
+	}
+}
+
+// exitIfInvalid exits 1 when err means the bundle metadata failed
+// validation; other errors are left to the caller.
+func exitIfInvalid(err error) {
+	if errors.Is(err, utils.ErrIncompleteBundle) {
+		exitWithError(1, err, "Invalid bundle: %v", err)
+	}
+}
+
+// outputStructured writes data to stdout in the --output format, JSON or
+// YAML; see utils.OutputJSON and utils.OutputYAML.
+//
+// Example:
+//
+//	if structuredOutput {
+//	    if err := outputStructured(out); err != nil {
+//	        log.Errorf("failed to write output: %v", err)
+//	        os.Exit(2)
+//	    }
+//	    return
+//	}
+//
+// Parameters:
+//   - data: any JSON-serializable value
+//
+// Returns:
+//   - error: if encoding or writing fails
+func outputStructured(data interface{}) error {
+	if outputFormat == "yaml" {
+		return utils.OutputYAMLTo(stdout, data)
+	}
+	return utils.OutputJSONTo(stdout, data)
+}
+
+// outputError writes an error object with a stable error code to stdout in
+// the --output format, as utils.OutputJSONErrorCode does for JSON.
+func outputError(code string, message string) error {
+	if outputFormat == "yaml" {
+		return utils.OutputYAMLTo(stdout, map[string]utils.JSONError{"error": {Code: code, Message: message}})
+	}
+	return utils.OutputJSONErrorTo(stdout, code, message)
+}
+
+// exitWithError reports a failed command and exits with code.
+//
+// The message is logged as an error. With --json or --output yaml it is
+// written to stdout as an error object instead, so consumers get a
+// parseable result.
+// Its code is the stable code of the sentinel err wraps (see
+// utils.ErrorCode), or the generic code of the exit code when err is nil
+// or wraps no sentinel.
+//
+// Example:
+//
+//	if err != nil {
+//	    exitWithError(2, err, "System error: %v", err)
+//	}
+//
+// Parameters:
+//   - code: exit code
+//   - err: the underlying error, may be nil
+//   - format: message format, as for fmt.Sprintf
+//   - args: message arguments
+func exitWithError(code int, err error, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if !structuredOutput {
+		log.Error(message)
+	} else {
+		errorCode := utils.ExitErrorCode(code)
+		if specific := utils.ErrorCode(err); specific != "" && specific != utils.ExitErrorCode(utils.ExitCodeFromError(err)) {
+			errorCode = specific
+		}
+		if err := outputError(errorCode, message); err != nil {
+			log.Errorf("failed to write output: %v", err)
+		}
+	}
+	exit(code)
+}
+
+// exitWithUsage reports invalid command line usage and exits 1.
+//
+// The message is logged, followed by the command help; with --json or
+// --output yaml an error object with code "usage" is written instead.
+//
+// Parameters:
+//   - cmd: the command that was invoked
+//   - message: what is wrong with the command line
+func exitWithUsage(cmd *cobra.Command, message string) {
+	if structuredOutput {
+		if err := outputError(utils.ErrorCodeUsage, message); err != nil {
+			log.Errorf("failed to write output: %v", err)
+		}
+		exit(1)
+	}
+	log.Error(message)
+	if err := cmd.Help(); err != nil {
+		log.Error(err)
+	}
+	exit(1)
+}
+
+// jobsFlagUsage is the help text shared by every --jobs flag.
+const jobsFlagUsage = "maximum number of parallel workers (default: max_concurrency setting, or NumCPU capped at 8)"
+
+// ApplyJobsFlag overrides the concurrency settings with --jobs.
+//
+// The settings are only changed when the flag was given on the command line,
+// so the configuration file values apply otherwise. --jobs sets
+// max_concurrency, io_concurrency and hash_concurrency alike, so a single
+// flag bounds every parallel operation for this invocation.
+//
+// Example:
+//
+//	ApplyJobsFlag(cmd)
+//	verified, corrupted, err := bundle.Verify(path)
+//
+// Parameters:
+//   - cmd: cobra command with an int "jobs" flag
+func ApplyJobsFlag(cmd *cobra.Command) {
+	if !cmd.Flags().Changed("jobs") {
+		return
+	}
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	log.Debugf("jobs set to %d", jobs)
+	viper.Set("max_concurrency", jobs)
+	viper.Set("io_concurrency", jobs)
+	viper.Set("hash_concurrency", jobs)
+}
+
+// formatFlagUsage is the help text shared by every --format flag.
+const formatFlagUsage = "render output with a Go template, e.g. '{{.Checksum}} {{.Title}}'"
+
+// progressInterval is the minimum time between two progress line updates.
+const progressInterval = 100 * time.Millisecond
+
+// newProgressLine returns a checksum.ProgressFunc that keeps a single
+// "<label> done/total: file" line up to date on w.
+//
+// Updates are limited to one per progressInterval so fast scans of many
+// small files do not flood the terminal; the last file is always shown and
+// ends the line so following output starts on a fresh line.
+//
+// Example:
+//
+//	files.ComputeWithProgress(path, newProgressLine(os.Stdout, "Hashing"))
+//
+// Parameters:
+//   - w: terminal to write to
+//   - label: text shown before the counts
+//
+// Returns:
+//   - checksum.ProgressFunc: callback for ComputeWithProgress
+func newProgressLine(w io.Writer, label string) checksum.ProgressFunc {
+	var last time.Time
+	return func(done, total int, currentFile string) {
+		if done < total && time.Since(last) < progressInterval {
+			return
+		}
+		last = time.Now()
+		fmt.Fprintf(w, "\r\033[K%s %d/%d: %s", label, done, total, currentFile)
+		if done == total {
+			fmt.Fprintln(w)
+		}
+	}
+}
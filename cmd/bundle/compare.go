@@ -0,0 +1,112 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// CompareCmd represents the compare command.
+//
+// It hashes a live directory and compares it against the manifest of an
+// existing bundle without touching the bundle's state.
+//
+// Usage:
+//
+//	bundle compare <bundle-path> <dir>
+var CompareCmd = &cobra.Command{
+	Use:   messages.GetUse("compare"),
+	Short: messages.GetShort("compare"),
+	Long:  messages.GetLong("compare"),
+	Run:   handleCompareCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(CompareCmd)
+}
+
+// handleCompareCmd processes the compare command.
+func handleCompareCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 2 {
+		exitWithUsage(cmd, "Usage: bundle compare <bundle-path> <dir>")
+	}
+
+	bundlePath := args[0]
+	dir := args[1]
+
+	if !utils.IsBundleDir(bundlePath) {
+		exitWithError(1, utils.ErrNotABundle, "Not a bundle: %s", bundlePath)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			exitWithError(1, err, "Invalid directory: %v", err)
+		}
+		exitWithError(2, err, "System error: %v", err)
+	}
+	if !info.IsDir() {
+		exitWithError(1, utils.ErrInvalidPath, "Invalid directory: not a directory: %s", dir)
+	}
+
+	result, err := bundle.Compare(bundlePath, dir)
+	if err != nil {
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"bundle":    bundlePath,
+			"directory": dir,
+			"identical": result.Identical(),
+			"added":     result.Added,
+			"removed":   result.Removed,
+			"modified":  result.Modified,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	if result.Identical() {
+		log.Info("Directory matches bundle")
+		return
+	}
+
+	if err := utils.WriteTable(stdout, []string{"Status", "Path"}, compareRows(result)); err != nil {
+		exitWithError(2, err, "failed to output table: %v", err)
+	}
+	fmt.Fprintf(stdout, "\nTotal: %d added, %d removed, %d modified\n",
+		len(result.Added), len(result.Removed), len(result.Modified))
+}
+
+// compareRows returns the status and path table rows of result.
+func compareRows(result *bundle.CompareResult) [][]string {
+	rows := make([][]string, 0, len(result.Added)+len(result.Removed)+len(result.Modified))
+	for _, p := range result.Added {
+		rows = append(rows, []string{"added", p})
+	}
+	for _, p := range result.Removed {
+		rows = append(rows, []string{"removed", p})
+	}
+	for _, p := range result.Modified {
+		rows = append(rows, []string{"modified", p})
+	}
+	return rows
+}
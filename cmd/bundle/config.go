@@ -0,0 +1,136 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jvzantvoort/bundle/config"
+	"github.com/jvzantvoort/bundle/messages"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// ConfigCmd represents the config command.
+//
+// It helps to set up and locate the configuration file.
+//
+// Usage:
+//
+//	bundle config init [--force]
+//	bundle config path
+var ConfigCmd = &cobra.Command{
+	Use:   messages.GetUse("config"),
+	Short: messages.GetShort("config"),
+	Long:  messages.GetLong("config"),
+}
+
+func init() {
+	rootCmd.AddCommand(ConfigCmd)
+
+	// Subcommands: init, path
+	ConfigCmd.AddCommand(configInitCmd)
+	ConfigCmd.AddCommand(configPathCmd)
+
+	configInitCmd.Flags().BoolP("force", "f", false, "replace an existing config file")
+}
+
+// config init
+var configInitCmd = &cobra.Command{
+	Use:   messages.GetUse("config_init"),
+	Short: messages.GetShort("config_init"),
+	Long:  messages.GetLong("config_init"),
+	Run:   handleConfigInitCmd,
+}
+
+func handleConfigInitCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 0 {
+		exitWithUsage(cmd, "Usage: bundle config init [--force]")
+	}
+	force, _ := cmd.Flags().GetBool("force")
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		exitWithError(2, err, "System error: %v", err)
+	}
+	path := config.SearchFiles()[0]
+	poolRoot := filepath.Join(home, "bundles")
+
+	if err := config.WriteStarterConfig(path, poolRoot, force); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			exitWithError(1, err, "config file already exists: %s (use --force to replace it)", path)
+		}
+		exitWithError(2, err, "Failed to write config file: %v", err)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"status":    "created",
+			"path":      path,
+			"pool_root": poolRoot,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	log.Infof("Wrote config file %s", path)
+	log.Infof("Default pool root: %s", poolRoot)
+}
+
+// config path
+var configPathCmd = &cobra.Command{
+	Use:   messages.GetUse("config_path"),
+	Short: messages.GetShort("config_path"),
+	Long:  messages.GetLong("config_path"),
+	Run:   handleConfigPathCmd,
+}
+
+func handleConfigPathCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 0 {
+		exitWithUsage(cmd, "Usage: bundle config path")
+	}
+
+	used := viper.ConfigFileUsed()
+	searched := config.SearchFiles()
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"config_file":  used,
+			"search_paths": searched,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	if used != "" {
+		fmt.Fprintln(stdout, used)
+		return
+	}
+	log.Info("No config file loaded; searched:")
+	for _, path := range searched {
+		fmt.Fprintln(stdout, path)
+	}
+}
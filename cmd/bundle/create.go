@@ -4,13 +4,21 @@ Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
 package main
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/scanner"
 	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/checksum"
 	"github.com/jvzantvoort/bundle/utils"
 	"github.com/spf13/cobra"
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 )
 
 // CreateCmd represents the create command
@@ -23,8 +31,54 @@ var CreateCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(CreateCmd)
+	CreateCmd.Flags().Int("jobs", 0, jobsFlagUsage)
 	CreateCmd.Flags().StringP("tag", "T", "", "mark every line with this tag")
 	CreateCmd.Flags().StringP("title", "t", "", "log the contents of this file")
+	CreateCmd.Flags().Bool("strict", false, "abort on the first unreadable path instead of skipping it")
+	CreateCmd.Flags().Bool("compress-manifest", false, "store the checksum manifest gzip-compressed")
+	CreateCmd.Flags().String("manifest-format", "text", "checksum manifest format (text or json)")
+	CreateCmd.Flags().String("modified-after", "", "only include files modified at or after this date (YYYY-MM-DD or RFC 3339)")
+	CreateCmd.Flags().String("modified-before", "", "only include files modified before this date (YYYY-MM-DD or RFC 3339)")
+	CreateCmd.Flags().StringArray("exclude", nil, "leave out paths matching this gitignore-style pattern (repeatable)")
+	CreateCmd.Flags().String("algo", "", "hash algorithm: sha256 or sha512 (default: hash_algorithm setting, or sha256)")
+	CreateCmd.Flags().String("scan-order", "", "order files are hashed in: walk or path (default: scan_order setting, or walk)")
+	CreateCmd.Flags().Bool("no-default-title", false, "keep the title empty when --title is not given")
+	CreateCmd.Flags().Bool("force", false, "recreate the bundle if the directory is already a bundle")
+	CreateCmd.Flags().Bool("reset-metadata", false, "with --force, discard existing tags, title, description, annotations, replicas and creation time")
+	CreateCmd.Flags().Bool("reset", false, "alias for --reset-metadata")
+	_ = CreateCmd.Flags().MarkDeprecated("reset", "use --reset-metadata instead")
+	CreateCmd.Flags().String("description", "", "free-text notes stored in META.json, e.g. provenance")
+	CreateCmd.Flags().String("author", "", "record this author instead of the current user")
+	CreateCmd.Flags().Bool("dry-run", false, "only report the files, size and bundle checksum; write nothing")
+	CreateCmd.Flags().String("created-at", "", "record this RFC 3339 creation time instead of now (default: $SOURCE_DATE_EPOCH when set)")
+}
+
+// createdAtFromFlags returns the pinned creation time: --created-at, else
+// SOURCE_DATE_EPOCH (seconds since the epoch), else zero for "now".
+func createdAtFromFlags(cmd *cobra.Command) (time.Time, error) {
+	var createdAt time.Time
+	if value := GetString(*cmd, "created-at"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("--created-at: %w", err)
+		}
+		if t.IsZero() {
+			return time.Time{}, fmt.Errorf("--created-at: zero timestamp")
+		}
+		createdAt = t
+	} else if value := os.Getenv("SOURCE_DATE_EPOCH"); value != "" {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("SOURCE_DATE_EPOCH: invalid value %q", value)
+		}
+		createdAt = time.Unix(seconds, 0).UTC()
+	} else {
+		return time.Time{}, nil
+	}
+	if createdAt.After(time.Now().Add(bundle.FutureTimestampTolerance)) {
+		return time.Time{}, fmt.Errorf("creation time is in the future: %s", createdAt.UTC().Format(time.RFC3339))
+	}
+	return createdAt, nil
 }
 
 func handleCreateCmd(cmd *cobra.Command, args []string) {
@@ -35,26 +89,121 @@ func handleCreateCmd(cmd *cobra.Command, args []string) {
 	defer log.Debugf("%s: end", cmd.Use)
 
 	if len(args) != 1 {
-		log.Error("No path provided")
-		if err := cmd.Help(); err != nil {
-			log.Error(err)
-		}
-		os.Exit(1)
+		exitWithUsage(cmd, "No path provided")
 	}
 
 	path := args[0]
 	title := GetString(*cmd, "title")
+	force, _ := cmd.Flags().GetBool("force")
+	resetMetadata, _ := cmd.Flags().GetBool("reset-metadata")
+	if reset, _ := cmd.Flags().GetBool("reset"); reset {
+		resetMetadata = true
+	}
 
-	b, err := bundle.Create(path, title)
+	// A forced recreate keeps the existing title unless metadata is reset
+	keepTitle := force && !resetMetadata && utils.IsBundleDir(path)
+	if noDefault, _ := cmd.Flags().GetBool("no-default-title"); title == "" && !noDefault && !keepTitle {
+		title = bundle.DefaultTitle(path)
+		log.Debugf("No title given, using %q", title)
+	}
+	ApplyJobsFlag(cmd)
+	strict, _ := cmd.Flags().GetBool("strict")
+	compress, _ := cmd.Flags().GetBool("compress-manifest")
+	formatName, _ := cmd.Flags().GetString("manifest-format")
+	format, err := checksum.ParseManifestFormat(formatName)
 	if err != nil {
+		exitWithError(1, err, "%v", err)
+	}
+
+	orderName := viper.GetString("scan_order")
+	if cmd.Flags().Changed("scan-order") {
+		orderName, _ = cmd.Flags().GetString("scan-order")
+	}
+	order, err := checksum.ParseScanOrder(orderName)
+	if err != nil {
+		exitWithError(1, err, "%v", err)
+	}
+
+	algoName := viper.GetString("hash_algorithm")
+	if cmd.Flags().Changed("algo") {
+		algoName, _ = cmd.Flags().GetString("algo")
+	}
+	algo, err := checksum.ParseHashAlgorithm(algoName)
+	if err != nil {
+		exitWithError(1, err, "%v", err)
+	}
+
+	var filter checksum.ModTimeFilter
+	bounds := []struct {
+		flag  string
+		bound *time.Time
+	}{{"modified-after", &filter.After}, {"modified-before", &filter.Before}}
+	for _, b := range bounds {
+		if value := GetString(*cmd, b.flag); value != "" {
+			t, err := checksum.ParseFilterDate(value)
+			if err != nil {
+				exitWithError(1, err, "--%s: %v", b.flag, err)
+			}
+			*b.bound = t
+		}
+	}
+	if err := filter.Validate(); err != nil {
+		exitWithError(1, err, "%v", err)
+	}
+
+	exclude, _ := cmd.Flags().GetStringArray("exclude")
+	if err := scanner.Excludes(exclude).Validate(); err != nil {
+		exitWithError(1, err, "%v", err)
+	}
+
+	author := GetString(*cmd, "author")
+	if cmd.Flags().Changed("author") && strings.TrimSpace(author) == "" {
+		exitWithError(1, nil, "invalid author: cannot be empty")
+	}
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	createdAt, err := createdAtFromFlags(cmd)
+	if err != nil {
+		exitWithError(1, err, "%v", err)
+	}
+
+	// Show hashing progress on interactive terminals only
+	var progress checksum.ProgressFunc
+	if !structuredOutput && utils.IsTerminal(os.Stdout) {
+		progress = newProgressLine(os.Stdout, "Hashing")
+	}
+
+	b, err := bundle.CreateWithOptions(path, bundle.CreateOptions{
+		Title:            title,
+		Description:      GetString(*cmd, "description"),
+		Author:           author,
+		Strict:           strict,
+		CompressManifest: compress,
+		ManifestFormat:   format,
+		ScanOrder:        order,
+		ModTimeFilter:    filter,
+		Exclude:          exclude,
+		Force:            force,
+		ResetMetadata:    resetMetadata,
+		HashAlgorithm:    algo,
+		Progress:         progress,
+		DryRun:           dryRun,
+		CreatedAt:        createdAt,
+	})
+	if err != nil {
+		if errors.Is(err, utils.ErrAlreadyABundle) {
+			exitWithError(1, err, "%v (use --force to recreate it)", err)
+		}
 		// Distinguish common user errors vs system errors where possible
 		if os.IsNotExist(err) {
-			log.Errorf("directory does not exist: %s", path)
-			os.Exit(1)
+			exitWithError(1, nil, "directory does not exist: %s", path)
+		}
+		if errors.Is(err, os.ErrPermission) {
+			exitWithError(utils.ExitCodeFromError(err), err, "Permission denied: %v", err)
 		}
-		// lock.AcquireLock returns an error string for lock contention; treat other errors as system errors
-		log.Errorf("System error: %v", err)
-		os.Exit(2)
+		exitIfLocked(err)
+		exitWithError(2, err, "System error: %v", err)
 	}
 
 	// Print a human-readable summary similar to the CLI contract
@@ -73,19 +222,38 @@ func handleCreateCmd(cmd *cobra.Command, args []string) {
 		log.Debugf("Size:     %d bytes", b.State.SizeBytes)
 	}
 
-	if jsonOutput {
+	type skippedPath struct {
+		Path  string `json:"path"`
+		Error string `json:"error"`
+	}
+	skipped := []skippedPath{}
+	if b.Files != nil {
+		for _, e := range b.Files.Errors {
+			skipped = append(skipped, skippedPath{Path: e.Path, Error: e.Err.Error()})
+		}
+	}
+
+	status := "created"
+	if dryRun {
+		status = "dry_run"
+	}
+
+	if structuredOutput {
 		out := map[string]interface{}{
-			"status":     "created",
+			"status":     status,
 			"path":       b.Path,
 			"checksum":   "",
 			"files":      0,
 			"size_bytes": 0,
 			"title":      "",
+			"author":     "",
 			"created_at": "",
+			"skipped":    skipped,
 		}
 		if b.Metadata != nil {
 			out["checksum"] = b.Metadata.BundleChecksum
 			out["title"] = b.Metadata.Title
+			out["author"] = b.Metadata.Author
 			out["created_at"] = b.Metadata.CreatedAt.UTC().Format("2006-01-02T15:04:05Z")
 		}
 		if b.Files != nil {
@@ -95,9 +263,25 @@ func handleCreateCmd(cmd *cobra.Command, args []string) {
 			out["size_bytes"] = b.State.SizeBytes
 		}
 
-		if err := utils.OutputJSON(out); err != nil {
-			log.Errorf("failed to output json: %v", err)
-			os.Exit(2)
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	if dryRun {
+		fmt.Fprintf(stdout, "Files:    %d\n", len(b.Files.Records))
+		fmt.Fprintf(stdout, "Size:     %s (%d bytes)\n", formatBytes(b.State.SizeBytes), b.State.SizeBytes)
+		fmt.Fprintf(stdout, "Checksum: %s\n", b.Metadata.BundleChecksum)
+		if len(skipped) > 0 {
+			log.Infof("%d paths would be skipped due to errors", len(skipped))
 		}
+		log.Info("Dry run: no bundle was written")
+		return
+	}
+
+	if len(skipped) > 0 {
+		log.Infof("Created bundle %q with %d files, %d paths skipped due to errors", b.Metadata.Title, len(b.Files.Records), len(skipped))
 	}
 }
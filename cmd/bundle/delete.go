@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"fmt"
+
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/metadata"
+	"github.com/jvzantvoort/bundle/pool"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// DeleteCmd represents the delete command.
+//
+// It removes a bundle, given by checksum or unique checksum prefix, from a
+// pool after confirmation.
+//
+// Usage:
+//
+//	bundle delete <checksum> [--pool <name>] [--force]
+var DeleteCmd = &cobra.Command{
+	Use:   messages.GetUse("delete"),
+	Short: messages.GetShort("delete"),
+	Long:  messages.GetLong("delete"),
+	Run:   handleDeleteCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(DeleteCmd)
+	DeleteCmd.Flags().StringP("pool", "p", "default", "pool name to delete from")
+	DeleteCmd.Flags().BoolP("force", "f", false, "delete without asking for confirmation")
+}
+
+// handleDeleteCmd processes the delete command.
+//
+// Unknown, ambiguous or invalid checksums and a declined confirmation exit
+// 1; failures to delete exit 2.
+func handleDeleteCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 1 {
+		exitWithUsage(cmd, "Usage: bundle delete <checksum> [--pool <name>] [--force]")
+	}
+
+	poolName, _ := cmd.Flags().GetString("pool")
+	force, _ := cmd.Flags().GetBool("force")
+
+	p, err := pool.GetPool(poolName)
+	if err != nil {
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+
+	sum, err := p.Resolve(args[0])
+	if err != nil {
+		exitWithError(utils.ExitCodeFromError(err), err, "%v", err)
+	}
+
+	size, err := p.DiskUsage(sum)
+	if err != nil {
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	if !force {
+		title := "(unreadable metadata)"
+		if meta, err := metadata.Load(p.GetBundlePath(sum)); err == nil {
+			title = meta.Title
+		}
+		prompt := fmt.Sprintf("Delete bundle %s \"%s\" (%s) from pool '%s'?", sum[:12], title, formatBytes(size), poolName)
+		if !utils.Confirm(prompt) {
+			exitWithError(1, nil, "Delete cancelled")
+		}
+	}
+
+	if err := p.Delete(sum); err != nil {
+		exitWithError(utils.ExitCodeFromError(err), err, "Delete failed: %v", err)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"status":          "deleted",
+			"pool":            poolName,
+			"checksum":        sum,
+			"reclaimed_bytes": size,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	log.Infof("Deleted bundle %s from pool '%s', reclaimed %s", sum, poolName, formatBytes(size))
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// DiffCmd represents the diff command.
+//
+// It compares the manifests of two bundles, e.g. successive snapshots,
+// without hashing any file.
+//
+// Usage:
+//
+//	bundle diff <pathA> <pathB>
+var DiffCmd = &cobra.Command{
+	Use:   messages.GetUse("diff"),
+	Short: messages.GetShort("diff"),
+	Long:  messages.GetLong("diff"),
+	Run:   handleDiffCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(DiffCmd)
+}
+
+// handleDiffCmd processes the diff command.
+func handleDiffCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 2 {
+		exitWithUsage(cmd, "Usage: bundle diff <pathA> <pathB>")
+	}
+
+	pathA := args[0]
+	pathB := args[1]
+	for _, path := range args {
+		if !utils.IsBundleDir(path) {
+			exitWithError(1, utils.ErrNotABundle, "Not a bundle: %s", path)
+		}
+	}
+
+	result, err := bundle.Diff(pathA, pathB)
+	if err != nil {
+		if errors.Is(err, bundle.ErrAlgorithmMismatch) {
+			exitWithError(1, err, "%v", err)
+		}
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"bundle_a":  pathA,
+			"bundle_b":  pathB,
+			"identical": result.Identical(),
+			"added":     result.Added,
+			"removed":   result.Removed,
+			"modified":  result.Modified,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	if result.Identical() {
+		log.Info("Bundles have the same files")
+		return
+	}
+
+	if err := utils.WriteTable(stdout, []string{"Status", "Path"}, compareRows(&result.CompareResult)); err != nil {
+		exitWithError(2, err, "failed to output table: %v", err)
+	}
+	fmt.Fprintf(stdout, "\nTotal: %d added, %d removed, %d modified\n",
+		len(result.Added), len(result.Removed), len(result.Modified))
+}
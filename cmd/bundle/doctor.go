@@ -0,0 +1,225 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jvzantvoort/bundle/config"
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/pool"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Check results reported by the doctor command
+const (
+	checkPass = "pass"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// DoctorCmd represents the doctor command.
+//
+// It diagnoses common configuration problems such as a missing or
+// unparseable config file and pool roots that are not writable.
+//
+// Usage:
+//
+//	bundle doctor
+var DoctorCmd = &cobra.Command{
+	Use:   messages.GetUse("doctor"),
+	Short: messages.GetShort("doctor"),
+	Long:  messages.GetLong("doctor"),
+	Run:   handleDoctorCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(DoctorCmd)
+}
+
+// doctorCheck is a single line of the doctor checklist.
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// handleDoctorCmd processes the doctor command.
+//
+// It runs all checks, prints the checklist and effective settings, and
+// exits with code 1 when any check failed.
+func handleDoctorCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	checks := []doctorCheck{}
+	checks = append(checks, checkConfigFile())
+	checks = append(checks, checkHomeDirs()...)
+	checks = append(checks, checkPools()...)
+
+	overall := checkPass
+	for _, c := range checks {
+		if c.Status == checkFail {
+			overall = checkFail
+			break
+		}
+		if c.Status == checkWarn {
+			overall = checkWarn
+		}
+	}
+
+	pools := map[string]string{}
+	for name := range viper.GetStringMap("pools") {
+		pools[name] = viper.GetString(fmt.Sprintf("pools.%s.root", name))
+	}
+	settings := map[string]interface{}{
+		"config_file": viper.ConfigFileUsed(),
+		"log_level":   viper.GetString("log_level"),
+		"log_format":  logFormat,
+		"pools":       pools,
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"status":   overall,
+			"checks":   checks,
+			"settings": settings,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+	} else {
+		rows := make([][]string, 0, len(checks))
+		for _, c := range checks {
+			rows = append(rows, []string{c.Name, c.Status, c.Detail})
+		}
+		if err := utils.WriteTable(stdout, []string{"Check", "Status", "Detail"}, rows); err != nil {
+			exitWithError(2, err, "failed to output table: %v", err)
+		}
+
+		fmt.Fprintf(stdout, "\nEffective settings:\n")
+		fmt.Fprintf(stdout, "  config_file: %s\n", settings["config_file"])
+		fmt.Fprintf(stdout, "  log_level:   %s\n", settings["log_level"])
+		fmt.Fprintf(stdout, "  log_format:  %s\n", settings["log_format"])
+		names := make([]string, 0, len(pools))
+		for name := range pools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(stdout, "  pools.%s.root: %s\n", name, pools[name])
+		}
+	}
+
+	if overall == checkFail {
+		exit(1)
+	}
+}
+
+// checkConfigFile reports whether a config file was found and parsed.
+func checkConfigFile() doctorCheck {
+	c := doctorCheck{Name: "config file"}
+	err := config.LoadError()
+
+	var notFound viper.ConfigFileNotFoundError
+	switch {
+	case err == nil:
+		c.Status, c.Detail = checkPass, viper.ConfigFileUsed()
+	case errors.As(err, &notFound):
+		c.Status, c.Detail = checkWarn, "no config.yaml found, using defaults"
+	default:
+		c.Status, c.Detail = checkFail, err.Error()
+	}
+	return c
+}
+
+// checkHomeDirs reports whether the home and user config directories exist.
+func checkHomeDirs() []doctorCheck {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return []doctorCheck{{Name: "home directory", Status: checkFail, Detail: err.Error()}}
+	}
+
+	checks := []doctorCheck{}
+	if info, err := os.Stat(home); err != nil || !info.IsDir() {
+		checks = append(checks, doctorCheck{Name: "home directory", Status: checkFail, Detail: home + " does not exist"})
+	} else {
+		checks = append(checks, doctorCheck{Name: "home directory", Status: checkPass, Detail: home})
+	}
+
+	configDir := filepath.Join(home, ".config", "bundle")
+	if info, err := os.Stat(configDir); err != nil || !info.IsDir() {
+		checks = append(checks, doctorCheck{Name: "config directory", Status: checkWarn, Detail: configDir + " does not exist"})
+	} else {
+		checks = append(checks, doctorCheck{Name: "config directory", Status: checkPass, Detail: configDir})
+	}
+	return checks
+}
+
+// checkPools reports on every configured pool's root directory.
+func checkPools() []doctorCheck {
+	pools, err := pool.ListPools()
+	if err != nil {
+		return []doctorCheck{{Name: "pools", Status: checkFail, Detail: err.Error()}}
+	}
+	if len(pools) == 0 {
+		return []doctorCheck{{Name: "pools", Status: checkWarn, Detail: "no pools configured"}}
+	}
+
+	checks := []doctorCheck{}
+	if _, ok := pools["default"]; !ok {
+		checks = append(checks, doctorCheck{Name: "pools", Status: checkWarn, Detail: "no 'default' pool configured"})
+	}
+
+	names := make([]string, 0, len(pools))
+	for name := range pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		root := pools[name].Root
+		c := doctorCheck{Name: "pool " + name, Status: checkPass, Detail: root}
+
+		info, err := os.Stat(root)
+		switch {
+		case os.IsNotExist(err):
+			c.Status, c.Detail = checkWarn, root+" does not exist (created on first import)"
+		case err != nil:
+			c.Status, c.Detail = checkFail, err.Error()
+		case !info.IsDir():
+			c.Status, c.Detail = checkFail, root+" is not a directory"
+		default:
+			if err := checkWritable(root); err != nil {
+				c.Status, c.Detail = checkFail, root+" is not writable: "+err.Error()
+			}
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// checkWritable creates and removes a temporary file in dir.
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".bundle-doctor-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
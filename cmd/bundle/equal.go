@@ -0,0 +1,88 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"fmt"
+
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// EqualCmd represents the equal command.
+//
+// It reports whether two bundles have the same content by comparing their
+// bundle checksums, exiting 0 when they are equal and 1 when they differ.
+//
+// Usage:
+//
+//	bundle equal <pathA> <pathB> [--deep]
+var EqualCmd = &cobra.Command{
+	Use:   messages.GetUse("equal"),
+	Short: messages.GetShort("equal"),
+	Long:  messages.GetLong("equal"),
+	Run:   handleEqualCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(EqualCmd)
+	EqualCmd.Flags().Bool("deep", false, "verify both bundles on disk before comparing")
+}
+
+// handleEqualCmd processes the equal command.
+func handleEqualCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 2 {
+		exitWithUsage(cmd, "Usage: bundle equal <pathA> <pathB>")
+	}
+	for _, path := range args {
+		if !utils.IsBundleDir(path) {
+			exitWithError(1, utils.ErrNotABundle, "Not a bundle: %s", path)
+		}
+	}
+	deep, _ := cmd.Flags().GetBool("deep")
+
+	result, err := bundle.Equal(args[0], args[1], deep)
+	if err != nil {
+		exitIfLocked(err)
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	code := 0
+	if !result.Equal {
+		code = 1
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"equal":      result.Equal,
+			"checksum_a": result.ChecksumA,
+			"checksum_b": result.ChecksumB,
+		}
+		if result.Reason != "" {
+			out["reason"] = result.Reason
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		exit(code)
+	}
+
+	if result.Equal {
+		fmt.Fprintf(stdout, "Equal: %s\n", result.ChecksumA)
+		return
+	}
+	fmt.Fprintf(stdout, "Not equal: %s\n", result.Reason)
+	fmt.Fprintf(stdout, "  A: %s\n  B: %s\n", result.ChecksumA, result.ChecksumB)
+	exit(code)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"fmt"
+
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/pool"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// GCCmd represents the pool gc command.
+//
+// It removes partial imports and other incomplete bundle directories from
+// a pool.
+//
+// Usage:
+//
+//	bundle pool gc [--pool <name>] [--dry-run]
+var GCCmd = &cobra.Command{
+	Use:   messages.GetUse("pool_gc"),
+	Short: messages.GetShort("pool_gc"),
+	Long:  messages.GetLong("pool_gc"),
+	Run:   handleGCCmd,
+}
+
+func init() {
+	PoolCmd.AddCommand(GCCmd)
+	GCCmd.Flags().StringP("pool", "p", "default", "pool name to clean up")
+	GCCmd.Flags().BoolP("dry-run", "n", false, "list what would be removed without removing it")
+}
+
+// handleGCCmd processes the gc command.
+//
+// Removing anything needs confirmation or --yes. An unknown pool and a
+// declined confirmation exit 1; failures to scan the pool or remove a
+// directory exit 2.
+func handleGCCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	poolName, _ := cmd.Flags().GetString("pool")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	p, err := pool.GetPool(poolName)
+	if err != nil {
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+
+	opts := pool.GCOptions{DryRun: dryRun}
+	opts.Confirm = func(garbage []string) bool {
+		for _, dir := range garbage {
+			log.Infof("Would remove %s", dir)
+		}
+		prompt := fmt.Sprintf("Remove %d directories from pool '%s'?", len(garbage), poolName)
+		if !utils.Confirm(prompt) {
+			exitWithError(1, nil, "GC cancelled")
+		}
+		return true
+	}
+	removed, err := p.GCWithOptions(opts)
+	if err != nil {
+		exitWithError(2, err, "GC failed: %v", err)
+	}
+
+	if structuredOutput {
+		status := "collected"
+		if dryRun {
+			status = "dry_run"
+		}
+		if removed == nil {
+			removed = []string{}
+		}
+		out := map[string]interface{}{
+			"status":  status,
+			"pool":    poolName,
+			"removed": removed,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	if len(removed) == 0 {
+		log.Infof("Pool '%s' holds no incomplete bundles", poolName)
+		return
+	}
+	action := "Removed"
+	if dryRun {
+		action = "Would remove"
+	}
+	for _, dir := range removed {
+		log.Infof("%s %s", action, dir)
+	}
+	log.Infof("%s %d directories from pool '%s'", action, len(removed), poolName)
+}
@@ -4,7 +4,9 @@ Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
 package main
 
 import (
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/jvzantvoort/bundle/messages"
 	"github.com/jvzantvoort/bundle/pool"
@@ -25,6 +27,8 @@ func init() {
 	rootCmd.AddCommand(ImportCmd)
 	ImportCmd.Flags().StringP("pool", "p", "default", "pool name to import to")
 	ImportCmd.Flags().BoolP("move", "m", false, "move bundle instead of copy")
+	ImportCmd.Flags().BoolP("quiet", "q", false, "do not print the pre-import summary")
+	ImportCmd.Flags().Bool("hardlink", false, "hard-link files into the pool instead of copying them")
 }
 
 func handleImportCmd(cmd *cobra.Command, args []string) {
@@ -35,31 +39,53 @@ func handleImportCmd(cmd *cobra.Command, args []string) {
 	defer log.Debugf("%s: end", cmd.Use)
 
 	if len(args) != 1 {
-		log.Error("Usage: bundle import <path> [--pool <name>] [--move]")
-		if err := cmd.Help(); err != nil {
-			log.Error(err)
-		}
-		os.Exit(1)
+		exitWithUsage(cmd, "Usage: bundle import <path> [--pool <name>] [--move]")
 	}
 
 	bundlePath := args[0]
 	poolName, _ := cmd.Flags().GetString("pool")
 	moveFlag, _ := cmd.Flags().GetBool("move")
+	hardlinkFlag, _ := cmd.Flags().GetBool("hardlink")
 
 	// Get pool configuration
 	p, err := pool.GetPool(poolName)
 	if err != nil {
-		log.Errorf("Pool error: %v", err)
-		os.Exit(1)
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+
+	quiet, _ := cmd.Flags().GetBool("quiet")
+
+	// Show what will be transferred
+	preview, err := pool.PreviewImport(bundlePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			exitWithError(1, utils.ErrNotABundle, "Not a bundle: %s", bundlePath)
+		}
+		exitWithError(2, err, "System error: %v", err)
+	}
+	if !quiet && !structuredOutput {
+		estimate := "unknown"
+		if preview.EstimatedSeconds > 0 {
+			estimate = time.Duration(preview.EstimatedSeconds * float64(time.Second)).Round(time.Second).String()
+		}
+		log.Infof("Import: %d files, %s, estimated time %s", preview.Files, formatBytes(preview.SizeBytes), estimate)
+	}
+
+	if limit := pool.ImportConfirmSize(); preview.SizeBytes > limit {
+		prompt := fmt.Sprintf("Bundle is %s, above import_confirm_size (%s). Import it?",
+			formatBytes(preview.SizeBytes), formatBytes(limit))
+		if !utils.Confirm(prompt) {
+			exitWithError(1, nil, "Import cancelled")
+		}
 	}
 
 	// Import bundle
-	if err := p.Import(bundlePath, moveFlag); err != nil {
-		log.Errorf("Import failed: %v", err)
-		os.Exit(2)
+	opts := pool.ImportOptions{Move: moveFlag, Hardlink: hardlinkFlag}
+	if err := p.ImportWithOptions(bundlePath, opts); err != nil {
+		exitWithError(2, err, "Import failed: %v", err)
 	}
 
-	if jsonOutput {
+	if structuredOutput {
 		operation := "copied"
 		if moveFlag {
 			operation = "moved"
@@ -71,10 +97,12 @@ func handleImportCmd(cmd *cobra.Command, args []string) {
 			"pool":      poolName,
 			"pool_root": p.Root,
 			"source":    bundlePath,
+			"hardlink":  hardlinkFlag,
+			"preview":   preview,
 		}
-		if err := utils.OutputJSON(out); err != nil {
-			log.Errorf("failed to output json: %v", err)
-			os.Exit(2)
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
 		}
 		return
 	}
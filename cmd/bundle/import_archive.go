@@ -0,0 +1,83 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"os"
+
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// ImportArchiveCmd represents the import-archive command.
+//
+// It unpacks a tar or tar.gz bundle archive into a new directory and
+// verifies the result against the stored checksums.
+//
+// Usage:
+//
+//	bundle import-archive <archive> <dest> [--verify=false]
+var ImportArchiveCmd = &cobra.Command{
+	Use:   messages.GetUse("import_archive"),
+	Short: messages.GetShort("import_archive"),
+	Long:  messages.GetLong("import_archive"),
+	Run:   handleImportArchiveCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(ImportArchiveCmd)
+	ImportArchiveCmd.Flags().Bool("verify", true, "verify the unpacked bundle against its checksums")
+}
+
+// handleImportArchiveCmd processes the import-archive command.
+//
+// User errors (missing archive, non-empty destination, unsafe entries, no
+// bundle in the archive, failed verification) exit 1; other errors exit 2.
+func handleImportArchiveCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 2 {
+		exitWithUsage(cmd, "Usage: bundle import-archive <archive> <dest>")
+	}
+	archivePath, dest := args[0], args[1]
+
+	if _, err := os.Stat(archivePath); os.IsNotExist(err) {
+		exitWithError(1, nil, "Archive not found: %s", archivePath)
+	}
+
+	verify, _ := cmd.Flags().GetBool("verify")
+	b, err := bundle.ImportArchiveWithOptions(archivePath, dest, bundle.ImportArchiveOptions{SkipVerify: !verify})
+	if err != nil {
+		exitWithError(utils.ExitCodeFromError(err), err, "Import failed: %v", err)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"status":   "imported",
+			"archive":  archivePath,
+			"path":     dest,
+			"title":    b.Metadata.Title,
+			"checksum": b.Metadata.BundleChecksum,
+			"files":    len(b.Files.Records),
+			"verified": verify,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	log.Infof("Imported %s to %s (%d files)", b.Metadata.Title, dest, len(b.Files.Records))
+	if !verify {
+		log.Warn("Bundle was not verified; run 'bundle verify' before relying on it")
+	}
+}
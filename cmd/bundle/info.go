@@ -4,10 +4,12 @@ Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
 package main
 
 import (
-	"os"
+	"time"
 
 	"github.com/jvzantvoort/bundle/messages"
 	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/checksum"
+	"github.com/jvzantvoort/bundle/state"
 	"github.com/jvzantvoort/bundle/utils"
 	"github.com/spf13/cobra"
 	log "github.com/sirupsen/logrus"
@@ -25,6 +27,36 @@ func init() {
 	rootCmd.AddCommand(InfoCmd)
 	InfoCmd.Flags().StringP("tag", "T", "", "mark every line with this tag")
 	InfoCmd.Flags().StringP("title", "t", "", "log the contents of this file")
+	InfoCmd.Flags().String("format", "", formatFlagUsage)
+	InfoCmd.Flags().Int("history", 5, "number of recent verifications to include (-1 for all)")
+}
+
+// infoResult is the result of the info command, used for JSON and --format
+type infoResult struct {
+	Path        string   `json:"path"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Checksum    string   `json:"checksum"`
+	Algorithm   string   `json:"algorithm"`
+	Files       int      `json:"files"`
+	SizeBytes   int64    `json:"size_bytes"`
+	CreatedAt   string   `json:"created_at"`
+	Author      string   `json:"author"`
+	Verified    *bool    `json:"verified"`
+	Stale       bool     `json:"stale"`
+	Tags        []string `json:"tags"`
+	Replicas    []string `json:"replicas"`
+
+	ModifiedAfter  string            `json:"modified_after,omitempty"`
+	ModifiedBefore string            `json:"modified_before,omitempty"`
+	Exclude        []string          `json:"exclude,omitempty"`
+	Annotations    map[string]string `json:"annotations,omitempty"`
+
+	// Most recent verifications, oldest first
+	History []state.VerificationEvent `json:"history"`
+
+	// Timestamps in the future; repair --fix-timestamps re-stamps them
+	FutureTimestamps []bundle.TimestampIssue `json:"future_timestamps,omitempty"`
 }
 
 func handleInfoCmd(cmd *cobra.Command, args []string) {
@@ -35,18 +67,34 @@ func handleInfoCmd(cmd *cobra.Command, args []string) {
 	defer log.Debugf("%s: end", cmd.Use)
 
 	if len(args) != 1 {
-		log.Error("No path provided")
-		if err := cmd.Help(); err != nil {
-			log.Error(err)
-		}
-		os.Exit(1)
+		exitWithUsage(cmd, "No path provided")
 	}
 
-	path := args[0]
-	b, err := bundle.Load(path)
+	showInfo(cmd, args[0])
+}
+
+// showInfo prints the information of the bundle at path in the format
+// selected by the flags of cmd. It is shared by info and show.
+func showInfo(cmd *cobra.Command, path string) {
+	b, err := loadShared(path)
 	if err != nil {
-		log.Errorf("System error: %v", err)
-		os.Exit(2)
+		exitIfLocked(err)
+		exitIfInvalid(err)
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	// Future timestamps break age-based queries; info only reports them
+	issues := b.FutureTimestamps(time.Now())
+	for _, issue := range issues {
+		log.Warnf("Suspicious timestamp: %s", issue)
+	}
+	if len(issues) > 0 {
+		log.Warnf("Run 'bundle repair --fix-timestamps %s' to re-stamp them", path)
+	}
+
+	algorithm := checksum.Algorithm
+	if b.Metadata != nil && b.Metadata.HashAlgorithm != "" {
+		algorithm = b.Metadata.HashAlgorithm
 	}
 
 	// Human-readable summary
@@ -55,45 +103,87 @@ func handleInfoCmd(cmd *cobra.Command, args []string) {
 	log.Debugf("Path:     %s", b.Path)
 	if b.Metadata != nil {
 		log.Debugf("Title:    %s", b.Metadata.Title)
+		if b.Metadata.Description != "" {
+			log.Debugf("Description: %s", b.Metadata.Description)
+		}
 		log.Debugf("Checksum: %s", b.Metadata.BundleChecksum)
+		log.Debugf("Algorithm: %s", algorithm)
 		log.Debugf("Author:   %s", b.Metadata.Author)
 		log.Debugf("Created:  %s", b.Metadata.CreatedAt.Format("2006-01-02 15:04:05"))
 	}
 	if b.State != nil {
 		log.Debugf("Files:    %d", len(b.Files.Records))
 		log.Debugf("Size:     %d", b.State.SizeBytes)
+		for _, event := range b.State.RecentHistory(historyCount(cmd)) {
+			log.Debugf("Verified: %s %v (%d corrupted)", event.Time.Format("2006-01-02 15:04:05"), event.Verified, event.Corrupted)
+		}
 	}
 
-	if jsonOutput {
-		out := map[string]interface{}{
-			"path":       b.Path,
-			"title":      "",
-			"checksum":   "",
-			"files":      0,
-			"size_bytes": 0,
-			"created_at": "",
-			"author":     "",
-			"verified":   nil,
-			"tags":       []string{},
-			"replicas":   []string{},
+	result := infoResult{
+		Path:      b.Path,
+		Algorithm: algorithm,
+		Tags:      []string{},
+		Replicas:  []string{},
+		History:   []state.VerificationEvent{},
+
+		FutureTimestamps: issues,
+	}
+	if b.Metadata != nil {
+		result.Title = b.Metadata.Title
+		result.Description = b.Metadata.Description
+		result.Checksum = b.Metadata.BundleChecksum
+		result.CreatedAt = b.Metadata.CreatedAt.UTC().Format("2006-01-02T15:04:05Z")
+		result.Author = b.Metadata.Author
+		if b.Metadata.ModifiedAfter != nil {
+			result.ModifiedAfter = b.Metadata.ModifiedAfter.Format(time.RFC3339)
 		}
-		if b.Metadata != nil {
-			out["title"] = b.Metadata.Title
-			out["checksum"] = b.Metadata.BundleChecksum
-			out["created_at"] = b.Metadata.CreatedAt.UTC().Format("2006-01-02T15:04:05Z")
-			out["author"] = b.Metadata.Author
+		if b.Metadata.ModifiedBefore != nil {
+			result.ModifiedBefore = b.Metadata.ModifiedBefore.Format(time.RFC3339)
 		}
-		if b.State != nil {
-			out["files"] = len(b.Files.Records)
-			out["size_bytes"] = b.State.SizeBytes
-			out["verified"] = b.State.Verified
+		result.Exclude = b.Metadata.Exclude
+		result.Annotations = b.Metadata.Annotations
+	}
+	if b.State != nil {
+		result.Files = len(b.Files.Records)
+		result.SizeBytes = b.State.SizeBytes
+		result.Verified = &b.State.Verified
+		result.Stale = b.State.IsStale(state.VerifyMaxAge())
+		result.History = b.State.RecentHistory(historyCount(cmd))
+	}
+	if b.Tags != nil {
+		result.Tags = b.Tags.List()
+	}
+
+	if format := GetString(*cmd, "format"); format != "" {
+		if err := utils.OutputTemplateTo(stdout, format, result); err != nil {
+			exitWithError(1, err, "Format error: %v", err)
 		}
-		if b.Tags != nil {
-			out["tags"] = b.Tags.List()
+		return
+	}
+
+	if structuredOutput {
+		if err := outputStructured(result); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
 		}
-		if err := utils.OutputJSON(out); err != nil {
-			log.Errorf("failed to output json: %v", err)
-			os.Exit(2)
+		return
+	}
+
+	if result.Stale {
+		if b.State.LastChecked.IsZero() {
+			log.Warn("Verification: STALE (never checked)")
+		} else {
+			log.Warnf("Verification: STALE (last checked %s)", daysAgo(b.State.LastChecked))
 		}
 	}
 }
+
+// historyCount returns the number of verifications to show, from the
+// --history flag when cmd has one (show shares showInfo but not the flag).
+func historyCount(cmd *cobra.Command) int {
+	if cmd.Flags().Lookup("history") == nil {
+		return 5
+	}
+	n, _ := cmd.Flags().GetInt("history")
+	return n
+}
@@ -4,13 +4,17 @@ Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
 package main
 
 import (
+    "fmt"
     "os"
     "path/filepath"
+    "sort"
     "strings"
     "strconv"
 
     "github.com/jvzantvoort/bundle/messages"
     "github.com/jvzantvoort/bundle/bundle"
+    "github.com/jvzantvoort/bundle/checksum"
+    "github.com/jvzantvoort/bundle/scanner"
     "github.com/jvzantvoort/bundle/utils"
     "github.com/spf13/cobra"
     log "github.com/sirupsen/logrus"
@@ -22,10 +26,18 @@ var ListCmd = &cobra.Command{
     Short: messages.GetShort("list"),
     Long:  messages.GetLong("list"),
     Run:   handleListCmd,
+
+    Annotations: map[string]string{csvAnnotation: "true"},
 }
 
 func init() {
     rootCmd.AddCommand(ListCmd)
+    ListCmd.Flags().Bool("include-meta", false, "also list the .bundle/ metadata files (diagnostic)")
+    ListCmd.Flags().String("format", "", formatFlagUsage)
+    ListCmd.Flags().Bool("count", false, "print only the number of files and their total size")
+    ListCmd.Flags().String("sort", "path", "sort files by path, size or checksum")
+    ListCmd.Flags().Bool("reverse", false, "reverse the sort order")
+    ListCmd.Flags().Bool("no-header", false, "leave out the header row of --output csv")
 }
 
 func handleListCmd(cmd *cobra.Command, args []string) {
@@ -36,40 +48,38 @@ func handleListCmd(cmd *cobra.Command, args []string) {
     defer log.Debugf("%s: end", cmd.Use)
 
     if len(args) != 1 {
-        log.Error("No path provided")
-        if err := cmd.Help(); err != nil {
-            log.Error(err)
-        }
-        os.Exit(1)
+        exitWithUsage(cmd, "No path provided")
     }
 
     path := args[0]
-    b, err := bundle.Load(path)
+    b, err := loadShared(path)
     if err != nil {
+        exitIfLocked(err)
+        exitIfInvalid(err)
         if os.IsNotExist(err) || strings.Contains(err.Error(), "not a bundle") {
-            log.Errorf("Not a bundle: %v", err)
-            os.Exit(1)
+            exitWithError(1, err, "Not a bundle: %v", err)
         }
-        log.Errorf("System error: %v", err)
-        os.Exit(2)
+        exitWithError(2, err, "System error: %v", err)
+    }
+
+    if count, _ := cmd.Flags().GetBool("count"); count {
+        listCount(b)
+        return
     }
 
-    // Prepare file entries
-    type fileEntry struct {
-        Path     string `json:"path"`
-        Checksum string `json:"checksum"`
-        Size     int64  `json:"size_bytes"`
+    includeMeta, _ := cmd.Flags().GetBool("include-meta")
+    sortKey := GetString(*cmd, "sort")
+    reverse, _ := cmd.Flags().GetBool("reverse")
+    if _, ok := fileEntryLess[sortKey]; !ok {
+        exitWithError(1, nil, "invalid sort key '%s': must be path, size or checksum", sortKey)
     }
 
+    sizes := fileSizes(b)
     entries := []fileEntry{}
     var totalSize int64
     for _, r := range b.Files.Records {
-        p := filepath.Join(b.Path, r.FilePath)
-        var size int64
-        if info, err := os.Stat(p); err == nil {
-            size = info.Size()
-            totalSize += size
-        }
+        size := sizes[filepath.ToSlash(r.FilePath)]
+        totalSize += size
         entries = append(entries, fileEntry{
             Path:     r.FilePath,
             Checksum: r.Checksum,
@@ -77,28 +87,195 @@ func handleListCmd(cmd *cobra.Command, args []string) {
         })
     }
 
-    if jsonOutput {
+    sortFileEntries(entries, sortKey, reverse)
+
+    var metaEntries []fileEntry
+    if includeMeta {
+        metaEntries, err = listMetaFiles(b.Path, b.Files.Algorithm)
+        if err != nil {
+            exitWithError(2, err, "System error: %v", err)
+        }
+        sortFileEntries(metaEntries, sortKey, reverse)
+    }
+
+    if format := GetString(*cmd, "format"); format != "" {
+        if err := utils.OutputTemplateTo(stdout, format, append(entries, metaEntries...)); err != nil {
+            exitWithError(1, err, "Format error: %v", err)
+        }
+        return
+    }
+
+    if structuredOutput {
         out := map[string]interface{}{
             "path":       b.Path,
             "files":      entries,
             "total_files": len(entries),
             "total_size": totalSize,
         }
-        if err := utils.OutputJSON(out); err != nil {
-            log.Errorf("failed to output json: %v", err)
-            os.Exit(2)
+        if includeMeta {
+            out["meta_files"] = metaEntries
+        }
+        if err := outputStructured(out); err != nil {
+            log.Errorf("failed to write output: %v", err)
+            exit(2)
+        }
+        return
+    }
+
+    if outputFormat == "csv" {
+        rows := [][]string{}
+        for _, e := range append(entries, metaEntries...) {
+            rows = append(rows, []string{e.Path, e.Checksum, strconv.FormatInt(e.Size, 10)})
+        }
+        if err := utils.OutputCSV(csvHeader(cmd, "path", "checksum", "size"), rows, stdout); err != nil {
+            exitWithError(2, err, "failed to output csv: %v", err)
         }
         return
     }
 
     // Human-readable table output
-    table := utils.OutputTable(os.Stdout)
-    table.Header("Filename", "Checksum", "Size")
-    for _, e := range entries {
-        _ = table.Append([]string{e.Path, e.Checksum, formatBytes(e.Size)})
+    if err := utils.WriteTable(stdout, []string{"Filename", "Checksum", "Size"}, fileRows(entries)); err != nil {
+        exitWithError(2, err, "failed to output table: %v", err)
     }
-    _ = table.Render()
     log.Debugf("\nTotal: %d files, %s", len(entries), formatBytes(totalSize))
+
+    if includeMeta {
+        // Metadata files are not part of the bundle checksum; keep them apart
+        log.Info("Metadata files (not part of the bundle checksum):")
+        if err := utils.WriteTable(stdout, []string{"Metadata File", "Checksum", "Size"}, fileRows(metaEntries)); err != nil {
+            exitWithError(2, err, "failed to output table: %v", err)
+        }
+    }
+}
+
+// listCount prints the file count and total size of b.
+//
+// The size comes from STATE.json, so no file is stat'ed; only bundles
+// without a recorded size fall back to scanning the bundle.
+func listCount(b *bundle.Bundle) {
+    var totalSize int64
+    if b.State != nil && b.State.SizeBytes > 0 {
+        totalSize = b.State.SizeBytes
+    } else {
+        sizes := fileSizes(b)
+        for _, r := range b.Files.Records {
+            totalSize += sizes[filepath.ToSlash(r.FilePath)]
+        }
+    }
+
+    if structuredOutput {
+        out := map[string]interface{}{
+            "path":        b.Path,
+            "total_files": len(b.Files.Records),
+            "total_size":  totalSize,
+        }
+        if err := outputStructured(out); err != nil {
+            log.Errorf("failed to write output: %v", err)
+            exit(2)
+        }
+        return
+    }
+    fmt.Fprintf(stdout, "%d files, %s\n", len(b.Files.Records), formatBytes(totalSize))
+}
+
+// fileSizes returns the current size of the files in b by slash-separated
+// relative path, gathered in a single scan instead of a stat per record;
+// only symlinks are stat'ed to get the size of their target. Files that
+// cannot be read are left out.
+func fileSizes(b *bundle.Bundle) map[string]int64 {
+    sizes := map[string]int64{}
+    opts := scanner.ScanOptions{OnError: func(string, error) error { return nil }}
+    if b.Metadata != nil {
+        opts.Exclude = b.Metadata.Exclude
+    }
+    files, err := scanner.ScanDirectoryInfoWithOptions(b.Path, opts)
+    if err != nil {
+        log.Warnf("Cannot scan %s: %v", b.Path, err)
+        return sizes
+    }
+    for _, file := range files {
+        size := file.Size
+        if file.Mode&os.ModeSymlink != 0 {
+            info, err := os.Stat(file.Path)
+            if err != nil {
+                continue
+            }
+            size = info.Size()
+        }
+        sizes[filepath.ToSlash(file.RelPath)] = size
+    }
+    return sizes
+}
+
+// fileEntry is a single row of list output
+type fileEntry struct {
+    Path     string `json:"path"`
+    Checksum string `json:"checksum"`
+    Size     int64  `json:"size_bytes"`
+}
+
+// fileEntryLess orders file entries by the --sort keys
+var fileEntryLess = map[string]func(a, b fileEntry) bool{
+    "path":     func(a, b fileEntry) bool { return a.Path < b.Path },
+    "size":     func(a, b fileEntry) bool { return a.Size < b.Size },
+    "checksum": func(a, b fileEntry) bool { return a.Checksum < b.Checksum },
+}
+
+// sortFileEntries sorts entries by key, descending with reverse. Entries
+// with equal keys stay in ascending path order.
+func sortFileEntries(entries []fileEntry, key string, reverse bool) {
+    less := fileEntryLess[key]
+    sort.SliceStable(entries, func(i, j int) bool {
+        a, b := entries[i], entries[j]
+        if less(a, b) {
+            return !reverse
+        }
+        if less(b, a) {
+            return reverse
+        }
+        return a.Path < b.Path
+    })
+}
+
+// fileRows converts entries to table rows
+func fileRows(entries []fileEntry) [][]string {
+    rows := make([][]string, len(entries))
+    for i, e := range entries {
+        rows[i] = []string{e.Path, e.Checksum, formatBytes(e.Size)}
+    }
+    return rows
+}
+
+// listMetaFiles returns the files in the bundle's .bundle/ directory with
+// their sizes and checksums, computed with the bundle's algorithm. Paths
+// are relative to the bundle root.
+func listMetaFiles(bundlePath string, algo checksum.HashAlgorithm) ([]fileEntry, error) {
+    metaDir := utils.GetBundleMetadataDir(bundlePath)
+    dirEntries, err := os.ReadDir(metaDir)
+    if err != nil {
+        return nil, err
+    }
+
+    entries := []fileEntry{}
+    for _, d := range dirEntries {
+        if d.IsDir() {
+            continue
+        }
+        info, err := d.Info()
+        if err != nil {
+            return nil, err
+        }
+        sum, err := checksum.ComputeFileHash(filepath.Join(metaDir, d.Name()), algo)
+        if err != nil {
+            return nil, err
+        }
+        entries = append(entries, fileEntry{
+            Path:     filepath.Join(filepath.Base(metaDir), d.Name()),
+            Checksum: sum,
+            Size:     info.Size(),
+        })
+    }
+    return entries, nil
 }
 
 // formatBytes formats bytes into human-friendly string (KB/MB/GB)
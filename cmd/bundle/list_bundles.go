@@ -5,11 +5,12 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"sort"
 
 	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/metadata"
 	"github.com/jvzantvoort/bundle/pool"
+	"github.com/jvzantvoort/bundle/state"
 	"github.com/jvzantvoort/bundle/utils"
 	"github.com/spf13/cobra"
 	log "github.com/sirupsen/logrus"
@@ -21,11 +22,27 @@ var ListBundlesCmd = &cobra.Command{
 	Short: messages.GetShort("list_bundles"),
 	Long:  messages.GetLong("list_bundles"),
 	Run:   handleListBundlesCmd,
+
+	Annotations: map[string]string{csvAnnotation: "true"},
 }
 
 func init() {
 	rootCmd.AddCommand(ListBundlesCmd)
 	ListBundlesCmd.Flags().StringP("pool", "p", "default", "pool name to list bundles from")
+	ListBundlesCmd.Flags().String("format", "", formatFlagUsage)
+	ListBundlesCmd.Flags().Bool("count", false, "print only the number of bundles")
+	ListBundlesCmd.Flags().String("sort", "title", "sort bundles by title, created, size or author")
+	ListBundlesCmd.Flags().Bool("reverse", false, "reverse the sort order")
+	ListBundlesCmd.Flags().Int("limit", 0, "show at most N bundles after sorting (0: all)")
+	ListBundlesCmd.Flags().Bool("no-header", false, "leave out the header row of --output csv")
+}
+
+// bundleListEntry is one bundle in list_bundles output, used for JSON and --format
+type bundleListEntry struct {
+	Checksum  string `json:"checksum"`
+	Title     string `json:"title"`
+	Author    string `json:"author"`
+	CreatedAt string `json:"created_at"`
 }
 
 func handleListBundlesCmd(cmd *cobra.Command, args []string) {
@@ -36,48 +53,90 @@ func handleListBundlesCmd(cmd *cobra.Command, args []string) {
 	defer log.Debugf("%s: end", cmd.Use)
 
 	poolName, _ := cmd.Flags().GetString("pool")
+	sortKey := GetString(*cmd, "sort")
+	reverse, _ := cmd.Flags().GetBool("reverse")
+	limit, _ := cmd.Flags().GetInt("limit")
+	if _, ok := bundleLess[sortKey]; !ok {
+		exitWithError(1, nil, "invalid sort key '%s': must be title, created, size or author", sortKey)
+	}
+	if limit < 0 {
+		exitWithError(1, nil, "invalid limit %d: must be 0 or more", limit)
+	}
 
 	// Get pool configuration
 	p, err := pool.GetPool(poolName)
 	if err != nil {
-		log.Errorf("Pool error: %v", err)
-		os.Exit(1)
+		exitWithError(1, err, "Pool error: %v", err)
 	}
 
 	// List bundles
 	bundles, err := p.ListBundles()
 	if err != nil {
-		log.Errorf("Failed to list bundles: %v", err)
-		os.Exit(2)
+		exitWithError(2, err, "Failed to list bundles: %v", err)
 	}
 
-	if jsonOutput {
-		type bundleInfo struct {
-			Checksum  string `json:"checksum"`
-			Title     string `json:"title"`
-			Author    string `json:"author"`
-			CreatedAt string `json:"created_at"`
+	if count, _ := cmd.Flags().GetBool("count"); count {
+		if structuredOutput {
+			out := map[string]interface{}{
+				"pool":  poolName,
+				"root":  p.Root,
+				"count": len(bundles),
+			}
+			if err := outputStructured(out); err != nil {
+				log.Errorf("failed to write output: %v", err)
+				exit(2)
+			}
+			return
 		}
+		fmt.Fprintln(stdout, len(bundles))
+		return
+	}
 
-		bundleList := make([]bundleInfo, len(bundles))
-		for i, meta := range bundles {
-			bundleList[i] = bundleInfo{
-				Checksum:  meta.BundleChecksum,
-				Title:     meta.Title,
-				Author:    meta.Author,
-				CreatedAt: meta.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
-			}
+	total := len(bundles)
+	sortBundles(p, bundles, sortKey, reverse)
+	if limit > 0 && limit < len(bundles) {
+		bundles = bundles[:limit]
+	}
+
+	bundleList := make([]bundleListEntry, len(bundles))
+	for i, meta := range bundles {
+		bundleList[i] = bundleListEntry{
+			Checksum:  meta.BundleChecksum,
+			Title:     meta.Title,
+			Author:    meta.Author,
+			CreatedAt: meta.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	if format := GetString(*cmd, "format"); format != "" {
+		if err := utils.OutputTemplateTo(stdout, format, bundleList); err != nil {
+			exitWithError(1, err, "Format error: %v", err)
 		}
+		return
+	}
 
+	if structuredOutput {
 		out := map[string]interface{}{
 			"pool":    poolName,
 			"root":    p.Root,
 			"bundles": bundleList,
 			"count":   len(bundles),
+			"total":   total,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	if outputFormat == "csv" {
+		rows := make([][]string, len(bundleList))
+		for i, e := range bundleList {
+			rows[i] = []string{e.Checksum, e.Title, e.Author, e.CreatedAt}
 		}
-		if err := utils.OutputJSON(out); err != nil {
-			log.Errorf("failed to output json: %v", err)
-			os.Exit(2)
+		if err := utils.OutputCSV(csvHeader(cmd, "checksum", "title", "author", "created"), rows, stdout); err != nil {
+			exitWithError(2, err, "failed to output csv: %v", err)
 		}
 		return
 	}
@@ -88,24 +147,70 @@ func handleListBundlesCmd(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	// Sort bundles by title
-	sort.Slice(bundles, func(i, j int) bool {
-		return bundles[i].Title < bundles[j].Title
-	})
-
-	table := utils.OutputTable(os.Stdout)
-	table.Header("Checksum", "Title", "Author", "Created")
-
-	for _, meta := range bundles {
-		_ = table.Append([]string{
+	rows := make([][]string, len(bundles))
+	for i, meta := range bundles {
+		rows[i] = []string{
 			meta.BundleChecksum[:12] + "...", // Truncate checksum
 			meta.Title,
 			meta.Author,
 			meta.CreatedAt.Format("2006-01-02 15:04"),
-		})
+		}
+	}
+
+	fmt.Fprintf(stdout, "Pool: %s (%s)\n\n", p.Title, p.Root)
+	if err := utils.WriteTable(stdout, []string{"Checksum", "Title", "Author", "Created"}, rows); err != nil {
+		exitWithError(2, err, "failed to output table: %v", err)
 	}
+	if len(bundles) < total {
+		fmt.Fprintf(stdout, "\nShowing %d of %d bundles\n", len(bundles), total)
+		return
+	}
+	fmt.Fprintf(stdout, "\nTotal: %d bundles\n", len(bundles))
+}
+
+// bundleSortKey holds what list_bundles sorts on for one bundle
+type bundleSortKey struct {
+	meta *metadata.Metadata
+	size int64
+}
 
-	fmt.Printf("Pool: %s (%s)\n\n", p.Title, p.Root)
-	_ = table.Render()
-	fmt.Printf("\nTotal: %d bundles\n", len(bundles))
+// bundleLess orders bundles by the --sort keys
+var bundleLess = map[string]func(a, b bundleSortKey) bool{
+	"title":   func(a, b bundleSortKey) bool { return a.meta.Title < b.meta.Title },
+	"created": func(a, b bundleSortKey) bool { return a.meta.CreatedAt.Before(b.meta.CreatedAt) },
+	"size":    func(a, b bundleSortKey) bool { return a.size < b.size },
+	"author":  func(a, b bundleSortKey) bool { return a.meta.Author < b.meta.Author },
+}
+
+// sortBundles sorts bundles by key, descending with reverse. Bundles with
+// equal keys stay in checksum order. Sizes come from STATE.json and are
+// only read when sorting by size; bundles without state sort as empty.
+func sortBundles(p *pool.Pool, bundles []*metadata.Metadata, key string, reverse bool) {
+	keys := make([]bundleSortKey, len(bundles))
+	for i, meta := range bundles {
+		keys[i].meta = meta
+		if key != "size" {
+			continue
+		}
+		if st, err := state.Load(p.GetBundlePath(meta.BundleChecksum)); err == nil {
+			keys[i].size = st.SizeBytes
+		} else {
+			log.Debugf("No state for bundle %s: %v", meta.BundleChecksum, err)
+		}
+	}
+
+	less := bundleLess[key]
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if less(a, b) {
+			return !reverse
+		}
+		if less(b, a) {
+			return reverse
+		}
+		return a.meta.BundleChecksum < b.meta.BundleChecksum
+	})
+	for i := range keys {
+		bundles[i] = keys[i].meta
+	}
 }
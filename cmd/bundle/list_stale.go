@@ -0,0 +1,107 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/pool"
+	"github.com/jvzantvoort/bundle/state"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// ListStaleCmd represents the list-stale command.
+//
+// It lists the pooled bundles whose last verification is older than the
+// `verify.max_age` setting or --max-age.
+//
+// Usage:
+//
+//	bundle list-stale --pool <name> [--max-age 720h]
+var ListStaleCmd = &cobra.Command{
+	Use:   messages.GetUse("list_stale"),
+	Short: messages.GetShort("list_stale"),
+	Long:  messages.GetLong("list_stale"),
+	Run:   handleListStaleCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(ListStaleCmd)
+	ListStaleCmd.Flags().StringP("pool", "p", "default", "pool name to check")
+	ListStaleCmd.Flags().Duration("max-age", 0, "maximum time since the last verification (default: verify.max_age setting, or 720h)")
+}
+
+func handleListStaleCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	poolName, _ := cmd.Flags().GetString("pool")
+	maxAge, _ := cmd.Flags().GetDuration("max-age")
+	if maxAge < 0 {
+		exitWithError(1, nil, "invalid --max-age %s: must not be negative", maxAge)
+	}
+	if maxAge == 0 {
+		maxAge = state.VerifyMaxAge()
+	}
+
+	p, err := pool.GetPool(poolName)
+	if err != nil {
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+
+	stale, err := p.ListStale(maxAge)
+	if err != nil {
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"pool":    poolName,
+			"max_age": maxAge.String(),
+			"bundles": stale,
+			"count":   len(stale),
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	if len(stale) == 0 {
+		log.Infof("No bundles older than %s since their last verification", maxAge)
+		return
+	}
+
+	rows := make([][]string, len(stale))
+	for i, b := range stale {
+		lastChecked := "never"
+		if b.LastChecked != nil {
+			lastChecked = fmt.Sprintf("%s (%s)", b.LastChecked.Format("2006-01-02 15:04"), daysAgo(*b.LastChecked))
+		}
+		rows[i] = []string{b.Checksum[:12] + "...", b.Title, lastChecked}
+	}
+
+	fmt.Fprintf(stdout, "Pool: %s (%s)\n\n", p.Title, p.Root)
+	if err := utils.WriteTable(stdout, []string{"Checksum", "Title", "Last Checked"}, rows); err != nil {
+		exitWithError(2, err, "failed to output table: %v", err)
+	}
+	fmt.Fprintf(stdout, "\nTotal: %d stale bundles\n", len(stale))
+}
+
+// daysAgo describes how many whole days ago t was, e.g. "45 days ago".
+func daysAgo(t time.Time) string {
+	days := int(time.Since(t).Hours() / 24)
+	if days == 1 {
+		return "1 day ago"
+	}
+	return fmt.Sprintf("%d days ago", days)
+}
@@ -5,12 +5,45 @@
 //
 //	bundle create <path> --title "My Bundle"
 //	bundle verify <path>
+//	bundle verify-file <path> <expected-checksum>
+//	bundle compare <bundle-path> <dir>
+//	bundle diff <pathA> <pathB>
+//	bundle equal <pathA> <pathB> [--deep]
+//	bundle clone <src> <dest>
+//	bundle add <bundle> <file>... --allow-rechecksum
+//	bundle rm <bundle> <relpath>... --allow-rechecksum
+//	bundle walk <dir>
+//	bundle import-archive <archive> <dest>
+//	bundle delete <checksum> --pool <name>
+//	bundle pool gc --pool <name> [--dry-run]
+//	bundle pool verify --pool <name>
+//	bundle verify-pool --pool <name>
+//	bundle list-stale --pool <name>
+//	bundle search --pool <name> --tag <tag>... [--any]
+//	bundle show <prefix> --pool <name>
+//	bundle checkout <prefix> <dest> --pool <name>
 //	bundle info <path>
 //	bundle list <path>
 //	bundle tag add <path> <tag>...
 //	bundle tag remove <path> <tag>...
 //	bundle tag list <path>
+//	bundle tag normalize <path>
+//	bundle tag export --pool <name>
+//	bundle tag import --pool <name> <file>
+//	bundle tag stats --pool <name>
 //	bundle rename <path> <new_title>
+//	bundle annotate <path> [key=value...] [--remove key...]
+//	bundle replica add <path> <uri>...
+//	bundle replica remove <path> <uri>...
+//	bundle replica list <path>
+//	bundle repair <path>
+//	bundle doctor
+//	bundle config init [--force]
+//	bundle config path
+//	bundle pool stats [--pool <name>]
+//	bundle pool list
+//	bundle pool check [--pool <name>]
+//	bundle pool move <prefix> --from <name> --to <name> [--keep]
 //
 // All commands support --json flag for machine-readable output and --verbose
 // flag for detailed logging.
@@ -0,0 +1,79 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// stdout receives command results: JSON and YAML objects, tables, CSV and
+// --format output. It is os.Stdout unless --output-file is given.
+var stdout io.Writer = os.Stdout
+
+// outputFilePath is the --output-file flag; resultFile is the temporary
+// file in the same directory that results are written to until the command
+// finishes.
+var outputFilePath string
+var resultFile *os.File
+
+// openOutputFile starts writing results to a temporary file next to path.
+//
+// Log lines go to stderr from then on, so only the result payload ends up
+// in the file. The file replaces path in closeOutputFile, so an existing
+// file is never left half-written.
+//
+// Parameters:
+//   - path: the --output-file target
+//
+// Returns:
+//   - error: if the temporary file cannot be created
+func openOutputFile(path string) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	resultFile = file
+	stdout = file
+	log.SetOutput(os.Stderr)
+	return nil
+}
+
+// closeOutputFile moves the result to the --output-file target when the
+// command exits with code.
+//
+// The temporary file is renamed over the target on success. On failure it
+// is kept only with --json or --output yaml, where the error object is the
+// result; otherwise it is removed and an existing target stays untouched.
+func closeOutputFile(code int) {
+	if resultFile == nil {
+		return
+	}
+	file := resultFile
+	resultFile = nil
+	stdout = os.Stdout
+
+	tmp := file.Name()
+	err := file.Close()
+	if err == nil && (code == 0 || structuredOutput) {
+		if err = os.Chmod(tmp, 0644); err == nil {
+			err = os.Rename(tmp, outputFilePath)
+		}
+	}
+	if err != nil {
+		log.Errorf("Cannot write %s: %v", outputFilePath, err)
+	}
+	if err != nil || (code != 0 && !structuredOutput) {
+		os.Remove(tmp)
+	}
+}
+
+// exit finishes --output-file and exits with code.
+func exit(code int) {
+	closeOutputFile(code)
+	os.Exit(code)
+}
@@ -0,0 +1,273 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/pool"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// PoolCmd represents the pool command.
+//
+// It groups the commands that work on whole pools.
+//
+// Usage:
+//
+//	bundle pool list
+//	bundle pool check [--pool <name>]
+//	bundle pool move <prefix> --from <name> --to <name> [--keep]
+//	bundle pool stats [--pool <name>]
+//	bundle pool gc [--pool <name>] [--dry-run]
+//	bundle pool verify [--pool <name>]
+var PoolCmd = &cobra.Command{
+	Use:   messages.GetUse("pool"),
+	Short: messages.GetShort("pool"),
+	Long:  messages.GetLong("pool"),
+}
+
+func init() {
+	rootCmd.AddCommand(PoolCmd)
+
+	// Subcommands: list, check, move
+	PoolCmd.AddCommand(poolListCmd)
+	PoolCmd.AddCommand(poolCheckCmd)
+	PoolCmd.AddCommand(poolMoveCmd)
+
+	poolCheckCmd.Flags().StringP("pool", "p", "", "only check this pool (default: all pools)")
+	poolMoveCmd.Flags().String("from", "", "pool to take the bundle from")
+	poolMoveCmd.Flags().String("to", "", "pool to move the bundle to")
+	poolMoveCmd.Flags().Bool("keep", false, "keep the bundle in the source pool (copy instead of move)")
+}
+
+// poolListEntry is one pool in the pool list output.
+type poolListEntry struct {
+	Name   string `json:"name"`
+	Title  string `json:"title"`
+	Root   string `json:"root"`
+	Exists bool   `json:"exists"`
+}
+
+// pool list
+var poolListCmd = &cobra.Command{
+	Use:   messages.GetUse("pool_list"),
+	Short: messages.GetShort("pool_list"),
+	Long:  messages.GetLong("pool_list"),
+	Run:   handlePoolListCmd,
+}
+
+func handlePoolListCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 0 {
+		exitWithUsage(cmd, "Usage: bundle pool list")
+	}
+
+	pools, err := pool.ListPools()
+	if err != nil {
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+
+	names := make([]string, 0, len(pools))
+	for name := range pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]poolListEntry, len(names))
+	for i, name := range names {
+		p := pools[name]
+		info, err := os.Stat(p.Root)
+		entries[i] = poolListEntry{
+			Name:   name,
+			Title:  p.Title,
+			Root:   p.Root,
+			Exists: err == nil && info.IsDir(),
+		}
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"pools": entries,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	if len(entries) == 0 {
+		log.Info("No pools configured")
+		return
+	}
+
+	rows := make([][]string, len(entries))
+	for i, e := range entries {
+		rows[i] = []string{e.Name, e.Title, e.Root, strconv.FormatBool(e.Exists)}
+	}
+	if err := utils.WriteTable(stdout, []string{"Pool", "Title", "Root", "Exists"}, rows); err != nil {
+		exitWithError(2, err, "failed to output table: %v", err)
+	}
+}
+
+// poolCheckEntry is one pool in the pool check output.
+type poolCheckEntry struct {
+	Name  string `json:"name"`
+	Root  string `json:"root"`
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// pool check
+var poolCheckCmd = &cobra.Command{
+	Use:   messages.GetUse("pool_check"),
+	Short: messages.GetShort("pool_check"),
+	Long:  messages.GetLong("pool_check"),
+	Run:   handlePoolCheckCmd,
+}
+
+func handlePoolCheckCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 0 {
+		exitWithUsage(cmd, "Usage: bundle pool check [--pool <name>]")
+	}
+
+	pools, err := selectedPools(cmd)
+	if err != nil {
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+
+	names := make([]string, 0, len(pools))
+	for name := range pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	valid := true
+	entries := make([]poolCheckEntry, len(names))
+	for i, name := range names {
+		entries[i] = poolCheckEntry{Name: name, Root: pools[name].Root, Valid: true}
+		if err := pools[name].Validate(); err != nil {
+			entries[i].Valid = false
+			entries[i].Error = err.Error()
+			valid = false
+		}
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"valid": valid,
+			"pools": entries,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+	} else if len(entries) == 0 {
+		log.Info("No pools configured")
+	} else {
+		rows := make([][]string, len(entries))
+		for i, e := range entries {
+			status := "ok"
+			if !e.Valid {
+				status = "invalid"
+			}
+			rows[i] = []string{e.Name, e.Root, status, e.Error}
+		}
+		if err := utils.WriteTable(stdout, []string{"Pool", "Root", "Status", "Problem"}, rows); err != nil {
+			exitWithError(2, err, "failed to output table: %v", err)
+		}
+	}
+
+	if !valid {
+		exit(1)
+	}
+}
+
+// pool move
+var poolMoveCmd = &cobra.Command{
+	Use:   messages.GetUse("pool_move"),
+	Short: messages.GetShort("pool_move"),
+	Long:  messages.GetLong("pool_move"),
+	Run:   handlePoolMoveCmd,
+}
+
+// handlePoolMoveCmd processes the pool move command.
+//
+// Unknown pools, unknown or ambiguous prefixes and copies that do not
+// verify exit 1; other failures exit 2.
+func handlePoolMoveCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	fromName := GetString(*cmd, "from")
+	toName := GetString(*cmd, "to")
+	if len(args) != 1 || fromName == "" || toName == "" {
+		exitWithUsage(cmd, "Usage: bundle pool move <prefix> --from <name> --to <name> [--keep]")
+	}
+	if fromName == toName {
+		exitWithError(1, nil, "source and destination pool are the same: %s", fromName)
+	}
+	keep, _ := cmd.Flags().GetBool("keep")
+
+	from, err := pool.GetPool(fromName)
+	if err != nil {
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+	to, err := pool.GetPool(toName)
+	if err != nil {
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+
+	sum, err := from.Resolve(args[0])
+	if err != nil {
+		exitWithError(utils.ExitCodeFromError(err), err, "%v", err)
+	}
+
+	if err := from.MoveTo(sum, to, !keep); err != nil {
+		exitWithError(utils.ExitCodeFromError(err), err, "Move failed: %v", err)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"status":   "moved",
+			"checksum": sum,
+			"from":     fromName,
+			"to":       toName,
+		}
+		if keep {
+			out["status"] = "copied"
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	action := "Moved"
+	if keep {
+		action = "Copied"
+	}
+	log.Infof("%s bundle %s from pool '%s' to pool '%s'", action, sum, fromName, toName)
+}
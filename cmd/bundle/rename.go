@@ -10,7 +10,6 @@ import (
 	"github.com/jvzantvoort/bundle/bundle"
 	"github.com/jvzantvoort/bundle/messages"
 	"github.com/jvzantvoort/bundle/metadata"
-	"github.com/jvzantvoort/bundle/utils"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -50,11 +49,7 @@ func handleRenameCmd(cmd *cobra.Command, args []string) {
 
 	// Validate arguments
 	if len(args) != 2 {
-		log.Error("Usage: bundle rename <path> <new_title>")
-		if err := cmd.Help(); err != nil {
-			log.Error(err)
-		}
-		os.Exit(1)
+		exitWithUsage(cmd, "Usage: bundle rename <path> <new_title>")
 	}
 
 	path := args[0]
@@ -66,32 +61,33 @@ func handleRenameCmd(cmd *cobra.Command, args []string) {
 	// Verify bundle exists
 	b, err := bundle.Load(path)
 	if err != nil {
+		exitIfInvalid(err)
 		if os.IsNotExist(err) || strings.Contains(err.Error(), "not a bundle") {
-			log.Errorf("Not a bundle: %v", err)
-			os.Exit(1)
+			exitWithError(1, err, "Not a bundle: %v", err)
 		}
-		log.Errorf("System error: %v", err)
-		os.Exit(2)
+		exitWithError(2, err, "System error: %v", err)
 	}
 
 	if b.Metadata == nil {
-		log.Errorf("bundle metadata missing")
-		os.Exit(2)
+		exitWithError(2, nil, "bundle metadata missing")
 	}
 
 	oldTitle := b.Metadata.Title
 	log.Debugf("Old title: %s", oldTitle)
 
 	// Update title using metadata helper
-	if err := metadata.UpdateTitle(path, newTitle); err != nil {
-		log.Errorf("Failed to update title: %v", err)
-		os.Exit(2)
+	err = withBundleLock(path, func() error {
+		return metadata.UpdateTitle(path, newTitle)
+	})
+	if err != nil {
+		exitIfLocked(err)
+		exitWithError(2, err, "Failed to update title: %v", err)
 	}
 
 	log.Debugf("Title updated successfully")
 
 	// Output results
-	if jsonOutput {
+	if structuredOutput {
 		out := map[string]interface{}{
 			"status":    "renamed",
 			"path":      path,
@@ -99,9 +95,9 @@ func handleRenameCmd(cmd *cobra.Command, args []string) {
 			"new_title": newTitle,
 			"title":     newTitle, // For backward compatibility
 		}
-		if err := utils.OutputJSON(out); err != nil {
-			log.Errorf("failed to output json: %v", err)
-			os.Exit(2)
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
 		}
 		return
 	}
@@ -0,0 +1,112 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"time"
+
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// RepairCmd represents the repair command.
+//
+// It regenerates a missing or damaged STATE.json, TAGS.txt or checksum
+// manifest, keeping META.json and so the bundle identity unchanged. With
+// --fix-timestamps it also re-stamps timestamps that lie in the future.
+//
+// Usage:
+//
+//	bundle repair <path> [--fix-timestamps]
+var RepairCmd = &cobra.Command{
+	Use:   messages.GetUse("repair"),
+	Short: messages.GetShort("repair"),
+	Long:  messages.GetLong("repair"),
+	Run:   handleRepairCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(RepairCmd)
+	RepairCmd.Flags().Bool("fix-timestamps", false, "re-stamp timestamps that lie in the future with the current time")
+}
+
+// handleRepairCmd processes the repair command.
+//
+// It exits 1 when the files no longer match the bundle checksum, since a
+// damaged manifest cannot then be regenerated safely.
+func handleRepairCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 1 {
+		exitWithUsage(cmd, "No path provided")
+	}
+	path := args[0]
+
+	// Timestamps first: the repair itself needs valid metadata
+	fixed := []bundle.TimestampIssue{}
+	if fix, _ := cmd.Flags().GetBool("fix-timestamps"); fix {
+		issues, err := bundle.FixTimestamps(path, time.Now())
+		if err != nil {
+			exitIfLocked(err)
+			exitWithError(utils.ExitCodeFromError(err), err, "Repair failed: %v", err)
+		}
+		fixed = issues
+	}
+
+	report, err := bundle.Repair(path)
+	if err != nil {
+		exitIfLocked(err)
+		exitWithError(utils.ExitCodeFromError(err), err, "Repair failed: %v", err)
+	}
+
+	status := "ok"
+	switch {
+	case report.Mismatch():
+		status = "mismatch"
+	case len(report.Regenerated) > 0 || len(fixed) > 0:
+		status = "repaired"
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"status":            status,
+			"path":              path,
+			"regenerated":       report.Regenerated,
+			"skipped":           report.Skipped,
+			"timestamps_fixed":  fixed,
+			"verified":          report.Verified,
+			"bundle_checksum":   report.BundleChecksum,
+			"computed_checksum": report.ComputedChecksum,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+	} else {
+		for _, issue := range fixed {
+			log.Infof("Re-stamped %s", issue)
+		}
+		for _, name := range report.Regenerated {
+			log.Infof("Regenerated %s", name)
+		}
+		for _, name := range report.Skipped {
+			log.Warnf("Not regenerating %s: it would change the bundle checksum", name)
+		}
+		if len(report.Regenerated) == 0 && len(report.Skipped) == 0 && len(fixed) == 0 {
+			log.Info("Nothing to repair")
+		}
+	}
+
+	if report.Mismatch() {
+		log.Errorf("Bundle checksum mismatch: META.json has %s, the files give %s", report.BundleChecksum, report.ComputedChecksum)
+		exit(1)
+	}
+}
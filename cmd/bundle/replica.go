@@ -0,0 +1,245 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/state"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// ReplicaCmd represents the replica command.
+//
+// It manages the replica locations recorded in .bundle/STATE.json.
+//
+// Usage:
+//
+//	bundle replica add <path> <uri>...
+//	bundle replica remove <path> <uri>...
+//	bundle replica list <path>
+var ReplicaCmd = &cobra.Command{
+	Use:   messages.GetUse("replica"),
+	Short: messages.GetShort("replica"),
+	Long:  messages.GetLong("replica"),
+}
+
+func init() {
+	rootCmd.AddCommand(ReplicaCmd)
+
+	// Subcommands: add, remove, list
+	ReplicaCmd.AddCommand(replicaAddCmd)
+	ReplicaCmd.AddCommand(replicaRemoveCmd)
+	ReplicaCmd.AddCommand(replicaListCmd)
+}
+
+// requireBundle exits 1 when path is not a loadable bundle and 2 on other
+// errors.
+func requireBundle(path string) {
+	if _, err := bundle.Load(path); err != nil {
+		exitIfInvalid(err)
+		if os.IsNotExist(err) || strings.Contains(err.Error(), "not a bundle") {
+			exitWithError(1, err, "Not a bundle: %v", err)
+		}
+		exitWithError(2, err, "System error: %v", err)
+	}
+}
+
+// updateReplicas applies fn to the state of the bundle at path and saves
+// it, holding the bundle lock.
+func updateReplicas(path string, fn func(st *state.State) error) (*state.State, error) {
+	var st *state.State
+	err := withBundleLock(path, func() error {
+		var err error
+		if st, err = state.Load(path); err != nil {
+			return err
+		}
+		if err := fn(st); err != nil {
+			return err
+		}
+		return st.Save(path)
+	})
+	return st, err
+}
+
+// replica add
+var replicaAddCmd = &cobra.Command{
+	Use:   messages.GetUse("replica_add"),
+	Short: messages.GetShort("replica_add"),
+	Long:  messages.GetLong("replica_add"),
+	Run:   handleReplicaAddCmd,
+}
+
+func handleReplicaAddCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) < 2 {
+		exitWithUsage(cmd, "Usage: bundle replica add <path> <uri> [<uri>...]")
+	}
+
+	path := args[0]
+	uris := args[1:]
+	for _, uri := range uris {
+		if _, err := state.NormalizeReplicaURI(uri); err != nil {
+			exitWithError(1, err, "%v", err)
+		}
+	}
+	requireBundle(path)
+
+	st, err := updateReplicas(path, func(st *state.State) error {
+		for _, uri := range uris {
+			if err := st.AddReplicaValidated(uri); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		exitIfLocked(err)
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"status":   "added",
+			"path":     path,
+			"replicas": st.Replicas,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	log.Debugf("Replicas: %v", st.Replicas)
+}
+
+// replica remove
+var replicaRemoveCmd = &cobra.Command{
+	Use:   messages.GetUse("replica_remove"),
+	Short: messages.GetShort("replica_remove"),
+	Long:  messages.GetLong("replica_remove"),
+	Run:   handleReplicaRemoveCmd,
+}
+
+func handleReplicaRemoveCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) < 2 {
+		exitWithUsage(cmd, "Usage: bundle replica remove <path> <uri> [<uri>...]")
+	}
+
+	path := args[0]
+	uris := args[1:]
+	requireBundle(path)
+
+	removed := []string{}
+	st, err := updateReplicas(path, func(st *state.State) error {
+		for _, uri := range uris {
+			// Replicas stored before validation may not be normalized
+			if normalized, err := state.NormalizeReplicaURI(uri); err == nil && st.RemoveReplica(normalized) {
+				removed = append(removed, normalized)
+			} else if st.RemoveReplica(uri) {
+				removed = append(removed, uri)
+			} else {
+				log.Debugf("Not a replica: %s", uri)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		exitIfLocked(err)
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"status":   "removed",
+			"path":     path,
+			"replicas": st.Replicas,
+			"removed":  removed,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	log.Debugf("Removed %d replica(s)", len(removed))
+}
+
+// replica list
+var replicaListCmd = &cobra.Command{
+	Use:   messages.GetUse("replica_list"),
+	Short: messages.GetShort("replica_list"),
+	Long:  messages.GetLong("replica_list"),
+	Run:   handleReplicaListCmd,
+}
+
+func handleReplicaListCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 1 {
+		exitWithUsage(cmd, "Usage: bundle replica list <path>")
+	}
+
+	path := args[0]
+	b, err := loadShared(path)
+	if err != nil {
+		exitIfLocked(err)
+		exitIfInvalid(err)
+		if os.IsNotExist(err) || strings.Contains(err.Error(), "not a bundle") {
+			exitWithError(1, err, "Not a bundle: %v", err)
+		}
+		exitWithError(2, err, "System error: %v", err)
+	}
+	replicas := []string{}
+	if b.State != nil && b.State.Replicas != nil {
+		replicas = b.State.Replicas
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"path":     path,
+			"replicas": replicas,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	if len(replicas) == 0 {
+		log.Info("No replicas")
+		return
+	}
+
+	rows := make([][]string, len(replicas))
+	for i, uri := range replicas {
+		rows[i] = []string{uri}
+	}
+	if err := utils.WriteTable(stdout, []string{"Replica"}, rows); err != nil {
+		exitWithError(2, err, "failed to output table: %v", err)
+	}
+}
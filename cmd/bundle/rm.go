@@ -0,0 +1,85 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// RmCmd represents the rm command.
+//
+// It deletes files from an existing bundle and updates its manifest and
+// metadata. The bundle checksum changes, so --allow-rechecksum is required.
+//
+// Usage:
+//
+//	bundle rm <bundle> <relpath>... --allow-rechecksum
+var RmCmd = &cobra.Command{
+	Use:   messages.GetUse("rm"),
+	Short: messages.GetShort("rm"),
+	Long:  messages.GetLong("rm"),
+	Run:   handleRmCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(RmCmd)
+	RmCmd.Flags().Bool("allow-rechecksum", false, "accept that removing files changes the bundle checksum")
+}
+
+// handleRmCmd processes the rm command.
+//
+// A missing --allow-rechecksum, a path that is not a bundle and files that
+// are not tracked exit 1; other errors exit 2.
+func handleRmCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) < 2 {
+		exitWithUsage(cmd, "Usage: bundle rm <bundle> <relpath>... --allow-rechecksum")
+	}
+	path := args[0]
+	relPaths := args[1:]
+
+	if allow, _ := cmd.Flags().GetBool("allow-rechecksum"); !allow {
+		exitWithError(1, nil, "Removing files changes the bundle checksum of %s; pass --allow-rechecksum to continue", path)
+	}
+
+	b, err := bundle.Load(path)
+	if err != nil {
+		exitIfInvalid(err)
+		exitWithError(utils.ExitCodeFromError(err), err, "Not a bundle: %v", err)
+	}
+	oldChecksum := b.Metadata.BundleChecksum
+
+	b, err = bundle.RemoveFiles(path, relPaths)
+	if err != nil {
+		exitIfLocked(err)
+		exitWithError(utils.ExitCodeFromError(err), err, "Remove failed: %v", err)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"status":       "removed",
+			"path":         path,
+			"files":        relPaths,
+			"old_checksum": oldChecksum,
+			"checksum":     b.Metadata.BundleChecksum,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	log.Infof("Removed %d file(s) from %s", len(relPaths), path)
+	log.Warnf("Bundle checksum changed: %s → %s", oldChecksum, b.Metadata.BundleChecksum)
+}
@@ -1,16 +1,35 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/jvzantvoort/bundle/config"
+	"github.com/jvzantvoort/bundle/lock"
 	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/utils"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var verbose bool
-var jsonOutput bool
+var jsonFlag bool
+
+// outputFormat is the resolved --output format: "json", "yaml" or "table".
+var outputFormat = "table"
+
+// structuredOutput is true when results are printed as JSON or YAML rather
+// than for humans.
+var structuredOutput bool
+
+// logFormat is the resolved --log-format: "text" or "json".
+var logFormat = config.LogFormatText
+
+// csvAnnotation marks commands that support --output csv; see
+// utils.OutputCSV.
+const csvAnnotation = "output.csv"
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -24,8 +43,16 @@ var rootCmd = &cobra.Command{
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		// cobra has already printed the error for humans; flag errors
+		// happen before the output format is resolved
+		if resolveOutputFormat() == nil && structuredOutput {
+			if err := outputError(utils.ErrorCodeUsage, err.Error()); err != nil {
+				log.Errorf("failed to write output: %v", err)
+			}
+		}
+		exit(1)
 	}
+	closeOutputFile(0)
 }
 
 func init() {
@@ -49,5 +76,85 @@ func init() {
 	log.SetLevel(log.InfoLevel)
 
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose logging")
-	rootCmd.PersistentFlags().BoolVarP(&jsonOutput, "json", "j", false, "Output JSON")
+	rootCmd.PersistentFlags().BoolVarP(&jsonFlag, "json", "j", false, "Output JSON (same as --output json)")
+	rootCmd.PersistentFlags().String("output", "", "output format: json, yaml, table or csv (default table)")
+	rootCmd.PersistentFlags().String("log-format", "", "log format: text or json (default: log_format setting, or text)")
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		if err := resolveOutputFormat(); err != nil {
+			exitWithUsage(cmd, err.Error())
+		}
+		if err := resolveLogFormat(); err != nil {
+			exitWithUsage(cmd, err.Error())
+		}
+		if outputFormat == "csv" && cmd.Annotations[csvAnnotation] == "" {
+			exitWithUsage(cmd, fmt.Sprintf("--output csv is not supported by %s", cmd.CommandPath()))
+		}
+		if outputFilePath != "" {
+			if err := openOutputFile(outputFilePath); err != nil {
+				exitWithError(2, err, "Cannot write %s: %v", outputFilePath, err)
+			}
+		}
+	}
+	rootCmd.PersistentFlags().StringVar(&outputFilePath, "output-file", "", "write the result to this file, replacing it only once complete")
+	rootCmd.PersistentFlags().BoolVarP(&utils.AssumeYes, "yes", "y", false, "Answer yes to confirmation prompts")
+	rootCmd.PersistentFlags().DurationVar(&lock.WaitTimeout, "lock-timeout", 0, "wait this long for a bundle locked by another process, e.g. 30s (default: fail immediately)")
+}
+
+// resolveOutputFormat sets outputFormat and structuredOutput from the
+// --output and --json flags.
+//
+// --json is an alias for --output json; without either the output is
+// for humans ("table").
+//
+// Returns:
+//   - error: for an unknown format, or --json with another --output
+func resolveOutputFormat() error {
+	format := "table"
+	if flag := rootCmd.PersistentFlags().Lookup("output"); flag != nil && flag.Changed {
+		format = strings.ToLower(flag.Value.String())
+		switch format {
+		case "json", "yaml", "table", "csv":
+		default:
+			return fmt.Errorf("invalid output format '%s': must be json, yaml, table or csv", flag.Value.String())
+		}
+		if jsonFlag && format != "json" {
+			return fmt.Errorf("--json conflicts with --output %s", format)
+		}
+	} else if jsonFlag {
+		format = "json"
+	}
+
+	outputFormat = format
+	structuredOutput = format == "json" || format == "yaml"
+	return nil
+}
+
+// resolveLogFormat sets logFormat from the --log-format flag or the
+// log_format setting and configures the loggers accordingly.
+//
+// JSON logs, like JSON or YAML results, are written to stderr, so stdout
+// only carries the command result and both streams stay parseable.
+//
+// Returns:
+//   - error: for an unknown log format
+func resolveLogFormat() error {
+	format := viper.GetString("log_format")
+	if flag := rootCmd.PersistentFlags().Lookup("log-format"); flag != nil && flag.Changed {
+		format = flag.Value.String()
+	}
+	if err := config.SetLogFormat(format); err != nil {
+		return err
+	}
+
+	logFormat = strings.ToLower(format)
+	if logFormat == "" {
+		logFormat = config.LogFormatText
+	}
+	if logFormat == config.LogFormatJSON {
+		log.SetFormatter(&log.JSONFormatter{})
+	}
+	if logFormat == config.LogFormatJSON || structuredOutput {
+		log.SetOutput(os.Stderr)
+	}
+	return nil
 }
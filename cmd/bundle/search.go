@@ -0,0 +1,125 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/metadata"
+	"github.com/jvzantvoort/bundle/pool"
+	"github.com/jvzantvoort/bundle/tag"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// SearchCmd represents the search command.
+//
+// It finds the bundles in a pool that carry the given tags.
+//
+// Usage:
+//
+//	bundle search --pool <name> --tag <tag> [--tag <tag>...] [--any]
+var SearchCmd = &cobra.Command{
+	Use:   messages.GetUse("search"),
+	Short: messages.GetShort("search"),
+	Long:  messages.GetLong("search"),
+	Run:   handleSearchCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(SearchCmd)
+	SearchCmd.Flags().StringP("pool", "p", "default", "pool name to search")
+	SearchCmd.Flags().StringArrayP("tag", "T", nil, "tag to search for (repeatable)")
+	SearchCmd.Flags().Bool("any", false, "match bundles with any of the tags instead of all")
+}
+
+// searchResult is one bundle in search output, used for JSON
+type searchResult struct {
+	Checksum string   `json:"checksum"`
+	Title    string   `json:"title"`
+	Tags     []string `json:"tags"`
+}
+
+// handleSearchCmd processes the search command.
+//
+// No matches is not an error: the command exits 0 with an empty list.
+func handleSearchCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	poolName, _ := cmd.Flags().GetString("pool")
+	tags, _ := cmd.Flags().GetStringArray("tag")
+	anyTag, _ := cmd.Flags().GetBool("any")
+	if len(tags) == 0 {
+		exitWithUsage(cmd, "Usage: bundle search --pool <name> --tag <tag> [--tag <tag>...] [--any]")
+	}
+	for _, raw := range tags {
+		if _, ok := tag.Valid(raw); !ok {
+			exitWithError(1, nil, "invalid tag '%s'", raw)
+		}
+	}
+
+	p, err := pool.GetPool(poolName)
+	if err != nil {
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+
+	var found []*metadata.Metadata
+	if anyTag {
+		found, err = p.FindByAnyTag(tags...)
+	} else {
+		found, err = p.FindByTag(tags...)
+	}
+	if err != nil {
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	results := make([]searchResult, len(found))
+	for i, meta := range found {
+		bundleTags, err := tag.Load(p.GetBundlePath(meta.BundleChecksum))
+		if err != nil {
+			exitWithError(2, err, "System error: %v", err)
+		}
+		results[i] = searchResult{Checksum: meta.BundleChecksum, Title: meta.Title, Tags: bundleTags.List()}
+	}
+
+	if structuredOutput {
+		match := "all"
+		if anyTag {
+			match = "any"
+		}
+		out := map[string]interface{}{
+			"pool":    poolName,
+			"tags":    tags,
+			"match":   match,
+			"bundles": results,
+			"count":   len(results),
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	if len(results) == 0 {
+		log.Info("No bundles found")
+		return
+	}
+
+	rows := make([][]string, len(results))
+	for i, r := range results {
+		rows[i] = []string{r.Checksum[:12] + "...", r.Title, strings.Join(r.Tags, ", ")}
+	}
+	if err := utils.WriteTable(stdout, []string{"Checksum", "Title", "Tags"}, rows); err != nil {
+		exitWithError(2, err, "failed to output table: %v", err)
+	}
+	fmt.Fprintf(stdout, "\nTotal: %d bundles\n", len(results))
+}
@@ -0,0 +1,62 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/pool"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// ShowCmd represents the show command.
+//
+// It prints the info of a pooled bundle given by a checksum prefix, such
+// as the truncated checksums list_bundles prints.
+//
+// Usage:
+//
+//	bundle show <prefix> [--pool <name>]
+var ShowCmd = &cobra.Command{
+	Use:   messages.GetUse("show"),
+	Short: messages.GetShort("show"),
+	Long:  messages.GetLong("show"),
+	Run:   handleShowCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(ShowCmd)
+	ShowCmd.Flags().StringP("pool", "p", "default", "pool name to look in")
+	ShowCmd.Flags().String("format", "", formatFlagUsage)
+}
+
+// handleShowCmd processes the show command.
+//
+// An unknown or ambiguous prefix exits 1.
+func handleShowCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 1 {
+		exitWithUsage(cmd, "Usage: bundle show <prefix> [--pool <name>]")
+	}
+
+	poolName, _ := cmd.Flags().GetString("pool")
+	p, err := pool.GetPool(poolName)
+	if err != nil {
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+
+	_, bundlePath, err := p.Get(args[0])
+	if err != nil {
+		exitWithError(utils.ExitCodeFromError(err), err, "%v", err)
+	}
+	log.Debugf("Resolved %s to %s", args[0], bundlePath)
+
+	showInfo(cmd, bundlePath)
+}
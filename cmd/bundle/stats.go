@@ -0,0 +1,167 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/metadata"
+	"github.com/jvzantvoort/bundle/pool"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// StatsCmd represents the pool stats command.
+//
+// It aggregates bundle counts, sizes, verification status and creation
+// dates across every configured pool, or a single pool with --pool.
+//
+// Usage:
+//
+//	bundle pool stats [--pool <name>]
+var StatsCmd = &cobra.Command{
+	Use:   messages.GetUse("pool_stats"),
+	Short: messages.GetShort("pool_stats"),
+	Long:  messages.GetLong("pool_stats"),
+	Run:   handleStatsCmd,
+}
+
+func init() {
+	PoolCmd.AddCommand(StatsCmd)
+	StatsCmd.Flags().StringP("pool", "p", "", "only show this pool (default: all pools)")
+	StatsCmd.Flags().Int("jobs", 0, jobsFlagUsage)
+}
+
+// poolStatsEntry is the per-pool line of the stats report.
+type poolStatsEntry struct {
+	Name  string `json:"name"`
+	Root  string `json:"root"`
+	Error string `json:"error,omitempty"`
+	*pool.PoolStats
+}
+
+// handleStatsCmd processes the stats command.
+//
+// Pools are scanned concurrently (bounded by --jobs or max_concurrency); a pool that cannot be scanned is reported
+// with its error and left out of the totals.
+func handleStatsCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	ApplyJobsFlag(cmd)
+
+	// Pools sharing a root are scanned once per name; reuse parsed metadata
+	metadata.EnableCache()
+	defer metadata.DisableCache()
+
+	pools, err := selectedPools(cmd)
+	if err != nil {
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+
+	names := make([]string, 0, len(pools))
+	for name := range pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]poolStatsEntry, len(names))
+	_ = utils.ParallelFor(len(names), utils.MaxConcurrency(), func(i int) error {
+		name := names[i]
+		p := pools[name]
+		entry := poolStatsEntry{Name: name, Root: p.Root, PoolStats: &pool.PoolStats{}}
+		stats, err := p.Stats()
+		if err != nil {
+			log.Warnf("Failed to scan pool '%s': %v", name, err)
+			entry.Error = err.Error()
+		} else {
+			entry.PoolStats = stats
+		}
+		entries[i] = entry
+		return nil
+	})
+
+	total := &pool.PoolStats{}
+	for _, e := range entries {
+		total.Add(e.PoolStats)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"pools": entries,
+			"total": total,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	if len(entries) == 0 {
+		log.Info("No pools configured")
+		return
+	}
+
+	rows := make([][]string, 0, len(entries)+1)
+	for _, e := range entries {
+		if e.Error != "" {
+			rows = append(rows, []string{e.Name, "error", "", "", "", "", "", ""})
+			continue
+		}
+		rows = append(rows, statsRow(e.Name, e.PoolStats))
+	}
+	if len(entries) > 1 {
+		rows = append(rows, statsRow("TOTAL", total))
+	}
+	header := []string{"Pool", "Bundles", "Size", "Verified", "Unverified", "Corrupt", "Oldest", "Newest"}
+	if err := utils.WriteTable(stdout, header, rows); err != nil {
+		exitWithError(2, err, "failed to output table: %v", err)
+	}
+	fmt.Fprintf(stdout, "\nTotal: %d bundles in %d pools\n", total.Bundles, len(entries))
+}
+
+// selectedPools returns the pools to report on: the one named by --pool, or
+// every configured pool.
+func selectedPools(cmd *cobra.Command) (map[string]*pool.Pool, error) {
+	name, _ := cmd.Flags().GetString("pool")
+	if name == "" {
+		return pool.ListPools()
+	}
+	p, err := pool.GetPool(name)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]*pool.Pool{name: p}, nil
+}
+
+// statsRow formats a PoolStats as a table row.
+func statsRow(name string, s *pool.PoolStats) []string {
+	return []string{
+		name,
+		strconv.Itoa(s.Bundles),
+		formatBytes(s.SizeBytes),
+		strconv.Itoa(s.Verified),
+		strconv.Itoa(s.Unverified),
+		strconv.Itoa(s.Corrupt),
+		statsDate(s.Oldest),
+		statsDate(s.Newest),
+	}
+}
+
+// statsDate formats an optional creation time for the stats table.
+func statsDate(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
+}
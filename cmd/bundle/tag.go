@@ -5,9 +5,13 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strconv"
 
 	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/pool"
 	"github.com/jvzantvoort/bundle/tag"
 	"github.com/jvzantvoort/bundle/utils"
 	"github.com/spf13/cobra"
@@ -27,10 +31,20 @@ func init() {
 	TagCmd.Flags().StringP("tag", "T", "", "mark every line with this tag")
 	TagCmd.Flags().StringP("title", "t", "", "log the contents of this file")
 
-	// Subcommands: add, remove, list
+	// Subcommands: add, remove, list, normalize, export, import, stats
 	TagCmd.AddCommand(tagAddCmd)
 	TagCmd.AddCommand(tagRemoveCmd)
 	TagCmd.AddCommand(tagListCmd)
+	TagCmd.AddCommand(tagNormalizeCmd)
+	TagCmd.AddCommand(tagExportCmd)
+	TagCmd.AddCommand(tagImportCmd)
+	TagCmd.AddCommand(tagStatsCmd)
+
+	tagExportCmd.Flags().StringP("pool", "p", "default", "pool name to export tags from")
+	tagExportCmd.Flags().Bool("csv", false, "write CSV instead of JSON (same as --output csv)")
+	tagImportCmd.Flags().StringP("pool", "p", "default", "pool name to import tags to")
+	tagImportCmd.Flags().Bool("replace", false, "replace existing tags instead of merging")
+	tagStatsCmd.Flags().StringP("pool", "p", "default", "pool name to count tags in")
 }
 
 func handleTagCmd(cmd *cobra.Command, args []string) {
@@ -41,11 +55,7 @@ func handleTagCmd(cmd *cobra.Command, args []string) {
 	defer log.Debugf("%s: end", cmd.Use)
 
 	if len(args) != 1 {
-		log.Error("No path provided")
-		if err := cmd.Help(); err != nil {
-			log.Error(err)
-		}
-		os.Exit(1)
+		exitWithUsage(cmd, "No path provided")
 	}
 }
 
@@ -65,50 +75,44 @@ func handleTagAddCmd(cmd *cobra.Command, args []string) {
 	defer log.Debugf("%s: end", cmd.Use)
 
 	if len(args) < 2 {
-		log.Error("Usage: bundle tag add <path> <tag> [<tag>...]")
-		if err := cmd.Help(); err != nil {
-			log.Error(err)
-		}
-		os.Exit(1)
+		exitWithUsage(cmd, "Usage: bundle tag add <path> <tag> [<tag>...]")
 	}
 
 	path := args[0]
 	// Validate path exists and is a directory (user error if not)
 	if fi, err := os.Stat(path); err != nil {
 		if os.IsNotExist(err) {
-			log.Errorf("Path does not exist: %s", path)
-			os.Exit(1)
+			exitWithError(1, nil, "Path does not exist: %s", path)
 		}
-		log.Errorf("System error: %v", err)
-		os.Exit(2)
+		exitWithError(2, err, "System error: %v", err)
 	} else if !fi.IsDir() {
-		log.Errorf("Path is not a directory: %s", path)
-		os.Exit(1)
+		exitWithError(1, nil, "Path is not a directory: %s", path)
 	}
 	tags := args[1:]
 
-	t, err := tag.Load(path)
+	var t *tag.Tags
+	err := withBundleLock(path, func() error {
+		var err error
+		if t, err = tag.Load(path); err != nil {
+			return err
+		}
+		t.Add(tags...)
+		return t.Save(path)
+	})
 	if err != nil {
-		log.Errorf("System error: %v", err)
-		os.Exit(2)
+		exitIfLocked(err)
+		exitWithError(2, err, "System error: %v", err)
 	}
 
-	t.Add(tags...)
-	if err := t.Save(path); err != nil {
-		log.Errorf("System error: %v", err)
-		os.Exit(2)
-	}
-
-	jsonOut := jsonOutput
-	if jsonOut {
+	if structuredOutput {
 		out := map[string]interface{}{
 			"status": "added",
 			"path":   path,
 			"tags":   t.List(),
 		}
-		if err := utils.OutputJSON(out); err != nil {
-			log.Errorf("failed to output json: %v", err)
-			os.Exit(2)
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
 		}
 		return
 	}
@@ -116,7 +120,7 @@ func handleTagAddCmd(cmd *cobra.Command, args []string) {
 	log.Debug("Tags Added")
 	// Print tags
 	for _, v := range t.List() {
-		fmt.Println(v)
+		fmt.Fprintln(stdout, v)
 	}
 }
 
@@ -136,50 +140,46 @@ func handleTagRemoveCmd(cmd *cobra.Command, args []string) {
 	defer log.Debugf("%s: end", cmd.Use)
 
 	if len(args) < 2 {
-		log.Error("Usage: bundle tag remove <path> <tag> [<tag>...]")
-		if err := cmd.Help(); err != nil {
-			log.Error(err)
-		}
-		os.Exit(1)
+		exitWithUsage(cmd, "Usage: bundle tag remove <path> <tag> [<tag>...]")
 	}
 
 	path := args[0]
 	// Validate path exists and is a directory (user error if not)
 	if fi, err := os.Stat(path); err != nil {
 		if os.IsNotExist(err) {
-			log.Errorf("Path does not exist: %s", path)
-			os.Exit(1)
+			exitWithError(1, nil, "Path does not exist: %s", path)
 		}
-		log.Errorf("System error: %v", err)
-		os.Exit(2)
+		exitWithError(2, err, "System error: %v", err)
 	} else if !fi.IsDir() {
-		log.Errorf("Path is not a directory: %s", path)
-		os.Exit(1)
+		exitWithError(1, nil, "Path is not a directory: %s", path)
 	}
 	tags := args[1:]
 
-	t, err := tag.Load(path)
+	var t *tag.Tags
+	var removed []string
+	err := withBundleLock(path, func() error {
+		var err error
+		if t, err = tag.Load(path); err != nil {
+			return err
+		}
+		removed = t.Remove(tags...)
+		return t.Save(path)
+	})
 	if err != nil {
-		log.Errorf("System error: %v", err)
-		os.Exit(2)
-	}
-
-	t.Remove(tags...)
-	if err := t.Save(path); err != nil {
-		log.Errorf("System error: %v", err)
-		os.Exit(2)
+		exitIfLocked(err)
+		exitWithError(2, err, "System error: %v", err)
 	}
 
-	jsonOut := jsonOutput
-	if jsonOut {
+	if structuredOutput {
 		out := map[string]interface{}{
-			"status": "removed",
-			"path":   path,
-			"tags":   tags,
+			"status":  "removed",
+			"path":    path,
+			"tags":    t.List(),
+			"removed": removed,
 		}
-		if err := utils.OutputJSON(out); err != nil {
-			log.Errorf("failed to output json: %v", err)
-			os.Exit(2)
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
 		}
 		return
 	}
@@ -203,41 +203,32 @@ func handleTagListCmd(cmd *cobra.Command, args []string) {
 	defer log.Debugf("%s: end", cmd.Use)
 
 	if len(args) != 1 {
-		log.Error("Usage: bundle tag list <path>")
-		if err := cmd.Help(); err != nil {
-			log.Error(err)
-		}
-		os.Exit(1)
+		exitWithUsage(cmd, "Usage: bundle tag list <path>")
 	}
 
 	path := args[0]
 	// Validate path exists and is a directory (user error if not)
 	if fi, err := os.Stat(path); err != nil {
 		if os.IsNotExist(err) {
-			log.Errorf("Path does not exist: %s", path)
-			os.Exit(1)
+			exitWithError(1, nil, "Path does not exist: %s", path)
 		}
-		log.Errorf("System error: %v", err)
-		os.Exit(2)
+		exitWithError(2, err, "System error: %v", err)
 	} else if !fi.IsDir() {
-		log.Errorf("Path is not a directory: %s", path)
-		os.Exit(1)
+		exitWithError(1, nil, "Path is not a directory: %s", path)
 	}
 	t, err := tag.Load(path)
 	if err != nil {
-		log.Errorf("System error: %v", err)
-		os.Exit(2)
+		exitWithError(2, err, "System error: %v", err)
 	}
 
-	jsonOut := jsonOutput
-	if jsonOut {
+	if structuredOutput {
 		out := map[string]interface{}{
 			"path": path,
 			"tags": t.List(),
 		}
-		if err := utils.OutputJSON(out); err != nil {
-			log.Errorf("failed to output json: %v", err)
-			os.Exit(2)
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
 		}
 		return
 	}
@@ -248,6 +239,258 @@ func handleTagListCmd(cmd *cobra.Command, args []string) {
 	}
 
 	for _, v := range t.List() {
-		fmt.Println(v)
+		fmt.Fprintln(stdout, v)
+	}
+}
+
+// tag normalize
+var tagNormalizeCmd = &cobra.Command{
+	Use:   messages.GetUse("tag_normalize"),
+	Short: messages.GetShort("tag_normalize"),
+	Long:  messages.GetLong("tag_normalize"),
+	Run:   handleTagNormalizeCmd,
+}
+
+func handleTagNormalizeCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 1 {
+		exitWithUsage(cmd, "Usage: bundle tag normalize <path>")
+	}
+
+	path := args[0]
+	// Validate path exists and is a directory (user error if not)
+	if fi, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			exitWithError(1, nil, "Path does not exist: %s", path)
+		}
+		exitWithError(2, err, "System error: %v", err)
+	} else if !fi.IsDir() {
+		exitWithError(1, nil, "Path is not a directory: %s", path)
+	}
+
+	var result *tag.NormalizeResult
+	err := withBundleLock(path, func() error {
+		var err error
+		result, err = tag.Normalize(path)
+		return err
+	})
+	if err != nil {
+		exitIfLocked(err)
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"status":  "normalized",
+			"path":    path,
+			"tags":    result.Tags,
+			"changed": result.Changed,
+			"dropped": result.Dropped,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	log.Infof("Normalized tags: %d changed, %d dropped", result.Changed, result.Dropped)
+}
+
+// tag export
+var tagExportCmd = &cobra.Command{
+	Use:   messages.GetUse("tag_export"),
+	Short: messages.GetShort("tag_export"),
+	Long:  messages.GetLong("tag_export"),
+	Run:   handleTagExportCmd,
+
+	Annotations: map[string]string{csvAnnotation: "true"},
+}
+
+func handleTagExportCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	poolName, _ := cmd.Flags().GetString("pool")
+	asCSV, _ := cmd.Flags().GetBool("csv")
+	asCSV = asCSV || outputFormat == "csv"
+	if outputFormat == "yaml" {
+		exitWithUsage(cmd, "tag export writes JSON or CSV; use --output json or --output csv")
+	}
+
+	p, err := pool.GetPool(poolName)
+	if err != nil {
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+
+	mapping, err := p.ExportTags()
+	if err != nil {
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	// --output-file redirects stdout to the file
+	if err := tag.WriteMapping(stdout, mapping, asCSV); err != nil {
+		exitWithError(2, err, "System error: %v", err)
+	}
+	if outputFilePath != "" {
+		log.Infof("Exported tags of %d bundles to %s", len(mapping), outputFilePath)
+	}
+}
+
+// tag import
+var tagImportCmd = &cobra.Command{
+	Use:   messages.GetUse("tag_import"),
+	Short: messages.GetShort("tag_import"),
+	Long:  messages.GetLong("tag_import"),
+	Run:   handleTagImportCmd,
+}
+
+func handleTagImportCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 1 {
+		exitWithUsage(cmd, "Usage: bundle tag import --pool <name> <file>")
+	}
+
+	poolName, _ := cmd.Flags().GetString("pool")
+	replace, _ := cmd.Flags().GetBool("replace")
+
+	p, err := pool.GetPool(poolName)
+	if err != nil {
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+
+	// "-" reads the mapping from stdin
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			if os.IsNotExist(err) {
+				exitWithError(1, nil, "File does not exist: %s", args[0])
+			}
+			exitWithError(2, err, "System error: %v", err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	mapping, err := tag.ReadMapping(r)
+	if err != nil {
+		exitWithError(1, err, "%v", err)
+	}
+
+	result, err := p.ImportTags(mapping, replace)
+	if err != nil {
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"status":   "imported",
+			"pool":     poolName,
+			"replace":  replace,
+			"updated":  result.Updated,
+			"rejected": result.Rejected,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+	} else {
+		for _, reject := range result.Rejected {
+			if reject.Tag != "" {
+				log.Warnf("Rejected %s: %s %q", reject.Checksum, reject.Reason, reject.Tag)
+			} else {
+				log.Warnf("Rejected %s: %s", reject.Checksum, reject.Reason)
+			}
+		}
+		log.Infof("Tagged %d bundles, %d entries rejected", result.Updated, len(result.Rejected))
+	}
+
+	if len(result.Rejected) > 0 {
+		exit(1)
+	}
+}
+
+// tag stats
+var tagStatsCmd = &cobra.Command{
+	Use:   messages.GetUse("tag_stats"),
+	Short: messages.GetShort("tag_stats"),
+	Long:  messages.GetLong("tag_stats"),
+	Run:   handleTagStatsCmd,
+}
+
+// tagCount is one tag in tag stats output, used for JSON
+type tagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+func handleTagStatsCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	poolName, _ := cmd.Flags().GetString("pool")
+
+	p, err := pool.GetPool(poolName)
+	if err != nil {
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+
+	counts, err := p.TagCounts()
+	if err != nil {
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	// Most common first, ties alphabetically
+	stats := make([]tagCount, 0, len(counts))
+	for t, n := range counts {
+		stats = append(stats, tagCount{Tag: t, Count: n})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].Tag < stats[j].Tag
+	})
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"pool": poolName,
+			"tags": stats,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	if len(stats) == 0 {
+		log.Info("No tags in pool")
+		return
+	}
+
+	rows := make([][]string, len(stats))
+	for i, st := range stats {
+		rows[i] = []string{st.Tag, strconv.Itoa(st.Count)}
+	}
+	if err := utils.WriteTable(stdout, []string{"Tag", "Bundles"}, rows); err != nil {
+		exitWithError(2, err, "failed to output table: %v", err)
 	}
 }
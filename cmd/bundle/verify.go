@@ -4,7 +4,11 @@ Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
 package main
 
 import (
+	"context"
+	"errors"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/jvzantvoort/bundle/messages"
 	"github.com/jvzantvoort/bundle/bundle"
@@ -23,6 +27,11 @@ var VerifyCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(VerifyCmd)
+	VerifyCmd.Flags().Int("jobs", 0, jobsFlagUsage)
+	VerifyCmd.Flags().String("file", "", "only verify this file, relative to the bundle root")
+	VerifyCmd.Flags().Bool("resume", false, "skip files already checked by an interrupted run")
+	VerifyCmd.Flags().Bool("strict", false, "fail when files not in the manifest are present")
+	VerifyCmd.Flags().String("emit-manifest", "", "write the checksums of the files on disk, untracked ones included, in sha256sum format to this file")
 	VerifyCmd.Flags().StringP("tag", "T", "", "mark every line with this tag")
 	VerifyCmd.Flags().StringP("title", "t", "", "log the contents of this file")
 }
@@ -35,46 +44,141 @@ func handleVerifyCmd(cmd *cobra.Command, args []string) {
 	defer log.Debugf("%s: end", cmd.Use)
 
 	if len(args) != 1 {
-		log.Error("No path provided")
-		if err := cmd.Help(); err != nil {
-			log.Error(err)
-		}
-		os.Exit(1)
+		exitWithUsage(cmd, "No path provided")
 	}
 
+	ApplyJobsFlag(cmd)
 	path := args[0]
 
-	verified, corrupted, err := bundle.Verify(path)
+	if relPath := GetString(*cmd, "file"); relPath != "" {
+		verifySingleFile(path, relPath)
+		return
+	}
+
+	resume, _ := cmd.Flags().GetBool("resume")
+	strict, _ := cmd.Flags().GetBool("strict")
+	emitManifest := GetString(*cmd, "emit-manifest")
+
+	// Stop on Ctrl-C or SIGTERM so the progress is saved for --resume
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	report, err := bundle.VerifyWithOptions(path, bundle.VerifyOptions{Resume: resume, Strict: strict, Context: ctx})
+	stop()
 	if err != nil {
 		if os.IsNotExist(err) {
-			log.Errorf("directory does not exist: %s", path)
-			os.Exit(1)
+			exitWithError(1, nil, "directory does not exist: %s", path)
+		}
+		exitIfLocked(err)
+		if errors.Is(err, context.Canceled) {
+			exitWithError(2, err, "Verification interrupted; run again with --resume to continue")
 		}
-		log.Errorf("System error: %v", err)
-		os.Exit(2)
+		exitWithError(2, err, "System error: %v", err)
 	}
 
-	if verified {
+	if report.Verified {
 		log.Info("Bundle Integrity: VALID")
 	} else {
 		log.Info("Bundle Integrity: INVALID")
 	}
+	log.Infof("Checksum Algorithm: %s", report.Algorithm)
+	for _, extra := range report.Extra {
+		log.Warnf("Untracked file (not in the manifest): %s", extra)
+	}
 
-	if jsonOutput {
+	// Written even for an invalid bundle; that is when it is most useful
+	if emitManifest != "" {
+		current, err := report.Recomputed.WithUntracked(path, report.Extra)
+		if err != nil {
+			exitWithError(2, err, "System error: %v", err)
+		}
+		if err := current.Export(emitManifest); err != nil {
+			exitWithError(2, err, "System error: %v", err)
+		}
+		log.Infof("Recomputed manifest: %s", emitManifest)
+	}
+
+	if structuredOutput {
 		out := map[string]interface{}{
-			"status":        "",
-			"files_checked": 0,
-			"last_verified": "",
-			"corrupted_files": corrupted,
+			"status":            "",
+			"algorithm":         report.Algorithm,
+			"files_checked":     report.FilesChecked,
+			"last_verified":     "",
+			"corrupted_files":   report.CorruptedPaths(),
+			"corrupted_details": report.Corrupted,
+			"missing_files":     report.Missing,
+			"mismatched_files":  report.Mismatched,
+			"extra_files":       report.Extra,
+		}
+		if emitManifest != "" {
+			out["emitted_manifest"] = emitManifest
 		}
-		if verified {
+		if report.Verified {
 			out["status"] = "valid"
 		} else {
 			out["status"] = "invalid"
 		}
-		if err := utils.OutputJSON(out); err != nil {
-			log.Errorf("failed to output json: %v", err)
-			os.Exit(2)
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+	} else if verbose && len(report.Corrupted) > 0 {
+		// Expected vs actual sizes tell truncation apart from content edits
+		rows := make([][]string, 0, len(report.Corrupted))
+		for _, c := range report.Corrupted {
+			rows = append(rows, []string{c.Path, c.Reason, formatSizePtr(c.ExpectedSize), formatSizePtr(c.ActualSize)})
+		}
+		if err := utils.WriteTable(stdout, []string{"File", "Reason", "Expected Size", "Actual Size"}, rows); err != nil {
+			exitWithError(2, err, "failed to output table: %v", err)
+		}
+	}
+
+	// Strict verification is meant for scripts, so failures set the exit code
+	if strict && !report.Verified {
+		exit(1)
+	}
+}
+
+// verifySingleFile handles verify --file: it checks one file and exits 1
+// when it does not match or is not tracked in the bundle.
+func verifySingleFile(path, relPath string) {
+	ok, err := bundle.VerifyFile(path, relPath)
+	if errors.Is(err, utils.ErrFileNotTracked) {
+		exitWithError(1, utils.ErrFileNotTracked, "%s is not tracked in bundle %s", relPath, path)
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			exitWithError(1, nil, "directory does not exist: %s", path)
+		}
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	status := "valid"
+	if !ok {
+		status = "invalid"
+	}
+	if structuredOutput {
+		out := map[string]interface{}{
+			"status": status,
+			"file":   relPath,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
 		}
+	} else if ok {
+		log.Infof("File Integrity: VALID (%s)", relPath)
+	} else {
+		log.Infof("File Integrity: INVALID (%s)", relPath)
+	}
+
+	if !ok {
+		exit(1)
+	}
+}
+
+// formatSizePtr formats an optional size, "-" when unknown
+func formatSizePtr(size *int64) string {
+	if size == nil {
+		return "-"
 	}
+	return formatBytes(*size)
 }
@@ -0,0 +1,140 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jvzantvoort/bundle/checksum"
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// VerifyFileCmd represents the verify-file command.
+//
+// It hashes a single file, which need not be part of a bundle, and compares
+// the result with an expected checksum given as argument or on stdin.
+//
+// Usage:
+//
+//	bundle verify-file <path> [expected-checksum|-]
+//
+// Example:
+//
+//	bundle verify-file ./photo.jpg e3b0c442...
+//	sha256sum photo.jpg | bundle verify-file ./photo.jpg -
+var VerifyFileCmd = &cobra.Command{
+	Use:   messages.GetUse("verify_file"),
+	Short: messages.GetShort("verify_file"),
+	Long:  messages.GetLong("verify_file"),
+	Run:   handleVerifyFileCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(VerifyFileCmd)
+	VerifyFileCmd.Flags().String("algo", checksum.Algorithm, "hash algorithm of the expected checksum: sha256 or sha512")
+}
+
+// handleVerifyFileCmd processes the verify-file command.
+//
+// It exits 0 when the checksums match and 1 when they differ or the input
+// is invalid; read errors other than a missing file exit 2.
+func handleVerifyFileCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) < 1 || len(args) > 2 {
+		exitWithUsage(cmd, "Usage: bundle verify-file <path> [expected-checksum|-]")
+	}
+	path := args[0]
+
+	algo, err := checksum.ParseHashAlgorithm(GetString(*cmd, "algo"))
+	if err != nil {
+		exitWithError(1, err, "%v", err)
+	}
+
+	var expected string
+	if len(args) == 2 && args[1] != "-" {
+		expected = args[1]
+	} else {
+		expected, err = readExpectedChecksum(os.Stdin)
+		if err != nil {
+			exitWithError(1, err, "Failed to read expected checksum: %v", err)
+		}
+	}
+	expected = strings.ToLower(strings.TrimSpace(expected))
+	if !algo.IsChecksum(expected) {
+		exitWithError(1, nil, "Invalid %s checksum: '%s'", algo, expected)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			exitWithError(1, nil, "File not found: %s", path)
+		}
+		exitWithError(2, err, "System error: %v", err)
+	}
+	if info.IsDir() {
+		exitWithError(1, nil, "%s is a directory, use 'bundle verify' for bundles", path)
+	}
+
+	log.Debugf("Hashing file: %s", path)
+	actual, err := checksum.ComputeFileHash(path, algo)
+	if err != nil {
+		exitWithError(utils.ExitCodeFromError(err), err, "Failed to compute checksum: %v", err)
+	}
+	match := actual == expected
+
+	if structuredOutput {
+		status := "valid"
+		if !match {
+			status = "invalid"
+		}
+		out := map[string]interface{}{
+			"status":    status,
+			"path":      path,
+			"algorithm": algo,
+			"expected":  expected,
+			"actual":    actual,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+	} else if match {
+		fmt.Fprintf(stdout, "%s: OK\n", path)
+	} else {
+		fmt.Fprintf(stdout, "%s: FAILED\n", path)
+		fmt.Fprintf(stdout, "Expected: %s\n", expected)
+		fmt.Fprintf(stdout, "Actual:   %s\n", actual)
+	}
+
+	if !match {
+		exit(1)
+	}
+}
+
+// readExpectedChecksum returns the first field of the first non-empty line
+// of r, so both a bare checksum and a sha256sum(1) output line are accepted.
+func readExpectedChecksum(r io.Reader) (string, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			return fields[0], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("no checksum on stdin")
+}
@@ -0,0 +1,162 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/pool"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// VerifyPoolCmd represents the pool verify command.
+//
+// It checks that every bundle in a pool still recomputes to the checksum
+// its directory is named by.
+//
+// Usage:
+//
+//	bundle pool verify [--pool <name>]
+var VerifyPoolCmd = &cobra.Command{
+	Use:   messages.GetUse("pool_verify"),
+	Short: messages.GetShort("pool_verify"),
+	Long:  messages.GetLong("pool_verify"),
+	Run:   handleVerifyPoolCmd,
+}
+
+// VerifyPoolTopCmd is the top-level verify-pool command, an equivalent of
+// pool verify with the same flags.
+//
+// Usage:
+//
+//	bundle verify-pool [--pool <name>]
+var VerifyPoolTopCmd = &cobra.Command{
+	Use:   messages.GetUse("verify_pool"),
+	Short: messages.GetShort("verify_pool"),
+	Long:  messages.GetLong("pool_verify"),
+	Run:   handleVerifyPoolCmd,
+}
+
+func init() {
+	PoolCmd.AddCommand(VerifyPoolCmd)
+	rootCmd.AddCommand(VerifyPoolTopCmd)
+	for _, cmd := range []*cobra.Command{VerifyPoolCmd, VerifyPoolTopCmd} {
+		cmd.Flags().StringP("pool", "p", "default", "pool name to verify")
+		cmd.Flags().Int("jobs", 0, jobsFlagUsage)
+	}
+}
+
+// poolVerifyError is a bundle pool verify could not check.
+type poolVerifyError struct {
+	Checksum string `json:"checksum"`
+	Error    string `json:"error"`
+}
+
+// poolVerifyResult is the outcome for one bundle, keyed by checksum in the
+// JSON output.
+type poolVerifyResult struct {
+	Title  string `json:"title"`
+	Status string `json:"status"` // "valid", "invalid" or "error"
+	Error  string `json:"error,omitempty"`
+}
+
+// handleVerifyPoolCmd processes the pool verify and verify-pool commands.
+//
+// Bundles are checked concurrently (bounded by --jobs or max_concurrency);
+// while more than one bundle is checked at a time, the files of each bundle
+// are hashed sequentially, so that bound covers all hashing workers.
+// It exits 1 when a bundle does not match its checksum and 2 when a bundle
+// could not be checked.
+func handleVerifyPoolCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	ApplyJobsFlag(cmd)
+	poolName, _ := cmd.Flags().GetString("pool")
+
+	p, err := pool.GetPool(poolName)
+	if err != nil {
+		exitWithError(1, err, "Pool error: %v", err)
+	}
+
+	bundles, err := p.ListBundles()
+	if err != nil {
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	// Bundles are the unit of parallelism: when several are checked at once,
+	// each hashes its files one at a time so --jobs bounds the total number
+	// of workers instead of multiplying with io_concurrency and
+	// hash_concurrency.
+	workers := utils.MaxConcurrency()
+	if workers > 1 && len(bundles) > 1 {
+		viper.Set("io_concurrency", 1)
+		viper.Set("hash_concurrency", 1)
+	}
+
+	results := make([]bool, len(bundles))
+	errs := make([]error, len(bundles))
+	_ = utils.ParallelFor(len(bundles), workers, func(i int) error {
+		results[i], errs[i] = p.VerifyBundle(bundles[i].BundleChecksum)
+		return nil
+	})
+
+	mismatched := []string{}
+	failed := []poolVerifyError{}
+	perBundle := make(map[string]poolVerifyResult, len(bundles))
+	for i, meta := range bundles {
+		result := poolVerifyResult{Title: meta.Title, Status: "valid"}
+		switch {
+		case errs[i] != nil:
+			log.Errorf("Cannot verify %s: %v", meta.BundleChecksum, errs[i])
+			failed = append(failed, poolVerifyError{Checksum: meta.BundleChecksum, Error: errs[i].Error()})
+			result.Status = "error"
+			result.Error = errs[i].Error()
+		case !results[i]:
+			log.Errorf("Content no longer matches checksum: %s (%s)", meta.BundleChecksum, meta.Title)
+			mismatched = append(mismatched, meta.BundleChecksum)
+			result.Status = "invalid"
+		default:
+			log.Debugf("Valid: %s (%s)", meta.BundleChecksum, meta.Title)
+		}
+		perBundle[meta.BundleChecksum] = result
+	}
+	valid := len(bundles) - len(mismatched) - len(failed)
+
+	if structuredOutput {
+		status := "valid"
+		if len(mismatched) > 0 {
+			status = "invalid"
+		}
+		out := map[string]interface{}{
+			"status":     status,
+			"pool":       poolName,
+			"checked":    len(bundles),
+			"valid":      valid,
+			"invalid":    len(mismatched),
+			"mismatched": mismatched,
+			"errors":     failed,
+			"results":    perBundle,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+	} else {
+		log.Infof("Checked %d bundles in pool '%s': %d valid, %d invalid, %d not checked",
+			len(bundles), poolName, valid, len(mismatched), len(failed))
+	}
+
+	switch {
+	case len(mismatched) > 0:
+		exit(1)
+	case len(failed) > 0:
+		exit(2)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright © 2025 John van Zantvoort <john@vanzantvoort.org>
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jvzantvoort/bundle/messages"
+	"github.com/jvzantvoort/bundle/scanner"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// WalkCmd represents the walk command.
+//
+// It lists the files the scanner finds in a directory, with .bundle/
+// excluded, so the contents of a bundle can be previewed before create.
+//
+// Usage:
+//
+//	bundle walk <dir> [--symlinks] [--exclude PATTERN]...
+var WalkCmd = &cobra.Command{
+	Use:   messages.GetUse("walk"),
+	Short: messages.GetShort("walk"),
+	Long:  messages.GetLong("walk"),
+	Run:   handleWalkCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(WalkCmd)
+	WalkCmd.Flags().Bool("symlinks", false, "follow symlinks to files and directories")
+	WalkCmd.Flags().StringArray("exclude", nil, "leave out paths matching this gitignore-style pattern (repeatable)")
+}
+
+// walkEntry is one file in the JSON output of the walk command.
+type walkEntry struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// handleWalkCmd processes the walk command.
+//
+// It prints one path per line, relative to dir where possible, or a JSON
+// document with the paths and sizes. A missing directory exits 1, walk
+// errors exit 2.
+func handleWalkCmd(cmd *cobra.Command, args []string) {
+	if verbose {
+		log.SetLevel(log.DebugLevel)
+	}
+	log.Debugf("%s: start", cmd.Use)
+	defer log.Debugf("%s: end", cmd.Use)
+
+	if len(args) != 1 {
+		exitWithUsage(cmd, "Usage: bundle walk <dir>")
+	}
+	dir := args[0]
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			exitWithError(1, nil, "directory does not exist: %s", dir)
+		}
+		exitWithError(2, err, "System error: %v", err)
+	}
+	if !info.IsDir() {
+		exitWithError(1, nil, "%s is not a directory", dir)
+	}
+
+	exclude, _ := cmd.Flags().GetStringArray("exclude")
+	if err := scanner.Excludes(exclude).Validate(); err != nil {
+		exitWithError(1, err, "%v", err)
+	}
+
+	symlinks, _ := cmd.Flags().GetBool("symlinks")
+	var entries []walkEntry
+	if symlinks {
+		entries, err = walkSymlinks(dir, exclude)
+	} else {
+		entries, err = walkFiles(dir, exclude)
+	}
+	if err != nil {
+		exitWithError(2, err, "System error: %v", err)
+	}
+
+	var totalSize int64
+	for _, entry := range entries {
+		totalSize += entry.Size
+	}
+
+	if structuredOutput {
+		out := map[string]interface{}{
+			"path":       dir,
+			"count":      len(entries),
+			"total_size": totalSize,
+			"files":      entries,
+		}
+		if err := outputStructured(out); err != nil {
+			log.Errorf("failed to write output: %v", err)
+			exit(2)
+		}
+		return
+	}
+
+	for _, entry := range entries {
+		fmt.Fprintln(stdout, entry.Path)
+	}
+	log.Debugf("%d files, %s", len(entries), formatBytes(totalSize))
+}
+
+// walkFiles lists the files in dir, leaving out those matching exclude,
+// with the sizes found by the scan; only symlinks are stat'ed to report the
+// size of their target.
+func walkFiles(dir string, exclude []string) ([]walkEntry, error) {
+	files, err := scanner.ScanDirectoryInfoWithOptions(dir, scanner.ScanOptions{Exclude: exclude})
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]walkEntry, 0, len(files))
+	for _, file := range files {
+		entry := walkEntry{Path: file.RelPath, Size: file.Size}
+		if file.Mode&os.ModeSymlink != 0 {
+			entry.Size = 0
+			if fi, err := os.Stat(file.Path); err == nil {
+				entry.Size = fi.Size()
+			} else {
+				log.Warnf("Cannot stat %s: %v", file.Path, err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// walkSymlinks lists the files in dir, leaving out those matching exclude,
+// with symlinks followed; the files have to be stat'ed to get their size.
+func walkSymlinks(dir string, exclude []string) ([]walkEntry, error) {
+	files, err := scanner.ScanWithSymlinksExcluding(dir, exclude)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]walkEntry, 0, len(files))
+	for _, file := range files {
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return nil, err
+		}
+		entry := walkEntry{Path: rel}
+		if fi, err := os.Stat(file); err == nil {
+			entry.Size = fi.Size()
+		} else {
+			log.Warnf("Cannot stat %s: %v", file, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
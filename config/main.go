@@ -18,12 +18,21 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
+// Supported log_format values.
+const (
+	LogFormatText = "text" // logfmt-style key=value lines
+	LogFormatJSON = "json" // one JSON object per line, for log aggregators
+)
+
 // Config holds the application configuration.
 //
 // It contains runtime settings for logging and output control.
@@ -47,6 +56,16 @@ var (
 	//	config.Logger.WithField("path", bundlePath).Debug("Loading bundle")
 	//	config.Logger.Error("Failed to create bundle")
 	Logger = logrus.New()
+
+	// SearchPaths lists the directories searched for config.yaml, in order.
+	SearchPaths = []string{
+		"$HOME/.config/bundle",
+		"/etc/bundle",
+		".",
+	}
+
+	// loadErr holds the error from reading the configuration file, if any.
+	loadErr error
 )
 
 // InitConfig initializes the configuration system.
@@ -62,6 +81,7 @@ var (
 //	}
 func InitConfig() {
 	viper.SetDefault("log_level", "info")
+	viper.SetDefault("log_format", LogFormatText)
 	
 	// Setup logrus first so we can log config loading
 	Logger.SetOutput(os.Stderr)
@@ -73,21 +93,22 @@ func InitConfig() {
 	// Set configuration file name and locations
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
-	viper.AddConfigPath("$HOME/.config/bundle")
-	viper.AddConfigPath("/etc/bundle")
-	viper.AddConfigPath(".")
-	
 	Logger.Debugf("Configuration search paths:")
-	Logger.Debugf("  - $HOME/.config/bundle/config.yaml")
-	Logger.Debugf("  - /etc/bundle/config.yaml")
-	Logger.Debugf("  - ./config.yaml")
+	for _, path := range SearchPaths {
+		viper.AddConfigPath(path)
+		Logger.Debugf("  - %s/config.yaml", path)
+	}
 	
 	// Read configuration file (ignore if not found)
 	err := viper.ReadInConfig()
+	loadErr = err
 	if err != nil {
 		Logger.Debugf("No configuration file found: %v", err)
 		Logger.Debugf("Using default configuration")
 	} else {
+		if err := SetLogFormat(viper.GetString("log_format")); err != nil {
+			Logger.Warnf("Ignoring log_format: %v", err)
+		}
 		Logger.Infof("Configuration loaded from: %s", viper.ConfigFileUsed())
 		Logger.Debugf("Configuration content:")
 		
@@ -138,3 +159,141 @@ func SetLogLevel(verbose, quiet bool) {
 		Logger.SetLevel(logrus.InfoLevel)
 	}
 }
+
+// SetLogFormat configures how the global logger formats its entries.
+//
+// Only the diagnostic log stream is affected; command results are written
+// separately. An empty format selects text.
+//
+// Example:
+//
+//	if err := config.SetLogFormat(config.LogFormatJSON); err != nil {
+//	    log.Fatal(err)
+//	}
+//	config.Logger.Info("Bundle created") // {"level":"info","msg":"Bundle created",...}
+//
+// Parameters:
+//   - format: LogFormatText or LogFormatJSON
+//
+// Returns:
+//   - error: if format is not a supported log format
+func SetLogFormat(format string) error {
+	switch strings.ToLower(format) {
+	case "", LogFormatText:
+		Logger.SetFormatter(&logrus.TextFormatter{
+			FullTimestamp: true,
+		})
+	case LogFormatJSON:
+		Logger.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		return fmt.Errorf("invalid log format '%s': must be text or json", format)
+	}
+	return nil
+}
+
+// LoadError returns the error encountered while reading the configuration
+// file during InitConfig, or nil if a file was loaded successfully.
+//
+// A viper.ConfigFileNotFoundError means no file exists in any of the
+// SearchPaths; any other error means a file was found but could not be
+// parsed.
+//
+// Example:
+//
+//	if err := config.LoadError(); err != nil {
+//	    fmt.Printf("Config problem: %v\n", err)
+//	}
+func LoadError() error {
+	return loadErr
+}
+
+// starterConfig is the config file written by WriteStarterConfig; %q is
+// replaced by the default pool root.
+const starterConfig = `# Bundle configuration file
+#
+# Written by "bundle config init". See config.yaml.example in the bundle
+# sources for all settings.
+
+# Logging: debug, info, warn or error
+log_level: info
+# Log format: text, or json for log aggregators
+# log_format: json
+
+# Pools are named storage locations bundles are imported into. Commands use
+# the default pool unless --pool names another one.
+pools:
+  default:
+    root: %q
+    title: Default Bundle Pool
+    # layout: sharded  # flat (default) or sharded (root/ab/cd/<checksum>)
+
+  # backup:
+  #   root: /backup/bundles
+  #   title: Backup Bundle Pool
+`
+
+// SearchFiles returns the config files InitConfig looks for, in order.
+//
+// Environment variables in SearchPaths are expanded and the paths made
+// absolute, as viper does when searching.
+//
+// Example:
+//
+//	for _, path := range config.SearchFiles() {
+//	    fmt.Println(path)
+//	}
+//
+// Returns:
+//   - []string: one config.yaml path per search path
+func SearchFiles() []string {
+	files := make([]string, 0, len(SearchPaths))
+	for _, dir := range SearchPaths {
+		dir = os.ExpandEnv(dir)
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		files = append(files, filepath.Join(dir, "config.yaml"))
+	}
+	return files
+}
+
+// WriteStarterConfig writes a commented starter config file to path.
+//
+// The file sets log_level and defines a default pool rooted at poolRoot.
+// Missing parent directories are created. An existing file is only
+// replaced when force is set.
+//
+// Example:
+//
+//	path := config.SearchFiles()[0]
+//	if err := config.WriteStarterConfig(path, "/srv/bundles", false); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - path: config file to write
+//   - poolRoot: root directory of the default pool
+//   - force: replace an existing file
+//
+// Returns:
+//   - error: wrapping os.ErrExist if path exists and force is not set, or
+//     I/O errors
+func WriteStarterConfig(path, poolRoot string, force bool) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(f, starterConfig, poolRoot); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
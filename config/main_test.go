@@ -0,0 +1,105 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestWriteStarterConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bundle", "config.yaml")
+	root := "/srv/my bundles"
+
+	if err := WriteStarterConfig(path, root, false); err != nil {
+		t.Fatalf("WriteStarterConfig failed: %v", err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("starter config does not parse: %v", err)
+	}
+	if got := v.GetString("log_level"); got != "info" {
+		t.Errorf("log_level = %q, want info", got)
+	}
+	if got := v.GetString("pools.default.root"); got != root {
+		t.Errorf("pools.default.root = %q, want %q", got, root)
+	}
+
+	// An existing file is kept without force
+	if err := os.WriteFile(path, []byte("log_level: debug\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := WriteStarterConfig(path, root, false); !errors.Is(err, os.ErrExist) {
+		t.Errorf("error = %v, want os.ErrExist", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "log_level: debug\n" {
+		t.Errorf("existing file changed: %q", data)
+	}
+
+	// and replaced with force
+	if err := WriteStarterConfig(path, root, true); err != nil {
+		t.Fatalf("WriteStarterConfig with force failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) == "log_level: debug\n" {
+		t.Error("existing file not replaced with force")
+	}
+}
+
+func TestSearchFiles(t *testing.T) {
+	t.Setenv("HOME", "/home/test")
+	files := SearchFiles()
+	if len(files) != len(SearchPaths) {
+		t.Fatalf("got %d files, want %d", len(files), len(SearchPaths))
+	}
+	if want := "/home/test/.config/bundle/config.yaml"; files[0] != want {
+		t.Errorf("first file = %q, want %q", files[0], want)
+	}
+	for _, f := range files {
+		if !filepath.IsAbs(f) {
+			t.Errorf("%q is not absolute", f)
+		}
+	}
+}
+
+func TestSetLogFormat(t *testing.T) {
+	formatter, out := Logger.Formatter, Logger.Out
+	defer func() {
+		Logger.SetFormatter(formatter)
+		Logger.SetOutput(out)
+	}()
+
+	var buf bytes.Buffer
+	Logger.SetOutput(&buf)
+
+	if err := SetLogFormat(LogFormatJSON); err != nil {
+		t.Fatalf("SetLogFormat(json) failed: %v", err)
+	}
+	Logger.Info("hello")
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log entry %q is not JSON: %v", buf.String(), err)
+	}
+	if entry["msg"] != "hello" || entry["level"] != "info" {
+		t.Errorf("log entry = %v", entry)
+	}
+
+	buf.Reset()
+	if err := SetLogFormat(LogFormatText); err != nil {
+		t.Fatalf("SetLogFormat(text) failed: %v", err)
+	}
+	Logger.Info("hello")
+	if !strings.Contains(buf.String(), `msg=hello`) {
+		t.Errorf("text log entry = %q", buf.String())
+	}
+
+	if err := SetLogFormat("xml"); err == nil {
+		t.Error("expected an error for an unknown log format")
+	}
+}
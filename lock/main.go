@@ -1,8 +1,11 @@
 // Package lock provides file-based locking for concurrent bundle operations.
 //
-// It implements exclusive locking to prevent multiple processes from modifying
-// a bundle simultaneously. Locks are fail-fast and atomic using OS-level file
-// creation primitives.
+// It implements reader/writer locking: an exclusive lock prevents multiple
+// processes from modifying a bundle simultaneously, and shared locks let
+// any number of readers run while no writer does. Locks are atomic using
+// OS-level file creation primitives. AcquireLock and AcquireSharedLock are
+// fail-fast; the Wait variants poll until a timeout for a lock held by
+// another process.
 //
 // Example usage:
 //
@@ -18,9 +21,28 @@
 package lock
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// WaitTimeout is how long write operations wait for a lock held by another
+// process. Zero, the default, fails immediately. It is set by the global
+// --lock-timeout flag.
+var WaitTimeout time.Duration
+
+// exclusiveName is the lock file of the exclusive lock in .bundle/.
+const exclusiveName = ".lock"
+
+// Backoff bounds for AcquireLockWait polling.
+const (
+	minLockPoll = 10 * time.Millisecond
+	maxLockPoll = 500 * time.Millisecond
 )
 
 // Lock represents a bundle lock.
@@ -43,16 +65,18 @@ type Lock struct {
 // AcquireLock attempts to acquire a lock on the bundle (fail-fast).
 //
 // It creates a lock file at .bundle/.lock atomically. If the lock file already
-// exists (another process holds the lock), it returns an error immediately
-// without waiting.
+// exists (another process holds the lock), or shared locks are held by
+// readers, it returns utils.ErrBundleLocked immediately without waiting.
 //
-// The lock file contains the PID of the process holding the lock for debugging.
+// The lock file records the PID, host name and creation time of the holder.
+// A lock left behind by a process that is no longer running, or older than
+// MaxAge, is stale: it is reclaimed and the lock acquired as usual.
 //
 // Example:
 //
 //	lock, err := lock.AcquireLock("/path/to/bundle")
 //	if err != nil {
-//	    if strings.Contains(err.Error(), "locked by another process") {
+//	    if errors.Is(err, utils.ErrBundleLocked) {
 //	        log.Fatal("Bundle is currently in use")
 //	    }
 //	    log.Fatal(err)
@@ -68,7 +92,76 @@ type Lock struct {
 //   - *Lock: lock handle for Release()
 //   - error: if lock is held by another process or .bundle/ cannot be created
 func AcquireLock(bundlePath string) (*Lock, error) {
-	lockPath := filepath.Join(bundlePath, ".bundle", ".lock")
+	l, err := createExclusive(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	if hasReaders(filepath.Dir(l.lockPath)) {
+		l.Release()
+		return nil, utils.ErrBundleLocked
+	}
+	return l, nil
+}
+
+// AcquireLockWait acquires a lock on the bundle, waiting for another
+// process to release it.
+//
+// It retries with exponential backoff until timeout has passed. Once the
+// exclusive lock file is created it is kept while waiting for readers
+// holding shared locks to finish, so new readers cannot keep a writer out
+// indefinitely. A zero or negative timeout makes it fail-fast like
+// AcquireLock. Errors other than lock contention are returned immediately.
+//
+// Example:
+//
+//	lock, err := lock.AcquireLockWait("/path/to/bundle", 30*time.Second)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer lock.Release()
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//   - timeout: how long to wait for the lock
+//
+// Returns:
+//   - *Lock: lock handle for Release()
+//   - error: utils.ErrBundleLocked if the lock is still held at the
+//     deadline, or if .bundle/ cannot be created
+func AcquireLockWait(bundlePath string, timeout time.Duration) (*Lock, error) {
+	if timeout <= 0 {
+		return AcquireLock(bundlePath)
+	}
+	deadline := time.Now().Add(timeout)
+
+	var l *Lock
+	err := pollUntil(deadline, func() error {
+		var err error
+		l, err = createExclusive(bundlePath)
+		return err
+	})
+	if err != nil {
+		return nil, waitError(err, timeout)
+	}
+
+	// Holding the lock file keeps new readers out while current ones drain
+	err = pollUntil(deadline, func() error {
+		if hasReaders(filepath.Dir(l.lockPath)) {
+			return utils.ErrBundleLocked
+		}
+		return nil
+	})
+	if err != nil {
+		l.Release()
+		return nil, waitError(err, timeout)
+	}
+	return l, nil
+}
+
+// createExclusive creates .bundle/.lock, reclaiming a stale one, without
+// looking at shared locks.
+func createExclusive(bundlePath string) (*Lock, error) {
+	lockPath := filepath.Join(bundlePath, ".bundle", exclusiveName)
 
 	// Ensure the .bundle directory exists so OpenFile can create the lock
 	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
@@ -77,15 +170,20 @@ func AcquireLock(bundlePath string) (*Lock, error) {
 
 	// Atomic create-if-not-exists
 	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if os.IsExist(err) && reclaimStale(lockPath) {
+		lockFile, err = os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	}
 	if err != nil {
 		if os.IsExist(err) {
-			return nil, fmt.Errorf("bundle is locked by another process")
+			return nil, utils.ErrBundleLocked
 		}
 		return nil, err
 	}
 
-	// Write PID for debugging
-	fmt.Fprintf(lockFile, "PID: %d\n", os.Getpid())
+	// Record the holder so a stale lock can be recognized
+	if err := writeLockInfo(lockFile, time.Now()); err != nil {
+		log.Debugf("failed to write lock info: %v", err)
+	}
 
 	return &Lock{
 		lockPath: lockPath,
@@ -93,9 +191,35 @@ func AcquireLock(bundlePath string) (*Lock, error) {
 	}, nil
 }
 
+// pollUntil calls try until it returns anything but utils.ErrBundleLocked
+// or deadline passes, backing off exponentially between attempts.
+func pollUntil(deadline time.Time, try func() error) error {
+	poll := minLockPoll
+	for {
+		err := try()
+		if !errors.Is(err, utils.ErrBundleLocked) {
+			return err
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return err
+		}
+		time.Sleep(min(poll, remaining))
+		poll = min(2*poll, maxLockPoll)
+	}
+}
+
+// waitError notes the time waited on a lock contention error.
+func waitError(err error, timeout time.Duration) error {
+	if errors.Is(err, utils.ErrBundleLocked) {
+		return fmt.Errorf("%w (waited %s)", err, timeout)
+	}
+	return err
+}
+
 // Release removes the lock.
 //
-// It closes the lock file handle and deletes .bundle/.lock. Should always be
+// It closes the lock file handle and deletes the lock file. Should always be
 // called when operations are complete, typically via defer.
 //
 // Example:
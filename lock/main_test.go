@@ -0,0 +1,169 @@
+package lock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+func TestAcquireLockWait(t *testing.T) {
+	dir := t.TempDir()
+	held, err := AcquireLock(dir)
+	if err != nil {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+
+	// A zero timeout is fail-fast
+	if _, err := AcquireLockWait(dir, 0); !errors.Is(err, utils.ErrBundleLocked) {
+		t.Fatalf("zero timeout error = %v, want ErrBundleLocked", err)
+	}
+	if _, err := AcquireLockWait(dir, 30*time.Millisecond); !errors.Is(err, utils.ErrBundleLocked) {
+		t.Fatalf("expired timeout error = %v, want ErrBundleLocked", err)
+	}
+
+	// The lock is taken once the holder releases it
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		held.Release()
+	}()
+	l, err := AcquireLockWait(dir, 5*time.Second)
+	if err != nil {
+		t.Fatalf("AcquireLockWait failed: %v", err)
+	}
+	if err := l.Release(); err != nil {
+		t.Errorf("Release failed: %v", err)
+	}
+}
+
+// deadPID returns the PID of a process that has exited.
+func deadPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("run child: %v", err)
+	}
+	return cmd.Process.Pid
+}
+
+func TestAcquireLockStale(t *testing.T) {
+	host, _ := os.Hostname()
+	old := time.Now().Add(-2 * MaxAge())
+	for _, tc := range []struct {
+		name    string
+		content string
+		stale   bool
+	}{
+		{"dead process", fmt.Sprintf("PID: %d\nHost: %s\nCreated: %s\n", deadPID(t), host, time.Now().Format(time.RFC3339Nano)), true},
+		{"dead process, old format", fmt.Sprintf("PID: %d\n", deadPID(t)), true},
+		{"live process", fmt.Sprintf("PID: %d\nHost: %s\nCreated: %s\n", os.Getpid(), host, time.Now().Format(time.RFC3339Nano)), false},
+		{"live process past max age", fmt.Sprintf("PID: %d\nHost: %s\nCreated: %s\n", os.Getpid(), host, old.Format(time.RFC3339Nano)), true},
+		{"other host", fmt.Sprintf("PID: %d\nHost: %s-elsewhere\nCreated: %s\n", deadPID(t), host, time.Now().Format(time.RFC3339Nano)), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			lockPath := filepath.Join(dir, ".bundle", ".lock")
+			if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+				t.Fatalf("mkdir: %v", err)
+			}
+			if err := os.WriteFile(lockPath, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("write lock: %v", err)
+			}
+
+			l, err := AcquireLock(dir)
+			if !tc.stale {
+				if !errors.Is(err, utils.ErrBundleLocked) {
+					t.Fatalf("AcquireLock error = %v, want ErrBundleLocked", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("stale lock not reclaimed: %v", err)
+			}
+			defer l.Release()
+			data, _ := os.ReadFile(lockPath)
+			if info := parseLockInfo(data); info.PID != os.Getpid() || info.Created.IsZero() {
+				t.Errorf("lock info = %+v, want this process", info)
+			}
+		})
+	}
+}
+
+func TestSharedLock(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".bundle"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	// Readers coexist and keep writers out
+	r1, err := AcquireSharedLock(dir)
+	if err != nil {
+		t.Fatalf("AcquireSharedLock failed: %v", err)
+	}
+	r2, err := AcquireSharedLock(dir)
+	if err != nil {
+		t.Fatalf("second AcquireSharedLock failed: %v", err)
+	}
+	if _, err := AcquireLock(dir); !errors.Is(err, utils.ErrBundleLocked) {
+		t.Fatalf("AcquireLock with readers error = %v, want ErrBundleLocked", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".bundle", exclusiveName)); !os.IsNotExist(err) {
+		t.Errorf("failed AcquireLock left its lock file: %v", err)
+	}
+
+	// A waiting writer gets the lock once the readers are done
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		r1.Release()
+		r2.Release()
+	}()
+	w, err := AcquireLockWait(dir, 5*time.Second)
+	if err != nil {
+		t.Fatalf("AcquireLockWait failed: %v", err)
+	}
+
+	// The writer keeps readers out
+	if _, err := AcquireSharedLock(dir); !errors.Is(err, utils.ErrBundleLocked) {
+		t.Fatalf("AcquireSharedLock with writer error = %v, want ErrBundleLocked", err)
+	}
+	if err := w.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	r, err := AcquireSharedLockWait(dir, 0)
+	if err != nil {
+		t.Fatalf("AcquireSharedLockWait failed: %v", err)
+	}
+	r.Release()
+
+	// A reader that died does not block writers
+	stale := filepath.Join(dir, ".bundle", sharedPrefix+"dead")
+	if err := os.WriteFile(stale, []byte(fmt.Sprintf("PID: %d\n", deadPID(t))), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	w, err = AcquireLock(dir)
+	if err != nil {
+		t.Fatalf("AcquireLock with stale reader failed: %v", err)
+	}
+	w.Release()
+}
+
+func TestIsLockFile(t *testing.T) {
+	for rel, want := range map[string]bool{
+		".bundle/.lock":                true,
+		".bundle/.lock.shared.123456":  true,
+		"./.bundle/.lock":              true,
+		".bundle/META.json":            false,
+		".lock":                        false,
+		"photos/.bundle/.lock":         false,
+		".bundle/sub/.lock.shared.123": false,
+	} {
+		if got := IsLockFile(rel); got != want {
+			t.Errorf("IsLockFile(%q) = %v, want %v", rel, got, want)
+		}
+	}
+}
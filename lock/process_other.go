@@ -0,0 +1,10 @@
+//go:build !unix
+
+package lock
+
+// processAlive reports whether a process with pid exists. Without a
+// portable check every process is assumed alive, so locks are only
+// reclaimed by age.
+func processAlive(pid int) bool {
+	return true
+}
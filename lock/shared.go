@@ -0,0 +1,169 @@
+package lock
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// sharedPrefix starts the name of every shared lock file in .bundle/; each
+// reader creates its own file.
+const sharedPrefix = ".lock.shared."
+
+// Held reports whether the bundle at bundlePath is locked, exclusively or
+// by readers. Stale locks are reclaimed rather than reported.
+//
+// Example:
+//
+//	if lock.Held(stagingPath) {
+//	    return // an import is still writing it
+//	}
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//
+// Returns:
+//   - bool: true while another process holds a lock on the bundle
+func Held(bundlePath string) bool {
+	bundleDir := filepath.Join(bundlePath, ".bundle")
+	return exclusiveHeld(bundleDir) || hasReaders(bundleDir)
+}
+
+// IsLockFile reports whether rel, a path relative to a bundle directory,
+// names one of its lock files. Copies of a bundle leave these out: a lock
+// belongs to the process holding it on the original, and a copied one
+// would make the copy look locked.
+//
+// Example:
+//
+//	utils.CopyTree(src, dst, utils.CopyOptions{Skip: lock.IsLockFile})
+//
+// Parameters:
+//   - rel: slash- or OS-separated path relative to the bundle directory
+//
+// Returns:
+//   - bool: true for .bundle/.lock and .bundle/.lock.shared.*
+func IsLockFile(rel string) bool {
+	dir, name := filepath.Split(filepath.ToSlash(filepath.Clean(rel)))
+	return strings.TrimSuffix(dir, "/") == ".bundle" && strings.HasPrefix(name, exclusiveName)
+}
+
+// AcquireSharedLock attempts to acquire a shared lock on the bundle
+// (fail-fast).
+//
+// Any number of shared locks can be held at once, but none while the
+// exclusive lock is held, and AcquireLock fails while shared locks are
+// held. Use it for operations that only read the bundle metadata.
+//
+// Each reader creates its own .bundle/.lock.shared.* file and then checks
+// for the exclusive lock; a writer creates .bundle/.lock and then checks
+// for shared lock files. Whichever comes second sees the other, so a
+// reader and a writer never both proceed. Stale shared locks are reclaimed
+// like stale exclusive ones.
+//
+// Unlike AcquireLock it does not create .bundle/, so it fails with an
+// os.ErrNotExist error for a directory that is not a bundle.
+//
+// Example:
+//
+//	lock, err := lock.AcquireSharedLock("/path/to/bundle")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer lock.Release()
+//
+//	b, err := bundle.Load("/path/to/bundle")
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//
+// Returns:
+//   - *Lock: lock handle for Release()
+//   - error: utils.ErrBundleLocked if the exclusive lock is held, or I/O
+//     errors
+func AcquireSharedLock(bundlePath string) (*Lock, error) {
+	bundleDir := filepath.Join(bundlePath, ".bundle")
+	if exclusiveHeld(bundleDir) {
+		return nil, utils.ErrBundleLocked
+	}
+
+	lockFile, err := os.CreateTemp(bundleDir, sharedPrefix+"*")
+	if err != nil {
+		return nil, err
+	}
+	l := &Lock{lockPath: lockFile.Name(), lockFile: lockFile}
+	if err := writeLockInfo(lockFile, time.Now()); err != nil {
+		log.Debugf("failed to write lock info: %v", err)
+	}
+
+	// A writer may have taken the lock between the check and the create
+	if exclusiveHeld(bundleDir) {
+		l.Release()
+		return nil, utils.ErrBundleLocked
+	}
+	return l, nil
+}
+
+// AcquireSharedLockWait acquires a shared lock on the bundle, waiting for
+// a writer to release the exclusive lock.
+//
+// It retries AcquireSharedLock with exponential backoff until timeout has
+// passed. A zero or negative timeout makes it fail-fast.
+//
+// Example:
+//
+//	lock, err := lock.AcquireSharedLockWait("/path/to/bundle", lock.WaitTimeout)
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//   - timeout: how long to wait for the lock
+//
+// Returns:
+//   - *Lock: lock handle for Release()
+//   - error: utils.ErrBundleLocked if the exclusive lock is still held at
+//     the deadline, or I/O errors
+func AcquireSharedLockWait(bundlePath string, timeout time.Duration) (*Lock, error) {
+	var l *Lock
+	err := pollUntil(time.Now().Add(timeout), func() error {
+		var err error
+		l, err = AcquireSharedLock(bundlePath)
+		return err
+	})
+	if err != nil {
+		if timeout > 0 {
+			err = waitError(err, timeout)
+		}
+		return nil, err
+	}
+	return l, nil
+}
+
+// exclusiveHeld reports whether bundleDir holds a live exclusive lock.
+func exclusiveHeld(bundleDir string) bool {
+	lockPath := filepath.Join(bundleDir, exclusiveName)
+	if _, err := os.Stat(lockPath); os.IsNotExist(err) {
+		return false
+	}
+	return !reclaimStale(lockPath)
+}
+
+// hasReaders reports whether bundleDir holds live shared locks.
+func hasReaders(bundleDir string) bool {
+	entries, err := os.ReadDir(bundleDir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), sharedPrefix) {
+			continue
+		}
+		if !reclaimStale(filepath.Join(bundleDir, entry.Name())) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package lock
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// defaultMaxAge is the lock age after which a lock held by a live process
+// is considered stale when `lock_max_age` is not configured.
+const defaultMaxAge = 24 * time.Hour
+
+// MaxAge returns the age after which a lock is reclaimed even though its
+// PID belongs to a running process.
+//
+// PIDs are reused, so a live PID does not prove the lock holder is still
+// running; past this age it is assumed not to be. It reads the
+// `lock_max_age` configuration key (a duration such as "12h"), defaulting
+// to 24 hours when unset or not positive.
+//
+// Returns:
+//   - time.Duration: maximum lock age
+func MaxAge() time.Duration {
+	if d := viper.GetDuration("lock_max_age"); d > 0 {
+		return d
+	}
+	return defaultMaxAge
+}
+
+// lockInfo is the content of a lock file.
+type lockInfo struct {
+	PID     int
+	Host    string
+	Created time.Time
+}
+
+// writeLockInfo writes the lock file content for the current process.
+func writeLockInfo(f *os.File, now time.Time) error {
+	host, _ := os.Hostname()
+	_, err := fmt.Fprintf(f, "PID: %d\nHost: %s\nCreated: %s\n", os.Getpid(), host, now.UTC().Format(time.RFC3339Nano))
+	return err
+}
+
+// parseLockInfo parses lock file content. Unknown and malformed lines are
+// ignored, so lock files written by older versions, holding only the PID,
+// are read too.
+func parseLockInfo(data []byte) lockInfo {
+	var info lockInfo
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "PID":
+			info.PID, _ = strconv.Atoi(value)
+		case "Host":
+			info.Host = value
+		case "Created":
+			info.Created, _ = time.Parse(time.RFC3339Nano, value)
+		}
+	}
+	return info
+}
+
+// stale reports whether the lock described by info can be reclaimed.
+//
+// A lock is stale when its PID is not running on this host, or when it is
+// older than maxAge. Locks from other hosts, for example on shared
+// storage, are only reclaimed by age.
+func (info lockInfo) stale(now time.Time, maxAge time.Duration) bool {
+	host, _ := os.Hostname()
+	sameHost := info.Host == "" || info.Host == host
+	if sameHost && info.PID > 0 && !processAlive(info.PID) {
+		return true
+	}
+	return !info.Created.IsZero() && now.Sub(info.Created) > maxAge
+}
+
+// reclaimStale removes the lock file at lockPath when it is stale and
+// reports whether it did.
+//
+// The lock is renamed aside first, so that of several processes reclaiming
+// the same lock only one removes it. If the renamed file is not the one
+// found stale, another process took the lock in between and it is put
+// back.
+func reclaimStale(lockPath string) bool {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return false
+	}
+	info := parseLockInfo(data)
+	if info.Created.IsZero() {
+		// Crashed before writing the content; fall back to the file age
+		if fi, err := os.Stat(lockPath); err == nil {
+			info.Created = fi.ModTime()
+		}
+	}
+	if !info.stale(time.Now(), MaxAge()) {
+		return false
+	}
+
+	// Named so it does not look like a lock itself
+	aside := filepath.Join(filepath.Dir(lockPath), fmt.Sprintf(".stale%s.%d", filepath.Base(lockPath), os.Getpid()))
+	if err := os.Rename(lockPath, aside); err != nil {
+		return false
+	}
+	if current, err := os.ReadFile(aside); err != nil || !bytes.Equal(current, data) {
+		// Link fails if yet another lock was created meanwhile
+		if err := os.Link(aside, lockPath); err != nil {
+			log.Warnf("failed to restore lock %s: %v", lockPath, err)
+		}
+		os.Remove(aside)
+		return false
+	}
+	os.Remove(aside)
+	log.Warnf("Reclaimed stale lock %s (PID %d, created %s)", lockPath, info.PID, info.Created.Format(time.RFC3339))
+	return true
+}
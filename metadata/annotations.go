@@ -0,0 +1,105 @@
+package metadata
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var annotationKeyPattern = regexp.MustCompile(`^[A-Za-z0-9.-]{1,64}$`)
+
+// ValidateAnnotationKey checks that key can be used as an annotation key.
+//
+// Keys are 1-64 characters: letters, digits, dots and hyphens, e.g.
+// "project-code" or "retention.class". Keys are case-sensitive.
+//
+// Parameters:
+//   - key: the annotation key
+//
+// Returns:
+//   - error: if key is empty or contains other characters
+func ValidateAnnotationKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("annotation key cannot be empty")
+	}
+	if !annotationKeyPattern.MatchString(key) {
+		return fmt.Errorf("invalid annotation key '%s': must be 1-64 letters, digits, dots or hyphens", key)
+	}
+	return nil
+}
+
+// SetAnnotation sets the annotation key to value and saves the metadata.
+//
+// Annotations are free-form key/value pairs for data the metadata has no
+// field for, such as a project code or retention class. Setting an
+// existing key replaces its value.
+//
+// Example:
+//
+//	err := metadata.SetAnnotation("/path/to/bundle", "retention-class", "7y")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//   - key: annotation key (see ValidateAnnotationKey)
+//   - value: annotation value, may be empty
+//
+// Returns:
+//   - error: if key is invalid or metadata cannot be loaded or saved
+func SetAnnotation(bundlePath string, key string, value string) error {
+	if err := ValidateAnnotationKey(key); err != nil {
+		return err
+	}
+
+	meta, err := Load(bundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata: %w", err)
+	}
+
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[key] = value
+
+	if err := meta.Save(bundlePath); err != nil {
+		return fmt.Errorf("failed to save metadata: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveAnnotation removes the annotation key and saves the metadata.
+//
+// Removing a key that is not set is not an error.
+//
+// Example:
+//
+//	err := metadata.RemoveAnnotation("/path/to/bundle", "retention-class")
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//   - key: annotation key
+//
+// Returns:
+//   - error: if metadata cannot be loaded or saved
+func RemoveAnnotation(bundlePath string, key string) error {
+	meta, err := Load(bundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata: %w", err)
+	}
+
+	if _, ok := meta.Annotations[key]; !ok {
+		return nil
+	}
+	delete(meta.Annotations, key)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+
+	if err := meta.Save(bundlePath); err != nil {
+		return fmt.Errorf("failed to save metadata: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnnotations(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".bundle"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	meta := &Metadata{Title: "Annotated", Author: "tester", Version: 1, CreatedAt: time.Now(), BundleChecksum: strings.Repeat("a", 64)}
+	if err := meta.Save(dir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if err := SetAnnotation(dir, "project", "apollo"); err != nil {
+		t.Fatalf("SetAnnotation failed: %v", err)
+	}
+	if err := SetAnnotation(dir, "retention-class", "7y"); err != nil {
+		t.Fatalf("SetAnnotation failed: %v", err)
+	}
+	for _, key := range []string{"", "bad key", "under_score", strings.Repeat("k", 65)} {
+		if err := SetAnnotation(dir, key, "x"); err == nil {
+			t.Errorf("SetAnnotation(%q) succeeded, want error", key)
+		}
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Annotations) != 2 || loaded.Annotations["project"] != "apollo" || loaded.Annotations["retention-class"] != "7y" {
+		t.Errorf("Annotations = %v", loaded.Annotations)
+	}
+	if err := loaded.Validate(); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+
+	if err := RemoveAnnotation(dir, "project"); err != nil {
+		t.Fatalf("RemoveAnnotation failed: %v", err)
+	}
+	if err := RemoveAnnotation(dir, "not-set"); err != nil {
+		t.Errorf("RemoveAnnotation of a missing key failed: %v", err)
+	}
+	if err := RemoveAnnotation(dir, "retention-class"); err != nil {
+		t.Fatalf("RemoveAnnotation failed: %v", err)
+	}
+	raw, err := os.ReadFile(metaPath(dir))
+	if err != nil {
+		t.Fatalf("read META.json: %v", err)
+	}
+	if strings.Contains(string(raw), "annotations") {
+		t.Errorf("META.json = %s, want no annotations field", raw)
+	}
+
+	loaded.Annotations = map[string]string{"bad key": "x"}
+	if err := loaded.Validate(); err == nil {
+		t.Error("Validate accepted an invalid annotation key")
+	}
+}
@@ -0,0 +1,136 @@
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// cacheEntry is a parsed META.json together with the file attributes it
+// was read with.
+type cacheEntry struct {
+	modTime time.Time
+	size    int64
+	meta    Metadata
+}
+
+// cache holds parsed metadata by bundle path while caching is enabled.
+// A nil entries map means caching is disabled.
+var cache struct {
+	sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// EnableCache makes Load keep parsed metadata in memory for the rest of
+// the process.
+//
+// Entries are keyed by bundle path and reused only while the modification
+// time and size of META.json are unchanged, so edits by other processes
+// are picked up. Caching is off by default because long-lived processes
+// would otherwise keep every bundle they ever saw; CLI commands that scan
+// the same bundles in several passes turn it on for their own run.
+//
+// Example:
+//
+//	metadata.EnableCache()
+//	defer metadata.DisableCache()
+//	stats, err := p.Stats()
+func EnableCache() {
+	cache.Lock()
+	defer cache.Unlock()
+	if cache.entries == nil {
+		cache.entries = map[string]cacheEntry{}
+	}
+}
+
+// DisableCache turns caching off and drops all cached metadata.
+func DisableCache() {
+	cache.Lock()
+	defer cache.Unlock()
+	cache.entries = nil
+}
+
+// metaPath returns the path of META.json in the bundle at bundlePath.
+func metaPath(bundlePath string) string {
+	return filepath.Join(bundlePath, ".bundle", "META.json")
+}
+
+// cacheKey returns the cache key for bundlePath, so "./b" and "b" share an
+// entry. It falls back to the cleaned path when it cannot be made absolute.
+func cacheKey(bundlePath string) string {
+	if abs, err := filepath.Abs(bundlePath); err == nil {
+		return abs
+	}
+	return filepath.Clean(bundlePath)
+}
+
+// cachedLoad returns a copy of the cached metadata for bundlePath when
+// caching is enabled and META.json has not changed since it was cached.
+// The FileInfo of META.json is returned for storing a fresh entry; it is
+// nil when caching is disabled or the file cannot be stat'ed.
+func cachedLoad(bundlePath string) (*Metadata, os.FileInfo) {
+	cache.Lock()
+	defer cache.Unlock()
+	if cache.entries == nil {
+		return nil, nil
+	}
+
+	info, err := os.Stat(metaPath(bundlePath))
+	if err != nil {
+		return nil, nil
+	}
+
+	entry, ok := cache.entries[cacheKey(bundlePath)]
+	if !ok || !entry.modTime.Equal(info.ModTime()) || entry.size != info.Size() {
+		return nil, info
+	}
+	return entry.meta.clone(), info
+}
+
+// storeCache caches a copy of meta, read from a META.json described by info.
+func storeCache(bundlePath string, info os.FileInfo, meta *Metadata) {
+	cache.Lock()
+	defer cache.Unlock()
+	if cache.entries == nil || info == nil {
+		return
+	}
+	cache.entries[cacheKey(bundlePath)] = cacheEntry{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		meta:    *meta.clone(),
+	}
+}
+
+// clone returns a copy of m that shares no pointers, slices or maps with
+// it, so callers can modify either freely.
+func (m *Metadata) clone() *Metadata {
+	c := *m
+	if m.ModifiedAfter != nil {
+		t := *m.ModifiedAfter
+		c.ModifiedAfter = &t
+	}
+	if m.ModifiedBefore != nil {
+		t := *m.ModifiedBefore
+		c.ModifiedBefore = &t
+	}
+	if m.Exclude != nil {
+		c.Exclude = append([]string(nil), m.Exclude...)
+	}
+	if m.Annotations != nil {
+		c.Annotations = make(map[string]string, len(m.Annotations))
+		for k, v := range m.Annotations {
+			c.Annotations[k] = v
+		}
+	}
+	return &c
+}
+
+// invalidateCache drops the cached metadata for bundlePath.
+func invalidateCache(bundlePath string) {
+	cache.Lock()
+	defer cache.Unlock()
+	if cache.entries != nil {
+		delete(cache.entries, cacheKey(bundlePath))
+	}
+}
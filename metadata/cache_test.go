@@ -0,0 +1,116 @@
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoad_Cache(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".bundle"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	meta := &Metadata{Title: "First", Author: "tester", Version: 1}
+	if err := meta.Save(dir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	EnableCache()
+	defer DisableCache()
+
+	first, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	first.Title = "Modified in memory"
+
+	second, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if second.Title != "First" {
+		t.Errorf("cached Title = %q, want a copy unaffected by callers", second.Title)
+	}
+
+	// An external edit with a new mtime must not be served from the cache
+	data := []byte(`{"title": "Second", "author": "tester", "version": 1}`)
+	if err := os.WriteFile(metaPath(dir), data, 0644); err != nil {
+		t.Fatalf("write META.json: %v", err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(metaPath(dir), later, later); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	third, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if third.Title != "Second" {
+		t.Errorf("Title after edit = %q, want %q", third.Title, "Second")
+	}
+
+	// Save drops the entry even when the mtime does not change
+	third.Title = "Third"
+	if err := third.Save(dir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := os.Chtimes(metaPath(dir), later, later); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	fourth, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if fourth.Title != "Third" {
+		t.Errorf("Title after Save = %q, want %q", fourth.Title, "Third")
+	}
+}
+
+func TestLoad_CacheDeepCopy(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".bundle"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	meta := &Metadata{
+		Title:          "Photos",
+		Author:         "tester",
+		Version:        1,
+		ModifiedAfter:  &after,
+		ModifiedBefore: &before,
+		Exclude:        []string{"*.tmp"},
+		Annotations:    map[string]string{"project": "alpha"},
+	}
+	if err := meta.Save(dir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	EnableCache()
+	defer DisableCache()
+
+	first, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	*first.ModifiedAfter = first.ModifiedAfter.AddDate(1, 0, 0)
+	*first.ModifiedBefore = first.ModifiedBefore.AddDate(1, 0, 0)
+	first.Exclude[0] = "*.bak"
+	first.Annotations["project"] = "beta"
+
+	second, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !second.ModifiedAfter.Equal(after) || !second.ModifiedBefore.Equal(before) {
+		t.Errorf("cached window = %v..%v, want %v..%v", second.ModifiedAfter, second.ModifiedBefore, after, before)
+	}
+	if second.Exclude[0] != "*.tmp" {
+		t.Errorf("cached Exclude = %v, want [*.tmp]", second.Exclude)
+	}
+	if second.Annotations["project"] != "alpha" {
+		t.Errorf("cached Annotations = %v, want project=alpha", second.Annotations)
+	}
+}
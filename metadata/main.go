@@ -29,7 +29,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
 )
 
@@ -48,6 +47,10 @@ import (
 //	fmt.Printf("Author: %s\n", meta.Author)
 //	fmt.Printf("Created: %s\n", meta.CreatedAt.Format(time.RFC3339))
 //
+// When EnableCache has been called, an unchanged META.json is returned from
+// memory instead of being read and parsed again. Every call returns its own
+// copy, so callers may modify the result.
+//
 // Parameters:
 //   - bundlePath: absolute or relative path to the bundle directory
 //
@@ -55,8 +58,12 @@ import (
 //   - *Metadata: parsed metadata
 //   - error: if file cannot be read or JSON is invalid
 func Load(bundlePath string) (*Metadata, error) {
-	metaFile := filepath.Join(bundlePath, ".bundle", "META.json")
-	data, err := os.ReadFile(metaFile)
+	cached, info := cachedLoad(bundlePath)
+	if cached != nil {
+		return cached, nil
+	}
+
+	data, err := os.ReadFile(metaPath(bundlePath))
 	if err != nil {
 		return nil, err
 	}
@@ -66,6 +73,7 @@ func Load(bundlePath string) (*Metadata, error) {
 		return nil, err
 	}
 
+	storeCache(bundlePath, info, &meta)
 	return &meta, nil
 }
 
@@ -94,23 +102,25 @@ func Load(bundlePath string) (*Metadata, error) {
 // Returns:
 //   - error: if file cannot be created, written, or JSON cannot be serialized
 func (m *Metadata) Save(bundlePath string) error {
-	metaFile := filepath.Join(bundlePath, ".bundle", "META.json")
-
 	data, err := json.MarshalIndent(m, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(metaFile, data, 0644)
+	invalidateCache(bundlePath)
+	return os.WriteFile(metaPath(bundlePath), data, 0644)
 }
 
 // Validate checks metadata fields against validation rules.
 //
 // It validates:
-//   - BundleChecksum is exactly 64 lowercase hex characters
+//   - HashAlgorithm is empty, "sha256" or "sha512"
+//   - BundleChecksum is exactly 64 lowercase hex characters (128 when
+//     HashAlgorithm is "sha512")
 //   - Version is >= 1
 //   - Author is not empty
 //   - CreatedAt is not zero
+//   - Annotation keys are valid (see ValidateAnnotationKey)
 //
 // Example:
 //
@@ -126,16 +136,26 @@ func (m *Metadata) Save(bundlePath string) error {
 //	}
 //
 // Returns:
-//   - error: describing the first validation failure, or nil if valid
+//   - error: describing the first validation failure, starting with the
+//     JSON name of the field, or nil if valid
 func (m *Metadata) Validate() error {
-	// Check bundle checksum format (64 hex characters)
-	if len(m.BundleChecksum) != 64 {
-		return fmt.Errorf("invalid bundle checksum length: %d, want 64", len(m.BundleChecksum))
+	// Check bundle checksum format (64 hex characters, 128 for sha512)
+	var want int
+	switch m.HashAlgorithm {
+	case "", "sha256":
+		want = 64
+	case "sha512":
+		want = 128
+	default:
+		return fmt.Errorf("invalid hash_algorithm: %q is not supported (expected sha256 or sha512)", m.HashAlgorithm)
+	}
+	if len(m.BundleChecksum) != want {
+		return fmt.Errorf("invalid bundle_checksum: length %d, want %d", len(m.BundleChecksum), want)
 	}
 
-	hexPattern := regexp.MustCompile("^[a-f0-9]{64}$")
+	hexPattern := regexp.MustCompile("^[a-f0-9]+$")
 	if !hexPattern.MatchString(m.BundleChecksum) {
-		return fmt.Errorf("invalid bundle checksum format: must be 64 hex characters")
+		return fmt.Errorf("invalid bundle_checksum: must be %d lowercase hex characters", want)
 	}
 
 	// Check version
@@ -145,11 +165,17 @@ func (m *Metadata) Validate() error {
 
 	// Check required fields
 	if m.Author == "" {
-		return fmt.Errorf("author cannot be empty")
+		return fmt.Errorf("invalid author: cannot be empty")
 	}
 
 	if m.CreatedAt.IsZero() {
-		return fmt.Errorf("created_at cannot be zero")
+		return fmt.Errorf("invalid created_at: cannot be zero")
+	}
+
+	for key := range m.Annotations {
+		if err := ValidateAnnotationKey(key); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -190,3 +216,36 @@ func UpdateTitle(bundlePath string, newTitle string) error {
 
 	return nil
 }
+
+// UpdateDescription updates the description field and saves the metadata.
+//
+// Like UpdateTitle it loads, updates and saves META.json in one operation.
+// An empty description removes the field.
+//
+// Example:
+//
+//	err := metadata.UpdateDescription("/path/to/bundle", "Scanned from the 1998 family albums")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//   - newDescription: new description to set
+//
+// Returns:
+//   - error: if metadata cannot be loaded or saved
+func UpdateDescription(bundlePath string, newDescription string) error {
+	meta, err := Load(bundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata: %w", err)
+	}
+
+	meta.Description = newDescription
+
+	if err := meta.Save(bundlePath); err != nil {
+		return fmt.Errorf("failed to save metadata: %w", err)
+	}
+
+	return nil
+}
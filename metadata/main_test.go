@@ -0,0 +1,100 @@
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpdateDescription(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".bundle"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	// META.json from before the field existed
+	data := []byte(`{"title": "Old", "created_at": "2024-01-15T10:30:00Z", "author": "tester", "version": 1, "bundle_checksum": "` + strings.Repeat("a", 64) + `"}`)
+	if err := os.WriteFile(metaPath(dir), data, 0644); err != nil {
+		t.Fatalf("write META.json: %v", err)
+	}
+	meta, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if meta.Description != "" {
+		t.Errorf("Description = %q, want empty", meta.Description)
+	}
+	if err := meta.Validate(); err != nil {
+		t.Errorf("Validate without description failed: %v", err)
+	}
+
+	if err := UpdateDescription(dir, "Scanned from the 1998 albums"); err != nil {
+		t.Fatalf("UpdateDescription failed: %v", err)
+	}
+	meta, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if meta.Description != "Scanned from the 1998 albums" || meta.Title != "Old" {
+		t.Errorf("after update: title %q, description %q", meta.Title, meta.Description)
+	}
+	if !meta.CreatedAt.Equal(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("CreatedAt changed to %v", meta.CreatedAt)
+	}
+
+	// Clearing the description drops the field
+	if err := UpdateDescription(dir, ""); err != nil {
+		t.Fatalf("UpdateDescription failed: %v", err)
+	}
+	raw, err := os.ReadFile(metaPath(dir))
+	if err != nil {
+		t.Fatalf("read META.json: %v", err)
+	}
+	if strings.Contains(string(raw), "description") {
+		t.Errorf("META.json = %s, want no description field", raw)
+	}
+}
+
+func TestHashAlgorithm(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".bundle"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	// sha512 bundles have 128 character checksums
+	data := []byte(`{"title": "Old", "created_at": "2024-01-15T10:30:00Z", "author": "tester", "version": 1, "hash_algorithm": "sha512", "bundle_checksum": "` + strings.Repeat("a", 128) + `"}`)
+	if err := os.WriteFile(metaPath(dir), data, 0644); err != nil {
+		t.Fatalf("write META.json: %v", err)
+	}
+	meta, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if meta.HashAlgorithm != "sha512" {
+		t.Errorf("HashAlgorithm = %q, want sha512", meta.HashAlgorithm)
+	}
+	if err := meta.Validate(); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+
+	// The field is written even for the default algorithm
+	meta.HashAlgorithm = "sha256"
+	meta.BundleChecksum = strings.Repeat("a", 64)
+	if err := meta.Save(dir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	raw, err := os.ReadFile(metaPath(dir))
+	if err != nil {
+		t.Fatalf("read META.json: %v", err)
+	}
+	if !strings.Contains(string(raw), `"hash_algorithm": "sha256"`) {
+		t.Errorf("META.json = %s, want hash_algorithm sha256", raw)
+	}
+
+	meta.HashAlgorithm = "blake2b"
+	if err := meta.Validate(); err == nil || !strings.Contains(err.Error(), "hash_algorithm") {
+		t.Errorf("Validate(blake2b) error = %v, want hash_algorithm not supported", err)
+	}
+}
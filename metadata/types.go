@@ -30,14 +30,25 @@ import "time"
 // Metadata represents the bundle metadata stored in .bundle/META.json.
 //
 // It contains immutable information about the bundle that is set at creation
-// time and should not be modified (except Title which can be updated).
+// time and should not be modified (except Title and Description which can
+// be updated).
 //
 // Fields:
 //   - Title: human-readable name for the bundle (mutable)
+//   - Description: optional free-text notes, e.g. provenance (mutable)
 //   - CreatedAt: ISO 8601 timestamp of bundle creation
 //   - BundleChecksum: SHA256 of sorted file checksums (64 hex chars)
 //   - Author: system username that created the bundle
 //   - Version: metadata schema version (currently 1)
+//   - ManifestFormat: checksum manifest format ("text" or "json"); empty
+//     for bundles created before the format was recorded
+//   - HashAlgorithm: hash algorithm of all checksums ("sha256" or
+//     "sha512"), always written; empty for bundles created before the
+//     algorithm was recorded, which are always sha256
+//   - ModifiedAfter, ModifiedBefore: modification time window the files
+//     were selected with at creation; nil when unbounded
+//   - Exclude: gitignore-style patterns of the paths left out at creation
+//   - Annotations: free-form key/value pairs, e.g. a project code (mutable)
 //
 // Example JSON:
 //
@@ -46,12 +57,36 @@ import "time"
 //	  "created_at": "2024-01-15T10:30:00Z",
 //	  "bundle_checksum": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
 //	  "author": "username",
-//	  "version": 1
+//	  "version": 1,
+//	  "manifest_format": "text",
+//	  "hash_algorithm": "sha256"
 //	}
 type Metadata struct {
-	Title          string    `json:"title"`           // Human-readable name
-	CreatedAt      time.Time `json:"created_at"`      // ISO 8601 timestamp
-	BundleChecksum string    `json:"bundle_checksum"` // SHA256 of sorted file checksums
-	Author         string    `json:"author"`          // System username
-	Version        int       `json:"version"`         // Metadata version (starts at 1)
+	Title          string            `json:"title"`                     // Human-readable name
+	Description    string            `json:"description,omitempty"`     // Free-text notes
+	CreatedAt      time.Time         `json:"created_at"`                // ISO 8601 timestamp
+	BundleChecksum string            `json:"bundle_checksum"`           // SHA256 of sorted file checksums
+	Author         string            `json:"author"`                    // System username
+	Version        int               `json:"version"`                   // Metadata version (starts at 1)
+	ManifestFormat string            `json:"manifest_format,omitempty"` // Checksum manifest format
+	HashAlgorithm  string            `json:"hash_algorithm"`            // Hash algorithm of all checksums
+	ModifiedAfter  *time.Time        `json:"modified_after,omitempty"`  // Files modified at or after
+	ModifiedBefore *time.Time        `json:"modified_before,omitempty"` // Files modified before
+	Exclude        []string          `json:"exclude,omitempty"`         // Paths left out of the bundle
+	Annotations    map[string]string `json:"annotations,omitempty"`     // Custom key/value pairs
+}
+
+// ModTimeFilter returns the modification time window recorded in m.
+//
+// Returns:
+//   - after: lower bound (inclusive), zero when unbounded
+//   - before: upper bound (exclusive), zero when unbounded
+func (m *Metadata) ModTimeFilter() (after, before time.Time) {
+	if m.ModifiedAfter != nil {
+		after = *m.ModifiedAfter
+	}
+	if m.ModifiedBefore != nil {
+		before = *m.ModifiedBefore
+	}
+	return after, before
 }
@@ -0,0 +1,108 @@
+package pool
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// DiskUsage returns the total size of the files of a bundle in the pool,
+// including its .bundle/ metadata.
+//
+// Parameters:
+//   - checksum: full bundle checksum
+//
+// Returns:
+//   - int64: size in bytes
+//   - error: utils.ErrBundleNotFound if the bundle is not in the pool, or
+//     I/O errors
+func (p *Pool) DiskUsage(checksum string) (int64, error) {
+	bundlePath, err := p.existingBundlePath(checksum)
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	err = filepath.Walk(bundlePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// Delete removes a bundle from the pool.
+//
+// The bundle directory is first renamed to a partial import, which pool
+// listings skip, so an interrupted delete never leaves a half-removed
+// bundle that looks complete. On sharded pools, shard directories left
+// empty are removed as well.
+//
+// Example:
+//
+//	pool, _ := pool.GetPool("default")
+//	sum, _ := pool.Resolve("a1b2c3")
+//	err := pool.Delete(sum)
+//
+// Parameters:
+//   - checksum: full bundle checksum; use Resolve for prefixes
+//
+// Returns:
+//   - error: utils.ErrBundleNotFound if the bundle is not in the pool, or
+//     I/O errors
+func (p *Pool) Delete(checksum string) error {
+	bundlePath, err := p.existingBundlePath(checksum)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Deleting bundle %s from pool %s", checksum, p.Root)
+
+	stagingPath := bundlePath + partialSuffix
+	if err := os.RemoveAll(stagingPath); err != nil {
+		return fmt.Errorf("failed to remove stale partial import: %w", err)
+	}
+	if err := os.Rename(bundlePath, stagingPath); err != nil {
+		return fmt.Errorf("failed to delete bundle: %w", err)
+	}
+	if err := os.RemoveAll(stagingPath); err != nil {
+		return fmt.Errorf("failed to delete bundle: %w", err)
+	}
+
+	p.removeEmptyShards(bundlePath)
+	return nil
+}
+
+// removeEmptyShards removes the shard directories above the removed bundle
+// directory bundlePath that are now empty.
+func (p *Pool) removeEmptyShards(bundlePath string) {
+	// os.Remove fails on non-empty directories
+	for dir := filepath.Dir(bundlePath); dir != filepath.Clean(p.Root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+}
+
+// existingBundlePath returns the directory of the bundle with checksum,
+// or utils.ErrBundleNotFound when there is none.
+func (p *Pool) existingBundlePath(checksum string) (string, error) {
+	if !isBundleChecksum(checksum) {
+		return "", fmt.Errorf("%w: '%s' is not a bundle checksum", utils.ErrInvalidPath, checksum)
+	}
+	bundlePath := p.GetBundlePath(checksum)
+	info, err := os.Stat(bundlePath)
+	if os.IsNotExist(err) || (err == nil && !info.IsDir()) {
+		return "", fmt.Errorf("%w: %s", utils.ErrBundleNotFound, checksum)
+	}
+	if err != nil {
+		return "", err
+	}
+	return bundlePath, nil
+}
@@ -0,0 +1,90 @@
+package pool
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+// importBundle creates a bundle with one file of content and imports it
+// into p, returning its checksum.
+func importBundle(t *testing.T, p *Pool, content string) string {
+	t.Helper()
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "a.txt"), []byte(content), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	b, err := bundle.Create(src, content)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := p.Import(src, false); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	return b.Metadata.BundleChecksum
+}
+
+func TestPool_ResolveDelete(t *testing.T) {
+	for _, layout := range []Layout{LayoutFlat, LayoutSharded} {
+		t.Run(string(layout), func(t *testing.T) {
+			p := &Pool{Root: t.TempDir(), Title: "test", Layout: layout}
+			sum := importBundle(t, p, "hello")
+			other := importBundle(t, p, "world")
+
+			got, err := p.Resolve(sum[:8])
+			if err != nil || got != sum {
+				t.Fatalf("Resolve(%s) = %q, %v; want %s", sum[:8], got, err, sum)
+			}
+			if _, err := p.Resolve("0123456789abcdef"); !errors.Is(err, utils.ErrBundleNotFound) {
+				t.Errorf("Resolve unknown error = %v, want ErrBundleNotFound", err)
+			}
+			if _, err := p.Resolve("../x"); !errors.Is(err, utils.ErrInvalidPath) {
+				t.Errorf("Resolve invalid error = %v, want ErrInvalidPath", err)
+			}
+
+			size, err := p.DiskUsage(sum)
+			if err != nil || size < int64(len("hello")) {
+				t.Errorf("DiskUsage = %d, %v; want at least the file size", size, err)
+			}
+
+			if err := p.Delete(sum); err != nil {
+				t.Fatalf("Delete failed: %v", err)
+			}
+			if _, err := os.Stat(p.GetBundlePath(sum)); !os.IsNotExist(err) {
+				t.Errorf("bundle still present after Delete: %v", err)
+			}
+			if layout == LayoutSharded && sum[:2] != other[:2] {
+				if _, err := os.Stat(filepath.Join(p.Root, sum[:2])); !os.IsNotExist(err) {
+					t.Errorf("empty shard directory left behind: %v", err)
+				}
+			}
+			if err := p.Delete(sum); !errors.Is(err, utils.ErrBundleNotFound) {
+				t.Errorf("second Delete error = %v, want ErrBundleNotFound", err)
+			}
+
+			bundles, err := p.ListBundles()
+			if err != nil || len(bundles) != 1 || bundles[0].BundleChecksum != other {
+				t.Errorf("ListBundles after Delete = %v, %v; want only %s", bundles, err, other)
+			}
+		})
+	}
+}
+
+func TestPool_ResolveAmbiguous(t *testing.T) {
+	p := &Pool{Root: t.TempDir()}
+	for _, name := range []string{
+		"ab" + "00000000000000000000000000000000000000000000000000000000000000",
+		"ab" + "11111111111111111111111111111111111111111111111111111111111111",
+	} {
+		if err := os.MkdirAll(filepath.Join(p.Root, name), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	if _, err := p.Resolve("ab"); !errors.Is(err, utils.ErrAmbiguousPrefix) {
+		t.Errorf("Resolve error = %v, want ErrAmbiguousPrefix", err)
+	}
+}
@@ -0,0 +1,40 @@
+package pool
+
+import (
+	"github.com/jvzantvoort/bundle/bundle"
+)
+
+// Export copies a bundle out of the pool to a working directory and
+// verifies the copy.
+//
+// destDir must not exist or be an empty directory. The bundle is copied
+// with bundle.Clone, so it keeps its checksum and metadata and is verified
+// at destDir, which records the result in its STATE.json. When the copy or
+// the verification fails, the partial copy is removed again; an existing
+// empty destDir is kept, empty.
+//
+// Example:
+//
+//	pool, _ := pool.GetPool("default")
+//	sum, _ := pool.Resolve("e3b0c44298fc")
+//	if err := pool.Export(sum, "/work/photos"); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - checksum: full bundle checksum; use Resolve for prefixes
+//   - destDir: directory to copy the bundle to
+//
+// Returns:
+//   - error: utils.ErrBundleNotFound if the bundle is not in the pool,
+//     utils.ErrInvalidPath if destDir is not empty,
+//     utils.ErrCorruptedBundle if the copy does not verify, or I/O errors
+func (p *Pool) Export(checksum, destDir string) error {
+	srcPath, err := p.existingBundlePath(checksum)
+	if err != nil {
+		return err
+	}
+
+	_, err = bundle.Clone(srcPath, destDir)
+	return err
+}
@@ -0,0 +1,47 @@
+package pool
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+func TestPool_Export(t *testing.T) {
+	p := &Pool{Root: t.TempDir(), Title: "test", Layout: LayoutSharded}
+	sum := importBundle(t, p, "hello")
+
+	dest := filepath.Join(t.TempDir(), "work")
+	if err := p.Export(sum, dest); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dest, "a.txt")); err != nil || string(data) != "hello" {
+		t.Errorf("exported file = %q, %v; want hello", data, err)
+	}
+
+	// A non-empty destination is refused
+	if err := p.Export(sum, dest); !errors.Is(err, utils.ErrInvalidPath) {
+		t.Errorf("non-empty destination error = %v, want ErrInvalidPath", err)
+	}
+
+	// A corrupted bundle is not left behind
+	if err := os.WriteFile(filepath.Join(p.GetBundlePath(sum), "a.txt"), []byte("HELLO"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	empty := t.TempDir()
+	if err := p.Export(sum, empty); !errors.Is(err, utils.ErrCorruptedBundle) {
+		t.Fatalf("corrupted export error = %v, want ErrCorruptedBundle", err)
+	}
+	if entries, err := os.ReadDir(empty); err != nil || len(entries) != 0 {
+		t.Errorf("partial copy left in existing destination: %v, %v", entries, err)
+	}
+	missing := filepath.Join(t.TempDir(), "new")
+	if err := p.Export(sum, missing); !errors.Is(err, utils.ErrCorruptedBundle) {
+		t.Fatalf("corrupted export error = %v, want ErrCorruptedBundle", err)
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Errorf("partial copy left behind: %v", err)
+	}
+}
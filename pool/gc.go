@@ -0,0 +1,203 @@
+package pool
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jvzantvoort/bundle/lock"
+	"github.com/jvzantvoort/bundle/metadata"
+	log "github.com/sirupsen/logrus"
+)
+
+// GCOptions holds the optional settings for GCWithOptions.
+//
+// Fields:
+//   - DryRun: report the directories that would be removed, remove nothing
+//   - Confirm: called with the garbage found before anything is removed;
+//     returning false removes nothing. Not called for a dry run or when
+//     there is no garbage.
+type GCOptions struct {
+	DryRun  bool
+	Confirm func(garbage []string) bool
+}
+
+// partialMinAge is how long a partial import must have been left
+// untouched before GC removes it, so an import that is between two files,
+// or has just released its lock, is not mistaken for an interrupted one.
+const partialMinAge = time.Hour
+
+// GC removes incomplete bundle directories from the pool.
+//
+// It is GCWithOptions with the default options.
+//
+// Example:
+//
+//	pool, _ := pool.GetPool("default")
+//	removed, err := pool.GC()
+//	for _, dir := range removed {
+//	    fmt.Printf("removed %s\n", dir)
+//	}
+//
+// Returns:
+//   - []string: the removed directories
+//   - error: if the pool cannot be scanned or a directory cannot be removed
+func (p *Pool) GC() ([]string, error) {
+	return p.GCWithOptions(GCOptions{})
+}
+
+// GCWithOptions removes incomplete bundle directories from the pool.
+//
+// Only directories named like a pool entry are considered: a bundle
+// checksum, or a checksum with the .partial suffix of an import in
+// progress. Anything else below the pool root, such as lost+found, is
+// left alone.
+//
+// A directory is garbage when it is a partial import left by an
+// interrupted Import or Delete, has no readable META.json, or its contents
+// do not recompute to the checksum it is stored under. The last check
+// hashes every file, so a bundle whose files were damaged after import is
+// removed as well; run with opts.DryRun first to see what would go.
+// Partial imports that are locked or were modified within the last hour
+// belong to a running import and are kept. Bundles whose META.json names
+// another checksum than their directory are reported and kept, as are
+// bundles that cannot be checked, for example because they are locked.
+// Each directory is locked while it is removed.
+//
+// Example:
+//
+//	pool, _ := pool.GetPool("default")
+//	garbage, err := pool.GCWithOptions(pool.GCOptions{DryRun: true})
+//
+// Parameters:
+//   - opts: GC options
+//
+// Returns:
+//   - []string: the removed directories, or with DryRun those that would
+//     be removed
+//   - error: if the pool cannot be scanned or a directory cannot be removed
+func (p *Pool) GCWithOptions(opts GCOptions) ([]string, error) {
+	var garbage []string
+
+	if _, err := os.Stat(p.Root); os.IsNotExist(err) {
+		return garbage, nil
+	}
+	dirs, err := p.Layout.entryDirs(p.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pool directory: %w", err)
+	}
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pool directory: %w", err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			reason, err := gcReason(path, entry.Name())
+			if err != nil {
+				log.Warnf("Keeping %s: %v", path, err)
+				continue
+			}
+			if reason == "" {
+				continue
+			}
+			log.Debugf("Garbage %s: %s", path, reason)
+			garbage = append(garbage, path)
+		}
+	}
+
+	if opts.DryRun || len(garbage) == 0 {
+		return garbage, nil
+	}
+	if opts.Confirm != nil && !opts.Confirm(garbage) {
+		return nil, nil
+	}
+
+	var removed []string
+	for _, path := range garbage {
+		l, err := lock.AcquireLock(path)
+		if err != nil {
+			log.Warnf("Keeping %s: %v", path, err)
+			continue
+		}
+		err = os.RemoveAll(path)
+		l.Release()
+		if err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		p.removeEmptyShards(path)
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
+
+// gcReason returns why the pool directory at path, named name, is garbage,
+// or "" when it is not. An error means the directory could not be checked
+// and is kept.
+func gcReason(path, name string) (string, error) {
+	if sum, ok := strings.CutSuffix(name, partialSuffix); ok {
+		if !isBundleChecksum(sum) {
+			log.Debugf("Ignoring %s: not a pool entry", path)
+			return "", nil
+		}
+		if lock.Held(path) {
+			return "", fmt.Errorf("partial import is locked")
+		}
+		modified, err := lastModified(path)
+		if err != nil {
+			return "", err
+		}
+		if time.Since(modified) < partialMinAge {
+			return "", fmt.Errorf("partial import was modified at %s", modified.Format(time.RFC3339))
+		}
+		return "partial import", nil
+	}
+	if !isBundleChecksum(name) {
+		log.Debugf("Ignoring %s: not a pool entry", path)
+		return "", nil
+	}
+
+	meta, err := metadata.Load(path)
+	if err != nil {
+		return "missing or unreadable META.json", nil
+	}
+	if meta.BundleChecksum != name {
+		return "", fmt.Errorf("META.json checksum %s does not match the directory", meta.BundleChecksum)
+	}
+
+	got, err := contentChecksum(path)
+	if err != nil {
+		return "", err
+	}
+	if got != name {
+		return fmt.Sprintf("contents recompute to %s", got), nil
+	}
+	return "", nil
+}
+
+// lastModified returns the latest modification time of path and
+// everything below it.
+func lastModified(path string) (time.Time, error) {
+	var latest time.Time
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return latest, err
+}
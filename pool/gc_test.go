@@ -0,0 +1,126 @@
+package pool
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jvzantvoort/bundle/lock"
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+// backdate sets the modification time of path and everything below it to
+// two hours ago.
+func backdate(t *testing.T, path string) {
+	t.Helper()
+	old := time.Now().Add(-2 * time.Hour)
+	err := filepath.WalkDir(path, func(p string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Chtimes(p, old, old)
+	})
+	if err != nil {
+		t.Fatalf("backdate %s: %v", path, err)
+	}
+}
+
+func TestPool_GC(t *testing.T) {
+	for _, layout := range []Layout{LayoutFlat, LayoutSharded} {
+		t.Run(string(layout), func(t *testing.T) {
+			p := &Pool{Root: t.TempDir(), Title: "test", Layout: layout}
+			good := importBundle(t, p, "hello")
+			tampered := importBundle(t, p, "world")
+
+			mkdir := func(path string) string {
+				if err := os.MkdirAll(path, 0755); err != nil {
+					t.Fatalf("mkdir: %v", err)
+				}
+				return path
+			}
+
+			// Garbage: an interrupted import, a pool entry without
+			// metadata and a bundle whose content no longer matches
+			partial := mkdir(p.GetBundlePath(good) + partialSuffix)
+			backdate(t, partial)
+			noMeta := mkdir(p.GetBundlePath(strings.Repeat("0", 64)))
+			if err := os.WriteFile(filepath.Join(p.GetBundlePath(tampered), "a.txt"), []byte("WORLD"), 0644); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			want := map[string]bool{partial: true, noMeta: true, p.GetBundlePath(tampered): true}
+
+			// Kept: directories that are not pool entries, an import that
+			// is still running, a recent partial and a bundle stored under
+			// another checksum
+			keep := []string{
+				mkdir(filepath.Join(p.Root, "not-a-bundle")),
+				mkdir(filepath.Join(p.Root, "lost+found")),
+				mkdir(p.GetBundlePath(tampered) + partialSuffix),
+				mkdir(p.GetBundlePath(strings.Repeat("2", 64)) + partialSuffix),
+			}
+			running, err := lock.AcquireLock(keep[3])
+			if err != nil {
+				t.Fatalf("AcquireLock failed: %v", err)
+			}
+			defer running.Release()
+			backdate(t, keep[3])
+			moved := p.GetBundlePath(strings.Repeat("1", 64))
+			if err := utils.CopyTree(p.GetBundlePath(good), moved, utils.CopyOptions{}); err != nil {
+				t.Fatalf("copy: %v", err)
+			}
+			keep = append(keep, moved, p.GetBundlePath(good))
+
+			garbage, err := p.GCWithOptions(GCOptions{DryRun: true})
+			if err != nil {
+				t.Fatalf("GC dry run failed: %v", err)
+			}
+			if len(garbage) != len(want) {
+				t.Fatalf("dry run found %v, want %v", garbage, want)
+			}
+			for _, dir := range garbage {
+				if !want[dir] {
+					t.Errorf("unexpected garbage %s", dir)
+				}
+				if _, err := os.Stat(dir); err != nil {
+					t.Errorf("dry run removed %s", dir)
+				}
+			}
+
+			// Declining the confirmation removes nothing
+			var asked []string
+			removed, err := p.GCWithOptions(GCOptions{Confirm: func(g []string) bool {
+				asked = g
+				return false
+			}})
+			if err != nil || len(removed) != 0 || len(asked) != len(want) {
+				t.Fatalf("declined GC = %v, %v, asked about %v", removed, err, asked)
+			}
+			for dir := range want {
+				if _, err := os.Stat(dir); err != nil {
+					t.Errorf("declined GC removed %s", dir)
+				}
+			}
+
+			removed, err = p.GC()
+			if err != nil {
+				t.Fatalf("GC failed: %v", err)
+			}
+			if len(removed) != len(want) {
+				t.Errorf("GC removed %v, want %v", removed, want)
+			}
+			for dir := range want {
+				if _, err := os.Stat(dir); !os.IsNotExist(err) {
+					t.Errorf("%s not removed", dir)
+				}
+			}
+			for _, dir := range keep {
+				if _, err := os.Stat(dir); err != nil {
+					t.Errorf("%s removed: %v", dir, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package pool
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jvzantvoort/bundle/metadata"
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+// Resolve returns the full checksum of the one bundle in the pool whose
+// checksum starts with prefix.
+//
+// Prefixes are matched against the bundle directory names, so bundles
+// with unreadable metadata can still be resolved. Partial imports are
+// ignored.
+//
+// Example:
+//
+//	pool, _ := pool.GetPool("default")
+//	sum, err := pool.Resolve("a1b2c3")
+//
+// Parameters:
+//   - prefix: the start of a bundle checksum, in lowercase hex
+//
+// Returns:
+//   - string: the full checksum
+//   - error: utils.ErrBundleNotFound if no bundle matches,
+//     utils.ErrAmbiguousPrefix if several do
+func (p *Pool) Resolve(prefix string) (string, error) {
+	prefix = strings.ToLower(prefix)
+	if prefix == "" || strings.Trim(prefix, "0123456789abcdef") != "" {
+		return "", fmt.Errorf("%w: '%s' is not a checksum prefix", utils.ErrInvalidPath, prefix)
+	}
+
+	if _, err := os.Stat(p.Root); os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: %s", utils.ErrBundleNotFound, prefix)
+	}
+	dirs, err := p.Layout.entryDirs(p.Root)
+	if err != nil {
+		return "", fmt.Errorf("failed to read pool directory: %w", err)
+	}
+
+	var matches []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return "", fmt.Errorf("failed to read pool directory: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && isBundleChecksum(entry.Name()) && strings.HasPrefix(entry.Name(), prefix) {
+				matches = append(matches, entry.Name())
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", utils.ErrBundleNotFound, prefix)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("%w: %s matches %d bundles", utils.ErrAmbiguousPrefix, prefix, len(matches))
+}
+
+// Get returns the metadata and path of the one bundle in the pool whose
+// checksum starts with prefix.
+//
+// It accepts the truncated checksums printed by list_bundles as well as
+// full ones.
+//
+// Example:
+//
+//	pool, _ := pool.GetPool("default")
+//	meta, path, err := pool.Get("e3b0c44298fc")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("%s: %s\n", meta.Title, path)
+//
+// Parameters:
+//   - prefix: the start of a bundle checksum, in lowercase hex
+//
+// Returns:
+//   - *metadata.Metadata: metadata of the bundle
+//   - string: full path to the bundle in the pool
+//   - error: utils.ErrBundleNotFound if no bundle matches,
+//     utils.ErrAmbiguousPrefix if several do, or metadata errors
+func (p *Pool) Get(prefix string) (*metadata.Metadata, string, error) {
+	sum, err := p.Resolve(prefix)
+	if err != nil {
+		return nil, "", err
+	}
+	bundlePath := p.GetBundlePath(sum)
+	meta, err := metadata.Load(bundlePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load bundle metadata: %w", err)
+	}
+	return meta, bundlePath, nil
+}
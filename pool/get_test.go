@@ -0,0 +1,71 @@
+package pool
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jvzantvoort/bundle/metadata"
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+func TestPool_Get(t *testing.T) {
+	p := &Pool{Root: t.TempDir(), Title: "test"}
+	sum := importBundle(t, p, "hello")
+
+	meta, path, err := p.Get(sum[:12])
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if meta.BundleChecksum != sum || meta.Title != "hello" {
+		t.Errorf("Get metadata = %+v, want bundle %s", meta, sum)
+	}
+	if path != p.GetBundlePath(sum) {
+		t.Errorf("Get path = %q, want %q", path, p.GetBundlePath(sum))
+	}
+}
+
+func TestPool_GetPrefixes(t *testing.T) {
+	for _, layout := range []Layout{LayoutFlat, LayoutSharded} {
+		t.Run(string(layout), func(t *testing.T) {
+			p := &Pool{Root: t.TempDir(), Layout: layout}
+			// Two bundles share the prefix "abc", a third starts with "abd"
+			sums := map[string]string{
+				"abc0" + strings.Repeat("0", 60): "first",
+				"abc1" + strings.Repeat("1", 60): "second",
+				"abd0" + strings.Repeat("2", 60): "third",
+			}
+			for sum, title := range sums {
+				bundlePath := p.GetBundlePath(sum)
+				if err := os.MkdirAll(filepath.Join(bundlePath, ".bundle"), 0755); err != nil {
+					t.Fatalf("mkdir: %v", err)
+				}
+				meta := &metadata.Metadata{Title: title, BundleChecksum: sum}
+				if err := meta.Save(bundlePath); err != nil {
+					t.Fatalf("Save: %v", err)
+				}
+			}
+
+			for prefix, title := range map[string]string{"abc0": "first", "abc1": "second", "abd": "third"} {
+				meta, _, err := p.Get(prefix)
+				if err != nil {
+					t.Errorf("Get(%s) failed: %v", prefix, err)
+				} else if meta.Title != title {
+					t.Errorf("Get(%s) title = %q, want %q", prefix, meta.Title, title)
+				}
+			}
+			for _, prefix := range []string{"ab", "abc"} {
+				if _, _, err := p.Get(prefix); !errors.Is(err, utils.ErrAmbiguousPrefix) {
+					t.Errorf("Get(%s) error = %v, want ErrAmbiguousPrefix", prefix, err)
+				}
+			}
+			for _, prefix := range []string{"ffff", "abe"} {
+				if _, _, err := p.Get(prefix); !errors.Is(err, utils.ErrBundleNotFound) {
+					t.Errorf("Get(%s) error = %v, want ErrBundleNotFound", prefix, err)
+				}
+			}
+		})
+	}
+}
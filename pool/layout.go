@@ -0,0 +1,80 @@
+package pool
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Layout names how bundles are arranged below a pool root.
+//
+// The layout is selected per pool with the `layout` configuration key:
+//
+//	pools:
+//	  default:
+//	    root: /mnt/bundles
+//	    layout: sharded
+type Layout string
+
+const (
+	// LayoutFlat stores bundles as root/<checksum> (the default).
+	LayoutFlat Layout = "flat"
+
+	// LayoutSharded stores bundles as root/<c[0:2]>/<c[2:4]>/<checksum> to
+	// keep directories small on pools with many bundles.
+	LayoutSharded Layout = "sharded"
+)
+
+// ParseLayout converts a configuration value to a Layout.
+//
+// An empty string yields LayoutFlat.
+//
+// Parameters:
+//   - s: one of "flat" or "sharded"
+//
+// Returns:
+//   - Layout: the parsed layout
+//   - error: if s is not a known layout
+func ParseLayout(s string) (Layout, error) {
+	switch Layout(s) {
+	case "", LayoutFlat:
+		return LayoutFlat, nil
+	case LayoutSharded:
+		return LayoutSharded, nil
+	}
+	return "", fmt.Errorf("invalid layout '%s': must be flat or sharded", s)
+}
+
+// bundlePath returns the directory of the bundle with checksum below root.
+func (l Layout) bundlePath(root, checksum string) string {
+	if l == LayoutSharded && len(checksum) >= 4 {
+		return filepath.Join(root, checksum[:2], checksum[2:4], checksum)
+	}
+	return filepath.Join(root, checksum)
+}
+
+// entryDirs returns the directories below root whose entries are bundles.
+func (l Layout) entryDirs(root string) ([]string, error) {
+	dirs := []string{root}
+	if l != LayoutSharded {
+		return dirs, nil
+	}
+
+	// Descend through the two levels of two-character shard directories
+	for depth := 0; depth < 2; depth++ {
+		var next []string
+		for _, dir := range dirs {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range entries {
+				if entry.IsDir() && len(entry.Name()) == 2 {
+					next = append(next, filepath.Join(dir, entry.Name()))
+				}
+			}
+		}
+		dirs = next
+	}
+	return dirs, nil
+}
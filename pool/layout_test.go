@@ -0,0 +1,56 @@
+package pool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jvzantvoort/bundle/bundle"
+)
+
+func TestLayout_BundlePath(t *testing.T) {
+	sum := "abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789"
+
+	flat := &Pool{Root: "/pool"}
+	if got, want := flat.GetBundlePath(sum), filepath.Join("/pool", sum); got != want {
+		t.Errorf("flat GetBundlePath = %q, want %q", got, want)
+	}
+
+	sharded := &Pool{Root: "/pool", Layout: LayoutSharded}
+	if got, want := sharded.GetBundlePath(sum), filepath.Join("/pool", "ab", "cd", sum); got != want {
+		t.Errorf("sharded GetBundlePath = %q, want %q", got, want)
+	}
+
+	if _, err := ParseLayout("nested"); err == nil {
+		t.Error("expected error for unknown layout")
+	}
+}
+
+func TestImportListBundles_Sharded(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "a.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	b, err := bundle.Create(src, "Sharded")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	p := &Pool{Root: t.TempDir(), Title: "test", Layout: LayoutSharded}
+	if err := p.Import(src, false); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	sum := b.Metadata.BundleChecksum
+	if _, err := os.Stat(filepath.Join(p.Root, sum[:2], sum[2:4], sum, ".bundle", "META.json")); err != nil {
+		t.Fatalf("bundle not stored in sharded location: %v", err)
+	}
+
+	bundles, err := p.ListBundles()
+	if err != nil {
+		t.Fatalf("ListBundles failed: %v", err)
+	}
+	if len(bundles) != 1 || bundles[0].BundleChecksum != sum {
+		t.Fatalf("ListBundles = %v, want bundle %s", bundles, sum)
+	}
+}
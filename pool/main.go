@@ -31,15 +31,20 @@ package pool
 
 import (
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/jvzantvoort/bundle/lock"
 	"github.com/jvzantvoort/bundle/metadata"
+	"github.com/jvzantvoort/bundle/utils"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
+// partialSuffix marks a pool directory that is still being imported.
+const partialSuffix = ".partial"
+
 // Pool represents a centralized bundle storage location.
 //
 // A pool is a directory where bundles are stored with their checksums
@@ -52,8 +57,34 @@ import (
 //	    Title: "Production Pool",
 //	}
 type Pool struct {
-	Root  string // Root directory for bundle storage
-	Title string // Human-readable pool title
+	Root   string // Root directory for bundle storage
+	Title  string // Human-readable pool title
+	Layout Layout // Storage layout below Root (flat or sharded)
+}
+
+// RootEnvVar returns the environment variable that overrides a pool's root.
+//
+// The name is upper-cased and every character other than a letter or digit
+// is replaced by an underscore.
+//
+// Example:
+//
+//	pool.RootEnvVar("default")  // "BUNDLE_POOL_DEFAULT_ROOT"
+//	pool.RootEnvVar("off-site") // "BUNDLE_POOL_OFF_SITE_ROOT"
+//
+// Parameters:
+//   - name: pool name from configuration
+//
+// Returns:
+//   - string: environment variable name
+func RootEnvVar(name string) string {
+	key := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+	return "BUNDLE_POOL_" + strings.ToUpper(key) + "_ROOT"
 }
 
 // GetPool retrieves a pool configuration by name.
@@ -62,6 +93,10 @@ type Pool struct {
 // the pool configuration. Returns error if pool is not found or
 // configuration is invalid.
 //
+// The root directory can be overridden at runtime with the environment
+// variable returned by RootEnvVar (e.g. BUNDLE_POOL_DEFAULT_ROOT); the
+// environment variable takes precedence over the configuration file.
+//
 // Example:
 //
 //	pool, err := pool.GetPool("default")
@@ -87,6 +122,13 @@ func GetPool(name string) (*Pool, error) {
 
 	root := viper.GetString(fmt.Sprintf("pools.%s.root", name))
 	log.Debugf("Pool '%s' root from config: %s", name, root)
+
+	// Environment variable takes precedence over the configuration file
+	envName := RootEnvVar(name)
+	if envRoot, ok := os.LookupEnv(envName); ok && envRoot != "" {
+		log.Debugf("Pool '%s' root overridden by %s: %s", name, envName, envRoot)
+		root = envRoot
+	}
 	
 	if root == "" {
 		log.Debugf("Pool '%s' has empty root directory", name)
@@ -101,14 +143,22 @@ func GetPool(name string) (*Pool, error) {
 		log.Debugf("Pool '%s' title from config: %s", name, title)
 	}
 
+	layout, err := ParseLayout(viper.GetString(fmt.Sprintf("pools.%s.layout", name)))
+	if err != nil {
+		log.Debugf("Pool '%s' has invalid layout: %v", name, err)
+		return nil, fmt.Errorf("pool '%s': %w", name, err)
+	}
+
 	pool := &Pool{
-		Root:  root,
-		Title: title,
+		Root:   root,
+		Title:  title,
+		Layout: layout,
 	}
 	
 	log.Debugf("Pool '%s' configuration loaded successfully:", name)
-	log.Debugf("  Root:  %s", pool.Root)
-	log.Debugf("  Title: %s", pool.Title)
+	log.Debugf("  Root:   %s", pool.Root)
+	log.Debugf("  Title:  %s", pool.Title)
+	log.Debugf("  Layout: %s", pool.Layout)
 
 	return pool, nil
 }
@@ -155,6 +205,18 @@ func ListPools() (map[string]*Pool, error) {
 	return pools, nil
 }
 
+// ImportOptions holds the optional settings for ImportWithOptions.
+//
+// Fields:
+//   - Move: remove the source bundle after a successful import
+//   - Hardlink: hard-link the data files into the pool instead of copying
+//     them, falling back to copying across devices; .bundle/ is always
+//     copied so the pooled metadata stays independent of the source
+type ImportOptions struct {
+	Move     bool
+	Hardlink bool
+}
+
 // Import copies or moves a bundle to the pool.
 //
 // The bundle is stored in the pool with its checksum as the directory name,
@@ -174,10 +236,35 @@ func ListPools() (map[string]*Pool, error) {
 // Returns:
 //   - error: if import fails
 func (p *Pool) Import(bundlePath string, move bool) error {
+	return p.ImportWithOptions(bundlePath, ImportOptions{Move: move})
+}
+
+// ImportWithOptions imports a bundle to the pool using opts.
+//
+// With opts.Hardlink set, data files on the same filesystem as the pool
+// are hard-linked rather than copied, so importing many similar bundles
+// costs no extra space. The pooled bundle then shares those files with
+// the source: editing a file in place in the source changes the pooled
+// copy too, which a verify of the pool will report.
+//
+// Example:
+//
+//	pool, _ := pool.GetPool("default")
+//	err := pool.ImportWithOptions("/path/to/bundle", pool.ImportOptions{Hardlink: true})
+//
+// Parameters:
+//   - bundlePath: path to the bundle to import
+//   - opts: import options
+//
+// Returns:
+//   - error: if import fails
+func (p *Pool) ImportWithOptions(bundlePath string, opts ImportOptions) error {
+	move := opts.Move
 	log.Debugf("Import called:")
 	log.Debugf("  Pool:   %s (%s)", p.Title, p.Root)
 	log.Debugf("  Source: %s", bundlePath)
 	log.Debugf("  Mode:   %s", map[bool]string{true: "move", false: "copy"}[move])
+	log.Debugf("  Links:  %v", opts.Hardlink)
 	
 	// Load bundle metadata to get checksum
 	log.Debugf("Loading bundle metadata from: %s", bundlePath)
@@ -192,8 +279,8 @@ func (p *Pool) Import(bundlePath string, move bool) error {
 	log.Debugf("  Checksum: %s", meta.BundleChecksum)
 	log.Debugf("  Author:   %s", meta.Author)
 
-	// Destination is root/checksum
-	destPath := filepath.Join(p.Root, meta.BundleChecksum)
+	// Destination is root/checksum (or its sharded equivalent)
+	destPath := p.GetBundlePath(meta.BundleChecksum)
 	log.Debugf("Destination path: %s", destPath)
 
 	// Check if bundle already exists in pool
@@ -202,20 +289,54 @@ func (p *Pool) Import(bundlePath string, move bool) error {
 		return fmt.Errorf("bundle already exists in pool: %s", meta.BundleChecksum)
 	}
 
-	// Ensure pool root exists
-	log.Debugf("Ensuring pool root directory exists: %s", p.Root)
-	if err := os.MkdirAll(p.Root, 0755); err != nil {
+	// Ensure pool root (and shard directory) exists
+	log.Debugf("Ensuring pool directory exists: %s", filepath.Dir(destPath))
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
 		log.Debugf("Failed to create pool directory: %v", err)
 		return fmt.Errorf("failed to create pool directory: %w", err)
 	}
 
+	// Copy into a staging directory first so an interrupted import never
+	// looks like a complete bundle and can be resumed on the next run
+	stagingPath := destPath + partialSuffix
+	if _, err := os.Stat(stagingPath); err == nil {
+		log.Debugf("Resuming partial import from: %s", stagingPath)
+	}
+
 	// Copy bundle to pool
-	log.Debugf("Copying bundle from %s to %s", bundlePath, destPath)
-	if err := copyDir(bundlePath, destPath); err != nil {
+	log.Debugf("Copying bundle from %s to %s", bundlePath, stagingPath)
+	stats := &utils.CopyStats{}
+	copyOpts := utils.CopyOptions{
+		Overwrite:      utils.OverwriteAlways,
+		FollowSymlinks: true,
+		Skip:           lock.IsLockFile,
+		Stats:          stats,
+	}
+	if opts.Hardlink {
+		metaDir := filepath.Join(bundlePath, ".bundle") + string(filepath.Separator)
+		copyOpts.Hardlink = func(src string) bool {
+			return !strings.HasPrefix(src, metaDir)
+		}
+	}
+
+	// Lock the staging directory so GC leaves a running import alone
+	stagingLock, err := lock.AcquireLock(stagingPath)
+	if err != nil {
+		log.Debugf("Failed to lock staging directory: %v", err)
+		return fmt.Errorf("failed to lock staging directory: %w", err)
+	}
+	err = utils.CopyTree(bundlePath, stagingPath, copyOpts)
+	stagingLock.Release()
+	if err != nil {
 		log.Debugf("Failed to copy bundle: %v", err)
 		return fmt.Errorf("failed to copy bundle: %w", err)
 	}
-	log.Debugf("Bundle copied successfully")
+
+	if err := os.Rename(stagingPath, destPath); err != nil {
+		log.Debugf("Failed to finalize import: %v", err)
+		return fmt.Errorf("failed to finalize import: %w", err)
+	}
+	log.Debugf("Bundle copied successfully (%d files, %d linked)", stats.Copied+stats.Overwritten+stats.Linked, stats.Linked)
 
 	// If move, remove source
 	if move {
@@ -258,46 +379,61 @@ func (p *Pool) ListBundles() ([]*metadata.Metadata, error) {
 		return bundles, nil // Empty pool
 	}
 
-	log.Debugf("Scanning pool directory: %s", p.Root)
-	
-	// Scan pool directory
-	entries, err := os.ReadDir(p.Root)
+	log.Debugf("Scanning pool directory: %s (%s layout)", p.Root, p.Layout)
+
+	dirs, err := p.Layout.entryDirs(p.Root)
 	if err != nil {
 		log.Debugf("Failed to read pool directory: %v", err)
 		return nil, fmt.Errorf("failed to read pool directory: %w", err)
 	}
-	
-	log.Debugf("Found %d entries in pool directory", len(entries))
 
 	// Load metadata for each bundle
+	totalEntries := 0
 	validBundles := 0
 	skippedEntries := 0
-	
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			log.Debugf("Skipping non-directory entry: %s", entry.Name())
-			skippedEntries++
-			continue
-		}
 
-		bundlePath := filepath.Join(p.Root, entry.Name())
-		log.Debugf("Loading bundle metadata from: %s", bundlePath)
-		
-		meta, err := metadata.Load(bundlePath)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
 		if err != nil {
-			// Skip invalid bundles
-			log.Debugf("Skipping invalid bundle %s: %v", entry.Name(), err)
-			skippedEntries++
-			continue
+			log.Debugf("Failed to read pool directory: %v", err)
+			return nil, fmt.Errorf("failed to read pool directory: %w", err)
 		}
 
-		log.Debugf("Bundle loaded: %s (%s)", meta.Title, meta.BundleChecksum[:12])
-		bundles = append(bundles, meta)
-		validBundles++
+		log.Debugf("Found %d entries in %s", len(entries), dir)
+		totalEntries += len(entries)
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				log.Debugf("Skipping non-directory entry: %s", entry.Name())
+				skippedEntries++
+				continue
+			}
+
+			if strings.HasSuffix(entry.Name(), partialSuffix) {
+				log.Debugf("Skipping partial import: %s", entry.Name())
+				skippedEntries++
+				continue
+			}
+
+			bundlePath := filepath.Join(dir, entry.Name())
+			log.Debugf("Loading bundle metadata from: %s", bundlePath)
+
+			meta, err := metadata.Load(bundlePath)
+			if err != nil {
+				// Skip invalid bundles
+				log.Debugf("Skipping invalid bundle %s: %v", entry.Name(), err)
+				skippedEntries++
+				continue
+			}
+
+			log.Debugf("Bundle loaded: %s (%s)", meta.Title, meta.BundleChecksum[:12])
+			bundles = append(bundles, meta)
+			validBundles++
+		}
 	}
-	
+
 	log.Debugf("ListBundles completed:")
-	log.Debugf("  Total entries:   %d", len(entries))
+	log.Debugf("  Total entries:   %d", totalEntries)
 	log.Debugf("  Valid bundles:   %d", validBundles)
 	log.Debugf("  Skipped entries: %d", skippedEntries)
 
@@ -306,72 +442,14 @@ func (p *Pool) ListBundles() ([]*metadata.Metadata, error) {
 
 // GetBundlePath returns the full path to a bundle in the pool.
 //
+// The path honors the pool layout: root/<checksum> for flat pools and
+// root/<c[0:2]>/<c[2:4]>/<checksum> for sharded pools.
+//
 // Parameters:
 //   - checksum: bundle checksum
 //
 // Returns:
 //   - string: full path to bundle
 func (p *Pool) GetBundlePath(checksum string) string {
-	return filepath.Join(p.Root, checksum)
-}
-
-// copyDir recursively copies a directory.
-func copyDir(src, dst string) error {
-	// Get source info
-	srcInfo, err := os.Stat(src)
-	if err != nil {
-		return err
-	}
-
-	// Create destination directory
-	if err := os.MkdirAll(dst, srcInfo.Mode()); err != nil {
-		return err
-	}
-
-	// Read source directory
-	entries, err := os.ReadDir(src)
-	if err != nil {
-		return err
-	}
-
-	// Copy each entry
-	for _, entry := range entries {
-		srcPath := filepath.Join(src, entry.Name())
-		dstPath := filepath.Join(dst, entry.Name())
-
-		if entry.IsDir() {
-			if err := copyDir(srcPath, dstPath); err != nil {
-				return err
-			}
-		} else {
-			if err := copyFile(srcPath, dstPath); err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
-}
-
-// copyFile copies a single file.
-func copyFile(src, dst string) error {
-	srcFile, err := os.Open(src)
-	if err != nil {
-		return err
-	}
-	defer srcFile.Close()
-
-	srcInfo, err := srcFile.Stat()
-	if err != nil {
-		return err
-	}
-
-	dstFile, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, srcInfo.Mode())
-	if err != nil {
-		return err
-	}
-	defer dstFile.Close()
-
-	_, err = io.Copy(dstFile, srcFile)
-	return err
+	return p.Layout.bundlePath(p.Root, checksum)
 }
@@ -0,0 +1,101 @@
+package pool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/spf13/viper"
+)
+
+func TestRootEnvVar(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"default", "BUNDLE_POOL_DEFAULT_ROOT"},
+		{"off-site", "BUNDLE_POOL_OFF_SITE_ROOT"},
+		{"Backup2", "BUNDLE_POOL_BACKUP2_ROOT"},
+	}
+
+	for _, tt := range tests {
+		if got := RootEnvVar(tt.name); got != tt.want {
+			t.Errorf("RootEnvVar(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetPool_EnvOverride(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("pools.default.root", "/from/config")
+	viper.Set("pools.default.title", "Default")
+
+	p, err := GetPool("default")
+	if err != nil {
+		t.Fatalf("GetPool failed: %v", err)
+	}
+	if p.Root != "/from/config" {
+		t.Fatalf("Root = %q, want config value", p.Root)
+	}
+
+	t.Setenv("BUNDLE_POOL_DEFAULT_ROOT", "/from/env")
+	p, err = GetPool("default")
+	if err != nil {
+		t.Fatalf("GetPool with env failed: %v", err)
+	}
+	if p.Root != "/from/env" {
+		t.Fatalf("Root = %q, want env override", p.Root)
+	}
+
+	// The override does not define pools that are not configured
+	t.Setenv("BUNDLE_POOL_MISSING_ROOT", "/from/env")
+	if _, err := GetPool("missing"); err == nil {
+		t.Fatal("expected error for unconfigured pool")
+	}
+}
+
+func TestPool_ImportHardlink(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "a.txt"), []byte("shared"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	b, err := bundle.Create(src, "Linked")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// The pool lives next to the source so both are on the same device
+	p := &Pool{Root: filepath.Join(filepath.Dir(src), "pool"), Title: "test"}
+	if err := p.ImportWithOptions(src, ImportOptions{Hardlink: true}); err != nil {
+		t.Fatalf("ImportWithOptions failed: %v", err)
+	}
+	pooled := p.GetBundlePath(b.Metadata.BundleChecksum)
+
+	same := func(rel string) bool {
+		a, err := os.Stat(filepath.Join(src, rel))
+		if err != nil {
+			t.Fatalf("stat source: %v", err)
+		}
+		c, err := os.Stat(filepath.Join(pooled, rel))
+		if err != nil {
+			t.Fatalf("stat pooled: %v", err)
+		}
+		return os.SameFile(a, c)
+	}
+	if !same("a.txt") {
+		t.Errorf("data file was copied, want hard link")
+	}
+	if same(filepath.Join(".bundle", "META.json")) {
+		t.Errorf("metadata is shared with the source, want a copy")
+	}
+
+	ok, corrupted, err := bundle.Verify(pooled)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !ok {
+		t.Errorf("hard-linked bundle does not verify: %v", corrupted)
+	}
+}
@@ -0,0 +1,62 @@
+package pool
+
+import (
+	"fmt"
+
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// MoveTo copies a bundle from the pool to another pool, optionally
+// removing it from this one.
+//
+// The bundle is imported into dest like any other bundle, so dest's layout
+// is used and a bundle that already exists in dest is refused. The copy is
+// then verified against its checksum; when that fails, it is deleted from
+// dest again and the source is kept. Only after a successful verification
+// is the source removed, if requested.
+//
+// Example:
+//
+//	staging, _ := pool.GetPool("staging")
+//	production, _ := pool.GetPool("production")
+//	sum, _ := staging.Resolve("a1b2c3")
+//	err := staging.MoveTo(sum, production, true)
+//
+// Parameters:
+//   - checksum: full bundle checksum; use Resolve for prefixes
+//   - dest: pool to copy the bundle to
+//   - remove: delete the bundle from this pool after a verified copy
+//
+// Returns:
+//   - error: utils.ErrBundleNotFound if the bundle is not in the pool,
+//     an error if it already exists in dest, utils.ErrCorruptedBundle if
+//     the copy does not verify, or I/O errors
+func (p *Pool) MoveTo(checksum string, dest *Pool, remove bool) error {
+	srcPath, err := p.existingBundlePath(checksum)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Copying bundle %s from pool %s to pool %s", checksum, p.Root, dest.Root)
+
+	if err := dest.ImportWithOptions(srcPath, ImportOptions{}); err != nil {
+		return err
+	}
+
+	ok, err := dest.VerifyBundle(checksum)
+	if err != nil || !ok {
+		if derr := dest.Delete(checksum); derr != nil {
+			log.Errorf("failed to remove copy from pool %s: %v", dest.Root, derr)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to verify copy: %w", err)
+		}
+		return fmt.Errorf("%w: copy of %s does not match its checksum", utils.ErrCorruptedBundle, checksum)
+	}
+
+	if remove {
+		log.Debugf("Removing bundle %s from pool %s", checksum, p.Root)
+		return p.Delete(checksum)
+	}
+	return nil
+}
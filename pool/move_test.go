@@ -0,0 +1,67 @@
+package pool
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+func TestPool_MoveTo(t *testing.T) {
+	staging := &Pool{Root: t.TempDir(), Title: "staging"}
+	production := &Pool{Root: t.TempDir(), Title: "production", Layout: LayoutSharded}
+
+	// Copy keeps the source
+	kept := importBundle(t, staging, "kept")
+	if err := staging.MoveTo(kept, production, false); err != nil {
+		t.Fatalf("MoveTo failed: %v", err)
+	}
+	for _, p := range []*Pool{staging, production} {
+		if ok, err := p.VerifyBundle(kept); err != nil || !ok {
+			t.Errorf("bundle in pool %s: ok=%v, err=%v", p.Title, ok, err)
+		}
+	}
+
+	// A bundle already in the destination is refused
+	if err := staging.MoveTo(kept, production, true); err == nil {
+		t.Error("expected an error for a bundle already in the destination")
+	}
+	if _, err := os.Stat(staging.GetBundlePath(kept)); err != nil {
+		t.Errorf("source removed after a refused move: %v", err)
+	}
+
+	// Move removes the source
+	moved := importBundle(t, staging, "moved")
+	if err := staging.MoveTo(moved, production, true); err != nil {
+		t.Fatalf("MoveTo failed: %v", err)
+	}
+	if _, err := os.Stat(staging.GetBundlePath(moved)); !os.IsNotExist(err) {
+		t.Errorf("source still present after move: %v", err)
+	}
+	if ok, err := production.VerifyBundle(moved); err != nil || !ok {
+		t.Errorf("moved bundle: ok=%v, err=%v", ok, err)
+	}
+
+	// A corrupted bundle is neither copied nor removed
+	bad := importBundle(t, staging, "bad")
+	if err := os.WriteFile(filepath.Join(staging.GetBundlePath(bad), "a.txt"), []byte("BAD"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := staging.MoveTo(bad, production, true); !errors.Is(err, utils.ErrCorruptedBundle) {
+		t.Fatalf("corrupted move error = %v, want ErrCorruptedBundle", err)
+	}
+	if _, err := os.Stat(production.GetBundlePath(bad)); !os.IsNotExist(err) {
+		t.Errorf("corrupted copy left in destination: %v", err)
+	}
+	if _, err := os.Stat(staging.GetBundlePath(bad)); err != nil {
+		t.Errorf("source removed after a failed move: %v", err)
+	}
+
+	// Unknown bundles are reported as not found
+	missing := "0000000000000000000000000000000000000000000000000000000000000000"
+	if err := staging.MoveTo(missing, production, false); !errors.Is(err, utils.ErrBundleNotFound) {
+		t.Errorf("missing bundle error = %v, want ErrBundleNotFound", err)
+	}
+}
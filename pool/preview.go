@@ -0,0 +1,116 @@
+package pool
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jvzantvoort/bundle/checksum"
+	"github.com/jvzantvoort/bundle/state"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// probeBytes is how much source data the throughput probe reads
+var probeBytes int64 = 16 << 20
+
+// defaultImportConfirmSize is the import_confirm_size default (10 GiB)
+const defaultImportConfirmSize int64 = 10 << 30
+
+// ImportPreview summarizes what an import will transfer.
+//
+// Files and SizeBytes come from the source bundle's manifest and
+// STATE.json. The estimate is based on a short read of the source files and
+// is zero when the probe could not measure a rate.
+type ImportPreview struct {
+	Files            int     `json:"files"`                    // Files in the bundle manifest
+	SizeBytes        int64   `json:"size_bytes"`               // Total size of the bundle files
+	Throughput       float64 `json:"throughput_bytes_per_sec"` // Measured source read rate
+	EstimatedSeconds float64 `json:"estimated_seconds"`        // SizeBytes / Throughput
+}
+
+// PreviewImport reports the file count, size and estimated copy time of a
+// bundle before it is imported.
+//
+// The size is taken from STATE.json, falling back to the sizes recorded in
+// the manifest. Throughput is probed by reading up to 16 MiB of the source
+// files; the estimate is optimistic when that data is already cached.
+//
+// Example:
+//
+//	preview, err := pool.PreviewImport("/path/to/bundle")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("%d files, %d bytes, ~%.0fs\n",
+//	    preview.Files, preview.SizeBytes, preview.EstimatedSeconds)
+//
+// Parameters:
+//   - bundlePath: path to the bundle to import
+//
+// Returns:
+//   - *ImportPreview: the transfer summary
+//   - error: if the bundle manifest cannot be loaded
+func PreviewImport(bundlePath string) (*ImportPreview, error) {
+	files := &checksum.ChecksumFile{}
+	if err := files.Load(bundlePath); err != nil {
+		return nil, err
+	}
+
+	preview := &ImportPreview{Files: len(files.Records)}
+	if bundleState, err := state.Load(bundlePath); err == nil && bundleState.SizeBytes > 0 {
+		preview.SizeBytes = bundleState.SizeBytes
+	} else {
+		for _, record := range files.Records {
+			preview.SizeBytes += record.Size
+		}
+	}
+
+	preview.Throughput = probeThroughput(bundlePath, files.Records)
+	if preview.Throughput > 0 {
+		preview.EstimatedSeconds = float64(preview.SizeBytes) / preview.Throughput
+	}
+	return preview, nil
+}
+
+// probeThroughput reads up to probeBytes from the files in records and
+// returns the observed rate in bytes per second, or 0 if nothing was read.
+func probeThroughput(bundlePath string, records []checksum.ChecksumRecord) float64 {
+	var read int64
+	start := time.Now()
+	for _, record := range records {
+		if read >= probeBytes {
+			break
+		}
+		file, err := os.Open(filepath.Join(bundlePath, record.FilePath))
+		if err != nil {
+			log.Debugf("Throughput probe skipping %s: %v", record.FilePath, err)
+			continue
+		}
+		n, _ := io.CopyN(io.Discard, file, probeBytes-read)
+		file.Close()
+		read += n
+	}
+
+	elapsed := time.Since(start).Seconds()
+	if read == 0 || elapsed <= 0 {
+		return 0
+	}
+	return float64(read) / elapsed
+}
+
+// ImportConfirmSize returns the bundle size above which an import needs
+// explicit confirmation.
+//
+// It reads the `import_confirm_size` configuration key (bytes), defaulting
+// to 10 GiB when unset or not positive.
+//
+// Returns:
+//   - int64: threshold in bytes
+func ImportConfirmSize() int64 {
+	if n := viper.GetInt64("import_confirm_size"); n > 0 {
+		return n
+	}
+	return defaultImportConfirmSize
+}
@@ -0,0 +1,51 @@
+package pool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/spf13/viper"
+)
+
+func TestPreviewImport(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "a.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "b.txt"), []byte("world!"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := bundle.Create(src, "Preview"); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	preview, err := PreviewImport(src)
+	if err != nil {
+		t.Fatalf("PreviewImport() error = %v", err)
+	}
+	if preview.Files != 2 || preview.SizeBytes != 11 {
+		t.Errorf("PreviewImport() = %d files, %d bytes; want 2, 11", preview.Files, preview.SizeBytes)
+	}
+	if preview.Throughput < 0 || preview.EstimatedSeconds < 0 {
+		t.Errorf("PreviewImport() negative estimate: %+v", preview)
+	}
+
+	if _, err := PreviewImport(t.TempDir()); !os.IsNotExist(err) {
+		t.Errorf("PreviewImport() on non-bundle error = %v, want not exist", err)
+	}
+}
+
+func TestImportConfirmSize(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Reset()
+	if got := ImportConfirmSize(); got != defaultImportConfirmSize {
+		t.Errorf("ImportConfirmSize() = %d, want default %d", got, defaultImportConfirmSize)
+	}
+	viper.Set("import_confirm_size", 1024)
+	if got := ImportConfirmSize(); got != 1024 {
+		t.Errorf("ImportConfirmSize() = %d, want 1024", got)
+	}
+}
@@ -0,0 +1,79 @@
+package pool
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jvzantvoort/bundle/state"
+	log "github.com/sirupsen/logrus"
+)
+
+// StaleBundle is a pooled bundle whose last verification is too old.
+type StaleBundle struct {
+	Checksum    string     `json:"checksum"`     // Bundle checksum
+	Title       string     `json:"title"`        // Bundle title
+	LastChecked *time.Time `json:"last_checked"` // Last verification, nil when never verified
+	Verified    bool       `json:"verified"`     // Result of the last verification
+}
+
+// ListStale returns the bundles in the pool that were not verified within
+// maxAge.
+//
+// Bundles that were never verified, or whose STATE.json cannot be read,
+// are stale too. The result is ordered by last verification, oldest first,
+// with never verified bundles before all others.
+//
+// Example:
+//
+//	p, _ := pool.GetPool("default")
+//	stale, err := p.ListStale(state.VerifyMaxAge())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, b := range stale {
+//	    fmt.Printf("%s %s\n", b.Checksum[:12], b.Title)
+//	}
+//
+// Parameters:
+//   - maxAge: maximum time since the last verification
+//
+// Returns:
+//   - []StaleBundle: stale bundles, never nil
+//   - error: if the pool cannot be scanned
+func (p *Pool) ListStale(maxAge time.Duration) ([]StaleBundle, error) {
+	bundles, err := p.ListBundles()
+	if err != nil {
+		return nil, err
+	}
+
+	stale := []StaleBundle{}
+	for _, meta := range bundles {
+		entry := StaleBundle{Checksum: meta.BundleChecksum, Title: meta.Title}
+
+		bundleState, err := state.Load(p.GetBundlePath(meta.BundleChecksum))
+		if err != nil {
+			log.Debugf("No state for bundle %s: %v", meta.BundleChecksum, err)
+			stale = append(stale, entry)
+			continue
+		}
+		if !bundleState.IsStale(maxAge) {
+			continue
+		}
+		if !bundleState.LastChecked.IsZero() {
+			lastChecked := bundleState.LastChecked
+			entry.LastChecked = &lastChecked
+		}
+		entry.Verified = bundleState.Verified
+		stale = append(stale, entry)
+	}
+
+	sort.SliceStable(stale, func(i, j int) bool {
+		a, b := stale[i].LastChecked, stale[j].LastChecked
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		return a.Before(*b)
+	})
+
+	return stale, nil
+}
@@ -0,0 +1,43 @@
+package pool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jvzantvoort/bundle/state"
+)
+
+func TestPool_ListStale(t *testing.T) {
+	p := &Pool{Root: t.TempDir(), Title: "test"}
+	importBundle(t, p, "fresh")
+	old := importBundle(t, p, "old")
+	never := importBundle(t, p, "never")
+
+	st, err := state.Load(p.GetBundlePath(old))
+	if err != nil {
+		t.Fatalf("Load state: %v", err)
+	}
+	st.LastChecked = time.Now().Add(-60 * 24 * time.Hour)
+	if err := st.Save(p.GetBundlePath(old)); err != nil {
+		t.Fatalf("Save state: %v", err)
+	}
+	if err := os.Remove(filepath.Join(p.GetBundlePath(never), ".bundle", "STATE.json")); err != nil {
+		t.Fatalf("remove state: %v", err)
+	}
+
+	stale, err := p.ListStale(30 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("ListStale failed: %v", err)
+	}
+	if len(stale) != 2 {
+		t.Fatalf("ListStale() = %+v, want 2 bundles", stale)
+	}
+	if stale[0].Checksum != never || stale[0].LastChecked != nil {
+		t.Errorf("stale[0] = %+v, want never verified bundle first", stale[0])
+	}
+	if stale[1].Checksum != old || stale[1].LastChecked == nil {
+		t.Errorf("stale[1] = %+v, want old bundle", stale[1])
+	}
+}
@@ -0,0 +1,138 @@
+package pool
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jvzantvoort/bundle/state"
+	log "github.com/sirupsen/logrus"
+)
+
+// PoolStats summarizes the contents of a pool.
+//
+// Bundles are classified by their STATE.json: verified when the last check
+// passed, corrupt when a check was performed and failed, and unverified when
+// the bundle was never checked or its state cannot be read.
+//
+// Oldest and Newest are the earliest and latest CreatedAt from META.json;
+// they are nil for an empty pool.
+type PoolStats struct {
+	Bundles    int        `json:"bundles"`          // Number of bundles in the pool
+	SizeBytes  int64      `json:"size_bytes"`       // Sum of bundle sizes from STATE.json
+	Verified   int        `json:"verified"`         // Bundles whose last check passed
+	Unverified int        `json:"unverified"`       // Bundles never checked or without state
+	Corrupt    int        `json:"corrupt"`          // Bundles whose last check failed
+	Oldest     *time.Time `json:"oldest,omitempty"` // Earliest bundle creation time
+	Newest     *time.Time `json:"newest,omitempty"` // Latest bundle creation time
+}
+
+// Add accumulates other into s.
+//
+// Example:
+//
+//	total := &pool.PoolStats{}
+//	for _, st := range perPool {
+//	    total.Add(st)
+//	}
+func (s *PoolStats) Add(other *PoolStats) {
+	s.Bundles += other.Bundles
+	s.SizeBytes += other.SizeBytes
+	s.Verified += other.Verified
+	s.Unverified += other.Unverified
+	s.Corrupt += other.Corrupt
+	if other.Oldest != nil {
+		s.addCreated(*other.Oldest)
+	}
+	if other.Newest != nil {
+		s.addCreated(*other.Newest)
+	}
+}
+
+// addCreated widens the Oldest/Newest range of s to include t.
+func (s *PoolStats) addCreated(t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	if s.Oldest == nil || t.Before(*s.Oldest) {
+		oldest := t
+		s.Oldest = &oldest
+	}
+	if s.Newest == nil || t.After(*s.Newest) {
+		newest := t
+		s.Newest = &newest
+	}
+}
+
+// Stats returns aggregate statistics for all bundles in the pool.
+//
+// Sizes come from each bundle's STATE.json. When the state cannot be read
+// the bundle's files are walked instead, so the total still covers it.
+//
+// Example:
+//
+//	p, _ := pool.GetPool("default")
+//	stats, err := p.Stats()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("%d bundles, %d bytes\n", stats.Bundles, stats.SizeBytes)
+//
+// Returns:
+//   - *PoolStats: aggregate statistics
+//   - error: if the pool cannot be scanned
+func (p *Pool) Stats() (*PoolStats, error) {
+	bundles, err := p.ListBundles()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &PoolStats{Bundles: len(bundles)}
+	for _, meta := range bundles {
+		stats.addCreated(meta.CreatedAt)
+
+		bundlePath := p.GetBundlePath(meta.BundleChecksum)
+		bundleState, err := state.Load(bundlePath)
+		if err != nil {
+			log.Debugf("No state for bundle %s: %v", meta.BundleChecksum, err)
+			stats.Unverified++
+			size, err := contentSize(bundlePath)
+			if err != nil {
+				return nil, err
+			}
+			stats.SizeBytes += size
+			continue
+		}
+
+		stats.SizeBytes += bundleState.SizeBytes
+		switch {
+		case bundleState.Verified:
+			stats.Verified++
+		case bundleState.LastChecked.IsZero():
+			stats.Unverified++
+		default:
+			stats.Corrupt++
+		}
+	}
+
+	return stats, nil
+}
+
+// contentSize returns the total size of the regular files of the bundle at
+// bundlePath, excluding .bundle/, matching STATE.json SizeBytes.
+func contentSize(bundlePath string) (int64, error) {
+	var size int64
+	err := filepath.Walk(bundlePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && path == filepath.Join(bundlePath, ".bundle") {
+			return filepath.SkipDir
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
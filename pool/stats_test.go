@@ -0,0 +1,43 @@
+package pool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPool_Stats(t *testing.T) {
+	p := &Pool{Root: t.TempDir(), Title: "test"}
+	if stats, err := p.Stats(); err != nil || stats.Bundles != 0 || stats.Oldest != nil {
+		t.Fatalf("empty pool Stats() = %+v, %v", stats, err)
+	}
+
+	importBundle(t, p, "hello")
+	second := importBundle(t, p, "hi")
+
+	// Without STATE.json the size comes from the files themselves
+	if err := os.Remove(filepath.Join(p.GetBundlePath(second), ".bundle", "STATE.json")); err != nil {
+		t.Fatalf("remove state: %v", err)
+	}
+
+	stats, err := p.Stats()
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if stats.Bundles != 2 {
+		t.Errorf("Bundles = %d, want 2", stats.Bundles)
+	}
+	if stats.SizeBytes != int64(len("hello")+len("hi")) {
+		t.Errorf("SizeBytes = %d, want %d", stats.SizeBytes, len("hello")+len("hi"))
+	}
+	if stats.Oldest == nil || stats.Newest == nil || stats.Newest.Before(*stats.Oldest) {
+		t.Fatalf("Oldest/Newest = %v/%v", stats.Oldest, stats.Newest)
+	}
+
+	total := &PoolStats{}
+	total.Add(stats)
+	total.Add(&PoolStats{})
+	if total.Bundles != 2 || !total.Oldest.Equal(*stats.Oldest) || !total.Newest.Equal(*stats.Newest) {
+		t.Errorf("Add() = %+v, want %+v", total, stats)
+	}
+}
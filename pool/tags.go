@@ -0,0 +1,247 @@
+package pool
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jvzantvoort/bundle/checksum"
+	"github.com/jvzantvoort/bundle/metadata"
+	"github.com/jvzantvoort/bundle/tag"
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// TagReject is a mapping entry that ImportTags did not apply.
+type TagReject struct {
+	Checksum string `json:"checksum"`      // Bundle checksum from the mapping
+	Tag      string `json:"tag,omitempty"` // Offending tag, empty for bundle-level rejects
+	Reason   string `json:"reason"`        // Why the entry was rejected
+}
+
+// TagImportResult reports what ImportTags changed.
+type TagImportResult struct {
+	Updated  int         `json:"updated"`  // Bundles whose TAGS.txt was written
+	Rejected []TagReject `json:"rejected"` // Entries that were not applied
+}
+
+// ExportTags returns the tags of every bundle in the pool.
+//
+// Bundles without tags are included with an empty list so that importing
+// the export with replace semantics restores the taxonomy exactly.
+//
+// Example:
+//
+//	p, _ := pool.GetPool("default")
+//	mapping, err := p.ExportTags()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	tag.WriteMapping(os.Stdout, mapping, false)
+//
+// Returns:
+//   - tag.Mapping: bundle checksum to sorted tags
+//   - error: if the pool cannot be scanned or a TAGS.txt cannot be read
+func (p *Pool) ExportTags() (tag.Mapping, error) {
+	bundles, err := p.ListBundles()
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := tag.Mapping{}
+	for _, meta := range bundles {
+		tags, err := tag.Load(p.GetBundlePath(meta.BundleChecksum))
+		if err != nil {
+			return nil, err
+		}
+		mapping[meta.BundleChecksum] = tags.List()
+	}
+	return mapping, nil
+}
+
+// TagCounts returns how many bundles in the pool carry each tag.
+//
+// Tags are counted in normalized form, as Load returns them, so "Travel"
+// and "travel" in different bundles count as one tag. Bundles without a
+// TAGS.txt contribute nothing.
+//
+// Example:
+//
+//	p, _ := pool.GetPool("default")
+//	counts, err := p.TagCounts()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("travel: %d bundles\n", counts["travel"])
+//
+// Returns:
+//   - map[string]int: tag to number of bundles carrying it
+//   - error: if the pool cannot be scanned or a TAGS.txt cannot be read
+func (p *Pool) TagCounts() (map[string]int, error) {
+	bundles, err := p.ListBundles()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, meta := range bundles {
+		tags, err := tag.Load(p.GetBundlePath(meta.BundleChecksum))
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tags.Tags {
+			counts[t]++
+		}
+	}
+	return counts, nil
+}
+
+// ImportTags applies a tag mapping to the bundles in the pool.
+//
+// By default the mapped tags are merged into each bundle's existing tags;
+// with replace the bundle's tags are set to exactly the valid mapped tags.
+// Invalid tags and checksums that are not in the pool are skipped and
+// reported in the result rather than failing the import.
+//
+// Example:
+//
+//	file, _ := os.Open("tags.json")
+//	mapping, _ := tag.ReadMapping(file)
+//	result, err := p.ImportTags(mapping, false)
+//	fmt.Printf("%d updated, %d rejected\n", result.Updated, len(result.Rejected))
+//
+// Parameters:
+//   - mapping: bundle checksum to tags
+//   - replace: replace existing tags instead of merging
+//
+// Returns:
+//   - *TagImportResult: updated count and rejected entries
+//   - error: if a TAGS.txt cannot be read or written
+func (p *Pool) ImportTags(mapping tag.Mapping, replace bool) (*TagImportResult, error) {
+	checksums := make([]string, 0, len(mapping))
+	for checksum := range mapping {
+		checksums = append(checksums, checksum)
+	}
+	sort.Strings(checksums)
+
+	result := &TagImportResult{Rejected: []TagReject{}}
+	for _, checksum := range checksums {
+		if !isBundleChecksum(checksum) {
+			result.Rejected = append(result.Rejected, TagReject{Checksum: checksum, Reason: "invalid checksum"})
+			continue
+		}
+		bundlePath := p.GetBundlePath(checksum)
+		if !utils.IsBundleDir(bundlePath) {
+			result.Rejected = append(result.Rejected, TagReject{Checksum: checksum, Reason: "bundle not in pool"})
+			continue
+		}
+
+		valid := []string{}
+		for _, raw := range mapping[checksum] {
+			if _, ok := tag.Valid(raw); !ok {
+				result.Rejected = append(result.Rejected, TagReject{Checksum: checksum, Tag: raw, Reason: "invalid tag"})
+				continue
+			}
+			valid = append(valid, raw)
+		}
+
+		tags, err := tag.Load(bundlePath)
+		if err != nil {
+			return nil, err
+		}
+		if replace {
+			tags = &tag.Tags{}
+		}
+		tags.Add(valid...)
+		if err := tags.Save(bundlePath); err != nil {
+			return nil, err
+		}
+		log.Debugf("Tagged %s: %v", checksum, tags.List())
+		result.Updated++
+	}
+	return result, nil
+}
+
+// FindByTag returns the bundles in the pool that carry all of tags.
+//
+// The tags are normalized like tags added to a bundle, so "Travel" finds
+// bundles tagged "travel". Bundles are returned in pool order.
+//
+// Example:
+//
+//	p, _ := pool.GetPool("default")
+//	bundles, err := p.FindByTag("travel", "2024")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, meta := range bundles {
+//	    fmt.Printf("%s: %s\n", meta.BundleChecksum[:12], meta.Title)
+//	}
+//
+// Parameters:
+//   - tags: one or more tags that must all be present
+//
+// Returns:
+//   - []*metadata.Metadata: matching bundles, never nil
+//   - error: if no tag is given, a tag is invalid, or the pool or a
+//     TAGS.txt cannot be read
+func (p *Pool) FindByTag(tags ...string) ([]*metadata.Metadata, error) {
+	return p.findByTags(tags, true)
+}
+
+// FindByAnyTag returns the bundles in the pool that carry at least one of
+// tags. It is the OR counterpart of FindByTag.
+//
+// Parameters:
+//   - tags: one or more tags of which any must be present
+//
+// Returns:
+//   - []*metadata.Metadata: matching bundles, never nil
+//   - error: as for FindByTag
+func (p *Pool) FindByAnyTag(tags ...string) ([]*metadata.Metadata, error) {
+	return p.findByTags(tags, false)
+}
+
+// findByTags returns the bundles carrying all (matchAll) or any of tags.
+func (p *Pool) findByTags(tags []string, matchAll bool) ([]*metadata.Metadata, error) {
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("no tags to search for")
+	}
+	wanted := make([]string, len(tags))
+	for i, raw := range tags {
+		normalized, ok := tag.Valid(raw)
+		if !ok {
+			return nil, fmt.Errorf("invalid tag '%s'", raw)
+		}
+		wanted[i] = normalized
+	}
+
+	bundles, err := p.ListBundles()
+	if err != nil {
+		return nil, err
+	}
+
+	found := []*metadata.Metadata{}
+	for _, meta := range bundles {
+		bundleTags, err := tag.Load(p.GetBundlePath(meta.BundleChecksum))
+		if err != nil {
+			return nil, err
+		}
+		matches := 0
+		for _, t := range wanted {
+			if bundleTags.Has(t) {
+				matches++
+			}
+		}
+		if (matchAll && matches == len(wanted)) || (!matchAll && matches > 0) {
+			found = append(found, meta)
+		}
+	}
+	log.Debugf("Found %d of %d bundles tagged %v", len(found), len(bundles), wanted)
+	return found, nil
+}
+
+// isBundleChecksum reports whether s looks like a bundle checksum, so
+// mapping keys can never address paths outside the pool.
+func isBundleChecksum(s string) bool {
+	return checksum.SHA256.IsChecksum(s) || checksum.SHA512.IsChecksum(s)
+}
@@ -0,0 +1,154 @@
+package pool
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/metadata"
+	"github.com/jvzantvoort/bundle/tag"
+)
+
+func TestExportImportTags(t *testing.T) {
+	p := &Pool{Root: t.TempDir(), Title: "test", Layout: LayoutFlat}
+
+	var sums []string
+	for i, name := range []string{"one", "two"} {
+		src := t.TempDir()
+		if err := os.WriteFile(filepath.Join(src, "a.txt"), []byte(name), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		b, err := bundle.Create(src, name)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if i == 0 {
+			tags := &tag.Tags{}
+			tags.Add("old")
+			if err := tags.Save(src); err != nil {
+				t.Fatalf("Save tags: %v", err)
+			}
+		}
+		if err := p.Import(src, false); err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		sums = append(sums, b.Metadata.BundleChecksum)
+	}
+
+	exported, err := p.ExportTags()
+	if err != nil {
+		t.Fatalf("ExportTags failed: %v", err)
+	}
+	want := tag.Mapping{sums[0]: {"old"}, sums[1]: {}}
+	if !reflect.DeepEqual(exported, want) {
+		t.Fatalf("ExportTags = %v, want %v", exported, want)
+	}
+
+	// Merge, with an invalid tag and an unknown bundle
+	mapping := tag.Mapping{
+		sums[0]:                 {"New", "bad tag"},
+		strings.Repeat("0", 64): {"x"},
+		"../escape":             {"x"},
+	}
+	result, err := p.ImportTags(mapping, false)
+	if err != nil {
+		t.Fatalf("ImportTags failed: %v", err)
+	}
+	if result.Updated != 1 || len(result.Rejected) != 3 {
+		t.Fatalf("ImportTags result = %+v, want 1 updated and 3 rejected", result)
+	}
+	if tags, _ := tag.Load(p.GetBundlePath(sums[0])); !reflect.DeepEqual(tags.List(), []string{"new", "old"}) {
+		t.Errorf("merged tags = %v, want [new old]", tags.List())
+	}
+
+	// Replace restores the export
+	if _, err := p.ImportTags(exported, true); err != nil {
+		t.Fatalf("ImportTags replace failed: %v", err)
+	}
+	if tags, _ := tag.Load(p.GetBundlePath(sums[0])); !reflect.DeepEqual(tags.List(), []string{"old"}) {
+		t.Errorf("replaced tags = %v, want [old]", tags.List())
+	}
+}
+
+func TestFindByTag(t *testing.T) {
+	p := &Pool{Root: t.TempDir(), Title: "test", Layout: LayoutFlat}
+	tagged := map[string][]string{
+		"rome":   {"travel", "2024"},
+		"paris":  {"travel", "2023"},
+		"report": {"work"},
+	}
+	for name, tags := range tagged {
+		sum := importBundle(t, p, name)
+		bundleTags := &tag.Tags{}
+		bundleTags.Add(tags...)
+		if err := bundleTags.Save(p.GetBundlePath(sum)); err != nil {
+			t.Fatalf("Save tags: %v", err)
+		}
+	}
+
+	titles := func(found []*metadata.Metadata) []string {
+		names := []string{}
+		for _, meta := range found {
+			names = append(names, meta.Title)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	found, err := p.FindByTag("Travel", "2024")
+	if err != nil {
+		t.Fatalf("FindByTag failed: %v", err)
+	}
+	if got := titles(found); !reflect.DeepEqual(got, []string{"rome"}) {
+		t.Errorf("FindByTag(travel, 2024) = %v, want [rome]", got)
+	}
+
+	found, err = p.FindByAnyTag("2023", "work")
+	if err != nil {
+		t.Fatalf("FindByAnyTag failed: %v", err)
+	}
+	if got := titles(found); !reflect.DeepEqual(got, []string{"paris", "report"}) {
+		t.Errorf("FindByAnyTag(2023, work) = %v, want [paris report]", got)
+	}
+
+	found, err = p.FindByTag("missing")
+	if err != nil || found == nil || len(found) != 0 {
+		t.Errorf("FindByTag(missing) = %v, %v, want empty", found, err)
+	}
+
+	if _, err := p.FindByTag("two words"); err == nil {
+		t.Error("FindByTag accepted an invalid tag")
+	}
+	if _, err := p.FindByTag(); err == nil {
+		t.Error("FindByTag accepted no tags")
+	}
+}
+
+func TestTagCounts(t *testing.T) {
+	p := &Pool{Root: t.TempDir(), Title: "test", Layout: LayoutFlat}
+	for name, raw := range map[string]string{
+		"rome":  "Travel\n2024\n",
+		"paris": "travel\n",
+	} {
+		sum := importBundle(t, p, name)
+		tagsFile := filepath.Join(p.GetBundlePath(sum), ".bundle", "TAGS.txt")
+		if err := os.WriteFile(tagsFile, []byte(raw), 0644); err != nil {
+			t.Fatalf("write tags: %v", err)
+		}
+	}
+	untagged := importBundle(t, p, "untagged")
+	os.Remove(filepath.Join(p.GetBundlePath(untagged), ".bundle", "TAGS.txt"))
+
+	counts, err := p.TagCounts()
+	if err != nil {
+		t.Fatalf("TagCounts failed: %v", err)
+	}
+	want := map[string]int{"travel": 2, "2024": 1}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("TagCounts() = %v, want %v", counts, want)
+	}
+}
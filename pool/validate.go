@@ -0,0 +1,83 @@
+package pool
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jvzantvoort/bundle/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// Validate checks that the pool root can hold bundles.
+//
+// The root must be a writable directory. A root that does not exist yet is
+// valid when Import could create it, i.e. when its nearest existing parent
+// is a writable directory. Writability is tested by creating and removing
+// a temporary file, so ownership, mode bits and read-only mounts are all
+// taken into account.
+//
+// Example:
+//
+//	pool, _ := pool.GetPool("default")
+//	if err := pool.Validate(); err != nil {
+//	    log.Fatalf("pool default: %v", err)
+//	}
+//
+// Returns:
+//   - error: nil if the root is usable, otherwise a description of the
+//     problem; permission problems wrap os.ErrPermission
+func (p *Pool) Validate() error {
+	log.Debugf("Validating pool root: %s", p.Root)
+	if p.Root == "" {
+		return fmt.Errorf("no root directory configured")
+	}
+
+	info, err := os.Stat(p.Root)
+	if os.IsNotExist(err) {
+		parent, err := existingParent(p.Root)
+		if err != nil {
+			return fmt.Errorf("root %s cannot be created: %w", p.Root, err)
+		}
+		if err := utils.CheckWritable(parent); err != nil {
+			return fmt.Errorf("root %s cannot be created: %w", p.Root, err)
+		}
+		log.Debugf("Pool root %s does not exist yet, can be created in %s", p.Root, parent)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("root %s: %w", p.Root, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("root %s is not a directory", p.Root)
+	}
+	if err := utils.CheckWritable(p.Root); err != nil {
+		return fmt.Errorf("root %s is not writable: %w", p.Root, err)
+	}
+	return nil
+}
+
+// existingParent returns the nearest ancestor of path that exists, which
+// must be a directory.
+func existingParent(path string) (string, error) {
+	dir := filepath.Clean(path)
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no existing parent directory")
+		}
+		dir = parent
+
+		info, err := os.Stat(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if !info.IsDir() {
+			return "", fmt.Errorf("%s is not a directory", dir)
+		}
+		return dir, nil
+	}
+}
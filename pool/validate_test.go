@@ -0,0 +1,56 @@
+package pool
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		root    string
+		wantErr bool
+	}{
+		{"existing directory", dir, false},
+		{"creatable", filepath.Join(dir, "new", "pool"), false},
+		{"empty root", "", true},
+		{"root is a file", file, true},
+		{"parent is a file", filepath.Join(file, "pool"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Pool{Root: tt.root, Title: "test"}
+			err := p.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Validation creates nothing
+	if _, err := os.Stat(filepath.Join(dir, "new")); !os.IsNotExist(err) {
+		t.Errorf("Validate() created the root: %v", err)
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("permission checks do not apply to root")
+	}
+	readOnly := filepath.Join(dir, "ro")
+	if err := os.Mkdir(readOnly, 0555); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for _, root := range []string{readOnly, filepath.Join(readOnly, "pool")} {
+		p := &Pool{Root: root, Title: "test"}
+		if err := p.Validate(); !errors.Is(err, os.ErrPermission) {
+			t.Errorf("Validate(%s) error = %v, want ErrPermission", root, err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package pool
+
+import (
+	"github.com/jvzantvoort/bundle/bundle"
+	"github.com/jvzantvoort/bundle/checksum"
+)
+
+// VerifyBundle checks that a pooled bundle still matches the checksum it
+// is stored under.
+//
+// Every file is hashed again and the bundle checksum is recomputed from
+// the results; files missing from disk are left out, so they cause a
+// mismatch too. Like bundle.Verify, it records the outcome of the file
+// checks in the bundle's STATE.json.
+//
+// Example:
+//
+//	pool, _ := pool.GetPool("default")
+//	ok, err := pool.VerifyBundle(sum)
+//	if err == nil && !ok {
+//	    fmt.Printf("%s no longer matches its content\n", sum)
+//	}
+//
+// Parameters:
+//   - checksum: full bundle checksum; use Resolve for prefixes
+//
+// Returns:
+//   - bool: true if the content recomputes to checksum
+//   - error: utils.ErrBundleNotFound if the bundle is not in the pool, lock
+//     errors, or I/O errors
+func (p *Pool) VerifyBundle(checksum string) (bool, error) {
+	bundlePath, err := p.existingBundlePath(checksum)
+	if err != nil {
+		return false, err
+	}
+	got, err := contentChecksum(bundlePath)
+	if err != nil {
+		return false, err
+	}
+	return got == checksum, nil
+}
+
+// contentChecksum recomputes the bundle checksum of the bundle at
+// bundlePath from the files on disk.
+func contentChecksum(bundlePath string) (string, error) {
+	report, err := bundle.VerifyWithOptions(bundlePath, bundle.VerifyOptions{})
+	if err != nil {
+		return "", err
+	}
+	sums := make([]string, len(report.Recomputed.Records))
+	for i, record := range report.Recomputed.Records {
+		sums[i] = record.Checksum
+	}
+	return checksum.ComputeBundleHash(sums, report.Algorithm), nil
+}
@@ -0,0 +1,31 @@
+package pool
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jvzantvoort/bundle/utils"
+)
+
+func TestPool_VerifyBundle(t *testing.T) {
+	p := &Pool{Root: t.TempDir(), Title: "test"}
+	sum := importBundle(t, p, "hello")
+
+	if ok, err := p.VerifyBundle(sum); err != nil || !ok {
+		t.Fatalf("VerifyBundle() = %v, %v; want true", ok, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(p.GetBundlePath(sum), "a.txt"), []byte("HELLO"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if ok, err := p.VerifyBundle(sum); err != nil || ok {
+		t.Errorf("tampered VerifyBundle() = %v, %v; want false", ok, err)
+	}
+
+	missing := "0000000000000000000000000000000000000000000000000000000000000000"
+	if _, err := p.VerifyBundle(missing); !errors.Is(err, utils.ErrBundleNotFound) {
+		t.Errorf("missing bundle error = %v, want ErrBundleNotFound", err)
+	}
+}
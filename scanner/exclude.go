@@ -0,0 +1,128 @@
+package scanner
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Excludes is a list of gitignore-style patterns selecting paths to leave
+// out of a scan.
+//
+// Patterns are matched against slash-separated paths relative to the scan
+// root:
+//   - a pattern without a slash matches a file or directory name at any
+//     depth ("*.tmp", ".DS_Store")
+//   - a pattern with a slash is anchored at the root ("build/out",
+//     "/notes.txt")
+//   - a trailing slash only matches directories ("cache/")
+//   - "*", "?" and "[...]" match within a path component, "**" matches any
+//     number of components ("docs/**/*.bak")
+//   - a leading "!" re-includes paths excluded by an earlier pattern
+//
+// The last matching pattern decides. An excluded directory is skipped as a
+// whole, so, as with git, a file below it cannot be re-included.
+//
+// Example:
+//
+//	ex := scanner.Excludes{".DS_Store", "Thumbs.db", "*~", "tmp/"}
+//	ex.Match("photos/.DS_Store", false) // true
+type Excludes []string
+
+// Validate checks that every pattern is well-formed.
+//
+// Returns:
+//   - error: for the first empty or malformed pattern
+func (e Excludes) Validate() error {
+	for _, pattern := range e {
+		if strings.Trim(strings.TrimPrefix(pattern, "!"), "/") == "" {
+			return fmt.Errorf("invalid exclude pattern '%s': empty pattern", pattern)
+		}
+		for _, segment := range parsePattern(pattern).segments {
+			if _, err := path.Match(segment, ""); err != nil {
+				return fmt.Errorf("invalid exclude pattern '%s': %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Match reports whether the path relPath, relative to the scan root, is
+// excluded.
+//
+// Parameters:
+//   - relPath: path relative to the scan root, with either separator
+//   - isDir: whether relPath is a directory
+//
+// Returns:
+//   - bool: true if the last pattern matching relPath is not negated
+func (e Excludes) Match(relPath string, isDir bool) bool {
+	if len(e) == 0 {
+		return false
+	}
+	name := strings.Split(filepath.ToSlash(relPath), "/")
+	excluded := false
+	for _, pattern := range e {
+		p := parsePattern(pattern)
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if matchSegments(p.segments, name) {
+			excluded = !p.negate
+		}
+	}
+	return excluded
+}
+
+// excludePattern is a parsed Excludes entry.
+type excludePattern struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+}
+
+// parsePattern splits a pattern into path components, anchoring patterns
+// without an inner slash below "**".
+func parsePattern(pattern string) excludePattern {
+	var p excludePattern
+	if strings.HasPrefix(pattern, "!") {
+		p.negate = true
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		p.dirOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	p.segments = strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	return p
+}
+
+// matchSegments matches pattern components against path components.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			if len(pattern) == 1 {
+				// A trailing ** matches everything inside, not the directory
+				return len(name) > 0
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
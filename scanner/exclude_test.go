@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestExcludesMatch(t *testing.T) {
+	for _, tc := range []struct {
+		patterns []string
+		path     string
+		isDir    bool
+		want     bool
+	}{
+		{[]string{".DS_Store"}, ".DS_Store", false, true},
+		{[]string{".DS_Store"}, "photos/2024/.DS_Store", false, true},
+		{[]string{"*~"}, "notes.txt~", false, true},
+		{[]string{"*~"}, "notes.txt", false, false},
+		{[]string{"build/out"}, "build/out", true, true},
+		{[]string{"build/out"}, "src/build/out", true, false},
+		{[]string{"/notes.txt"}, "notes.txt", false, true},
+		{[]string{"/notes.txt"}, "docs/notes.txt", false, false},
+		{[]string{"cache/"}, "cache", true, true},
+		{[]string{"cache/"}, "cache", false, false},
+		{[]string{"docs/**/*.bak"}, "docs/a.bak", false, true},
+		{[]string{"docs/**/*.bak"}, "docs/x/y/a.bak", false, true},
+		{[]string{"docs/**"}, "docs", true, false},
+		{[]string{"docs/**"}, "docs/a.txt", false, true},
+		{[]string{"*.log", "!keep.log"}, "keep.log", false, false},
+		{[]string{"*.log", "!keep.log"}, "other.log", false, true},
+		{nil, "anything", false, false},
+	} {
+		if got := Excludes(tc.patterns).Match(tc.path, tc.isDir); got != tc.want {
+			t.Errorf("Excludes(%q).Match(%q, %v) = %v, want %v", tc.patterns, tc.path, tc.isDir, got, tc.want)
+		}
+	}
+}
+
+func TestExcludesValidate(t *testing.T) {
+	if err := (Excludes{"*.tmp", "docs/**", "!keep"}).Validate(); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+	for _, bad := range []string{"", "!", "/", "[a-"} {
+		if err := (Excludes{bad}).Validate(); err == nil {
+			t.Errorf("Validate(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestScanDirectoryExcluding(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.txt", ".DS_Store", "sub/b.txt", "sub/Thumbs.db", "tmp/c.txt", ".bundle/META.json"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	files, err := ScanDirectoryExcluding(root, []string{".DS_Store", "Thumbs.db", "tmp/"})
+	if err != nil {
+		t.Fatalf("ScanDirectoryExcluding failed: %v", err)
+	}
+	var got []string
+	for _, f := range files {
+		rel, _ := filepath.Rel(root, f)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	sort.Strings(got)
+	if want := []string{"a.txt", "sub/b.txt"}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("ScanDirectoryExcluding() = %v, want %v", got, want)
+	}
+
+	if _, err := ScanDirectoryExcluding(root, []string{"[a-"}); err == nil {
+		t.Errorf("malformed pattern accepted")
+	}
+}
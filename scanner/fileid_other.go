@@ -0,0 +1,27 @@
+//go:build !unix
+
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// fileID identifies a file or directory independent of the path it was
+// reached by. Without device and inode numbers the resolved path is used.
+type fileID struct {
+	path string
+}
+
+// fileIDOf returns the resolved absolute path of path.
+func fileIDOf(path string, info os.FileInfo) (fileID, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fileID{}, err
+	}
+	real, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return fileID{}, err
+	}
+	return fileID{path: real}, nil
+}
@@ -0,0 +1,26 @@
+//go:build unix
+
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// fileID identifies a file or directory independent of the path it was
+// reached by.
+type fileID struct {
+	dev uint64
+	ino uint64
+}
+
+// fileIDOf returns the device and inode number of info, the result of a
+// stat of path.
+func fileIDOf(path string, info os.FileInfo) (fileID, error) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, fmt.Errorf("no device and inode for %s", path)
+	}
+	return fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)}, nil
+}
@@ -0,0 +1,91 @@
+package scanner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFile is the name of the optional file in a bundle root listing
+// exclude patterns, one per line.
+const IgnoreFile = ".bundleignore"
+
+// ReadIgnoreFile reads the exclude patterns from the IgnoreFile in rootPath.
+//
+// The file uses the Excludes syntax, one pattern per line. Blank lines and
+// lines starting with "#" are skipped; a pattern starting with "#" is
+// written as "\#". Trailing whitespace is trimmed.
+//
+// Example:
+//
+//	patterns, err := scanner.ReadIgnoreFile("/path/to/photos")
+//	if err != nil {
+//	    return err
+//	}
+//
+// Parameters:
+//   - rootPath: bundle root directory
+//
+// Returns:
+//   - Excludes: the patterns in the file, nil if there is none
+//   - error: if the file cannot be read or holds a malformed pattern
+func ReadIgnoreFile(rootPath string) (Excludes, error) {
+	ignorePath := filepath.Join(rootPath, IgnoreFile)
+	file, err := os.Open(ignorePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var patterns Excludes
+	lines := bufio.NewScanner(file)
+	for lines.Scan() {
+		line := strings.TrimRight(lines.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, strings.TrimPrefix(line, `\`))
+	}
+	if err := lines.Err(); err != nil {
+		return nil, err
+	}
+	if err := patterns.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", ignorePath, err)
+	}
+	return patterns, nil
+}
+
+// IgnorePatterns returns the patterns in effect for a scan of rootPath:
+// those of its IgnoreFile followed by patterns.
+//
+// A path excluded by either set is left out; since the last matching
+// pattern decides, a "!" entry in patterns can re-include a path the file
+// excludes, but not the other way around. When rootPath has an IgnoreFile,
+// the file itself is always excluded.
+//
+// Parameters:
+//   - rootPath: bundle root directory
+//   - patterns: additional patterns, e.g. from --exclude
+//
+// Returns:
+//   - Excludes: the combined patterns
+//   - error: if the IgnoreFile cannot be read or a pattern is malformed
+func IgnorePatterns(rootPath string, patterns []string) (Excludes, error) {
+	if err := Excludes(patterns).Validate(); err != nil {
+		return nil, err
+	}
+	fromFile, err := ReadIgnoreFile(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	if fromFile == nil {
+		return Excludes(patterns), nil
+	}
+	combined := append(fromFile, patterns...)
+	return append(combined, "/"+IgnoreFile), nil
+}
@@ -0,0 +1,75 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestReadIgnoreFile(t *testing.T) {
+	root := t.TempDir()
+	if patterns, err := ReadIgnoreFile(root); err != nil || patterns != nil {
+		t.Fatalf("ReadIgnoreFile() without file = %v, %v; want nil, nil", patterns, err)
+	}
+
+	content := "# comment\n\n*.log  \n!keep.log\r\ncache/\n\\#hash\n"
+	if err := os.WriteFile(filepath.Join(root, IgnoreFile), []byte(content), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	patterns, err := ReadIgnoreFile(root)
+	if err != nil {
+		t.Fatalf("ReadIgnoreFile failed: %v", err)
+	}
+	if got, want := strings.Join(patterns, ","), "*.log,!keep.log,cache/,#hash"; got != want {
+		t.Errorf("ReadIgnoreFile() = %q, want %q", got, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, IgnoreFile), []byte("[a-\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := ReadIgnoreFile(root); err == nil {
+		t.Errorf("malformed pattern accepted")
+	}
+}
+
+func TestScanDirectoryIgnoreFile(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.txt", "debug.log", "keep.log", "cache/x.bin", "sub/cache/y.bin", "sub/b.log", "tmp.bak"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, IgnoreFile), []byte("*.log\n!keep.log\ncache/\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	// Flags are combined with the file
+	files, err := ScanDirectoryExcluding(root, []string{"*.bak"})
+	if err != nil {
+		t.Fatalf("ScanDirectoryExcluding failed: %v", err)
+	}
+	var got []string
+	for _, f := range files {
+		rel, _ := filepath.Rel(root, f)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	sort.Strings(got)
+	if got, want := strings.Join(got, ","), "a.txt,keep.log"; got != want {
+		t.Errorf("ScanDirectoryExcluding() = %q, want %q", got, want)
+	}
+
+	// The ignore file stays excluded even when a flag re-includes it
+	patterns, err := IgnorePatterns(root, []string{"!" + IgnoreFile})
+	if err != nil {
+		t.Fatalf("IgnorePatterns failed: %v", err)
+	}
+	if !patterns.Match(IgnoreFile, false) {
+		t.Errorf("%s not excluded", IgnoreFile)
+	}
+}
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanDirectoryInfo(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{"a.txt": "abc", "sub/b.txt": "hello", ".bundle/META.json": "{}"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0640); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	files, err := ScanDirectoryInfo(root)
+	if err != nil {
+		t.Fatalf("ScanDirectoryInfo failed: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("ScanDirectoryInfo() = %v, want 2 files", files)
+	}
+	sizes := map[string]int64{}
+	for _, file := range files {
+		if file.Path != filepath.Join(root, file.RelPath) {
+			t.Errorf("Path = %s, want %s", file.Path, filepath.Join(root, file.RelPath))
+		}
+		if !file.Mode.IsRegular() || file.ModTime.IsZero() {
+			t.Errorf("%s: mode %v, modtime %v", file.RelPath, file.Mode, file.ModTime)
+		}
+		sizes[filepath.ToSlash(file.RelPath)] = file.Size
+	}
+	if sizes["a.txt"] != 3 || sizes["sub/b.txt"] != 5 {
+		t.Errorf("sizes = %v, want a.txt 3 and sub/b.txt 5", sizes)
+	}
+
+	// Check errors go to OnError, which can skip the path
+	var skipped []string
+	files, err = ScanDirectoryInfoWithOptions(root, ScanOptions{
+		Check: func(path string) error {
+			if filepath.Base(path) == "sub" {
+				return errors.New("rejected")
+			}
+			return nil
+		},
+		OnError: func(relPath string, err error) error {
+			skipped = append(skipped, relPath)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("ScanDirectoryInfoWithOptions failed: %v", err)
+	}
+	if len(files) != 1 || files[0].RelPath != "a.txt" || len(skipped) != 1 || skipped[0] != "sub" {
+		t.Errorf("files = %v, skipped = %v; want a.txt and sub", files, skipped)
+	}
+
+	// Without OnError the scan stops
+	if _, err := ScanDirectoryInfoWithOptions(root, ScanOptions{Check: func(string) error { return errors.New("rejected") }}); err == nil {
+		t.Error("Check error ignored without OnError")
+	}
+}
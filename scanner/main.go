@@ -23,6 +23,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // ScanDirectory walks a directory tree and returns all file paths, excluding .bundle/.
@@ -48,18 +49,166 @@ import (
 //   - []string: slice of absolute paths to regular files
 //   - error: if directory cannot be walked or accessed
 func ScanDirectory(rootPath string) ([]string, error) {
+	return ScanDirectoryExcluding(rootPath, nil)
+}
+
+// ScanDirectoryExcluding is like ScanDirectory but leaves out the paths
+// matching patterns.
+//
+// Patterns are gitignore-style globs matched against the path relative to
+// rootPath (see Excludes). Excluded directories are not descended into.
+// The patterns of an IgnoreFile in rootPath apply as well (see
+// IgnorePatterns).
+//
+// Example:
+//
+//	files, err := scanner.ScanDirectoryExcluding("/path/to/photos",
+//	    []string{".DS_Store", "Thumbs.db", "*~"})
+//
+// Parameters:
+//   - rootPath: absolute or relative path to the directory to scan
+//   - patterns: exclude patterns; nil excludes nothing
+//
+// Returns:
+//   - []string: slice of absolute paths to regular files
+//   - error: if a pattern is malformed or the directory cannot be walked
+func ScanDirectoryExcluding(rootPath string, patterns []string) ([]string, error) {
+	scanned, err := ScanDirectoryInfoWithOptions(rootPath, ScanOptions{Exclude: patterns})
+	if err != nil {
+		return nil, err
+	}
+
 	var files []string
+	for _, file := range scanned {
+		files = append(files, file.Path)
+	}
+	return files, nil
+}
 
-	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
+// ScannedFile is a file found by ScanDirectoryInfo, with the details the
+// walk already gathered.
+type ScannedFile struct {
+	Path    string      // Path rooted at the scan root, as ScanDirectory returns it
+	RelPath string      // Path relative to the scan root
+	Size    int64       // Size in bytes
+	Mode    os.FileMode // File mode; symlinks have os.ModeSymlink set
+	ModTime time.Time   // Modification time
+}
+
+// ScanOptions controls ScanDirectoryInfoWithOptions.
+type ScanOptions struct {
+	Exclude Excludes                              // Patterns to leave out, besides those of an IgnoreFile
+	Check   func(path string) error               // Called for every path below the root; an error is handled like a walk error
+	OnError func(relPath string, err error) error // Handles a walk error below the root; nil skips the path, otherwise the scan stops (default)
+}
+
+// ScanDirectoryInfo is like ScanDirectory but returns the size, mode and
+// modification time of each file along with its path, so callers need not
+// stat the files again.
+//
+// Example:
+//
+//	files, err := scanner.ScanDirectoryInfo("/path/to/photos")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, file := range files {
+//	    fmt.Printf("%10d  %s\n", file.Size, file.RelPath)
+//	}
+//
+// Parameters:
+//   - rootPath: absolute or relative path to the directory to scan
+//
+// Returns:
+//   - []ScannedFile: the files in walk order
+//   - error: if directory cannot be walked or accessed
+func ScanDirectoryInfo(rootPath string) ([]ScannedFile, error) {
+	return ScanDirectoryInfoWithOptions(rootPath, ScanOptions{})
+}
+
+// ScanDirectoryInfoWithOptions is like ScanDirectoryInfo with exclude
+// patterns and error handling set by opts.
+//
+// Paths matching opts.Exclude or the IgnoreFile in rootPath are left out,
+// as with ScanDirectoryExcluding. An error walking the root is always
+// returned; errors below it, including those of opts.Check, go to
+// opts.OnError, which can skip the path (and, for a directory, everything
+// in it) by returning nil.
+//
+// Example:
+//
+//	var skipped []string
+//	files, err := scanner.ScanDirectoryInfoWithOptions("/path/to/photos", scanner.ScanOptions{
+//	    Exclude: scanner.Excludes{"*.tmp"},
+//	    OnError: func(relPath string, err error) error {
+//	        skipped = append(skipped, relPath)
+//	        return nil
+//	    },
+//	})
+//
+// Parameters:
+//   - rootPath: absolute or relative path to the directory to scan
+//   - opts: exclude patterns and error handling
+//
+// Returns:
+//   - []ScannedFile: the files in walk order
+//   - error: if a pattern is malformed, the root cannot be walked or
+//     opts.OnError returns an error
+func ScanDirectoryInfoWithOptions(rootPath string, opts ScanOptions) ([]ScannedFile, error) {
+	excludes, err := IgnorePatterns(rootPath, opts.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	// fail hands an error below the root to opts.OnError
+	fail := func(path string, info os.FileInfo, err error) error {
+		if path == rootPath || opts.OnError == nil {
 			return err
 		}
+		relPath, relErr := filepath.Rel(rootPath, path)
+		if relErr != nil {
+			relPath = path
+		}
+		if err := opts.OnError(relPath, err); err != nil {
+			return err
+		}
+		if info != nil && info.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	}
+
+	var files []ScannedFile
+
+	err = filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return fail(path, info, err)
+		}
+		if opts.Check != nil && path != rootPath {
+			if err := opts.Check(path); err != nil {
+				return fail(path, info, err)
+			}
+		}
 
 		// Skip .bundle directory entirely
 		if info.IsDir() && info.Name() == ".bundle" {
 			return filepath.SkipDir
 		}
 
+		if path == rootPath {
+			return nil
+		}
+		relPath, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			return fail(path, info, err)
+		}
+		if excludes.Match(relPath, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		// Skip directories, only collect files
 		if info.IsDir() {
 			return nil
@@ -70,7 +219,13 @@ func ScanDirectory(rootPath string) ([]string, error) {
 			return nil
 		}
 
-		files = append(files, path)
+		files = append(files, ScannedFile{
+			Path:    path,
+			RelPath: relPath,
+			Size:    info.Size(),
+			Mode:    info.Mode(),
+			ModTime: info.ModTime(),
+		})
 		return nil
 	})
 
@@ -79,9 +234,15 @@ func ScanDirectory(rootPath string) ([]string, error) {
 
 // ScanWithSymlinks is like ScanDirectory but follows symlinks.
 //
-// It walks the directory tree and follows symbolic links to their targets.
-// Broken symlinks are skipped. The .bundle/ directory is still excluded.
-// Relative symlinks are resolved relative to their containing directory.
+// A symlink to a file is listed as a file, a symlink to a directory is
+// scanned as if the directory were part of the tree. Paths are those seen
+// through the tree, rooted at rootPath, so a file behind a symlink to a
+// directory outside the tree is listed below the link. Broken symlinks
+// and symlink loops are skipped. Directories and files are told apart by
+// device and inode: every directory is entered once, so a symlink back
+// into the tree cannot cause a cycle, and a file reachable through several
+// links is listed once, by the first path found. The .bundle/ directory
+// is still excluded.
 //
 // Example:
 //
@@ -90,54 +251,122 @@ func ScanDirectory(rootPath string) ([]string, error) {
 //	    log.Fatal(err)
 //	}
 //	for _, file := range files {
-//	    fmt.Println(file)  // May include files outside rootPath (via symlinks)
+//	    fmt.Println(file)  // May be below a symlinked directory
 //	}
 //
 // Parameters:
 //   - rootPath: absolute or relative path to the directory to scan
 //
 // Returns:
-//   - []string: slice of absolute paths to files (including symlink targets)
+//   - []string: paths of the files rooted at rootPath, without duplicates
 //   - error: if directory cannot be walked or accessed
 func ScanWithSymlinks(rootPath string) ([]string, error) {
-	var files []string
+	return ScanWithSymlinksExcluding(rootPath, nil)
+}
 
-	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+// ScanWithSymlinksExcluding is like ScanWithSymlinks but leaves out the
+// paths matching patterns.
+//
+// Patterns are matched against the path relative to rootPath as seen
+// through the tree, as with ScanDirectoryExcluding, and the patterns of an
+// IgnoreFile in rootPath apply as well. Excluded directories, linked or
+// not, are not descended into.
+//
+// Example:
+//
+//	files, err := scanner.ScanWithSymlinksExcluding("/path/to/photos",
+//	    []string{".DS_Store", "cache/"})
+//
+// Parameters:
+//   - rootPath: absolute or relative path to the directory to scan
+//   - patterns: exclude patterns; nil excludes nothing
+//
+// Returns:
+//   - []string: paths of the files rooted at rootPath, without duplicates
+//   - error: if a pattern is malformed or the directory cannot be walked
+func ScanWithSymlinksExcluding(rootPath string, patterns []string) ([]string, error) {
+	excludes, err := IgnorePatterns(rootPath, patterns)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	id, err := fileIDOf(rootPath, info)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &symlinkScan{
+		root:     rootPath,
+		excludes: excludes,
+		visited:  map[fileID]bool{id: true},
+		seen:     map[fileID]bool{},
+	}
+	if err := s.walk(rootPath, ""); err != nil {
+		return nil, err
+	}
+	return s.files, nil
+}
 
+// symlinkScan holds the state of a ScanWithSymlinks walk.
+type symlinkScan struct {
+	root     string
+	excludes Excludes
+	files    []string
+	visited  map[fileID]bool // Directories already entered
+	seen     map[fileID]bool // Files already listed
+}
+
+// walk scans dir, found at rel below the root, following the symlinks in
+// it.
+func (s *symlinkScan) walk(dir, rel string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
 		// Skip .bundle directory
-		if info.IsDir() && info.Name() == ".bundle" {
-			return filepath.SkipDir
+		if entry.Name() == ".bundle" {
+			continue
 		}
+		path := filepath.Join(dir, entry.Name())
+		relPath := filepath.Join(rel, entry.Name())
 
-		if info.IsDir() {
-			return nil
+		// Stat follows symlinks; broken ones and loops fail and are skipped
+		info, err := os.Stat(path)
+		if err != nil {
+			if entry.Type()&os.ModeSymlink != 0 {
+				continue
+			}
+			return err
 		}
-
-		if strings.Contains(path, ".bundle") {
-			return nil
+		if s.excludes.Match(relPath, info.IsDir()) {
+			continue
+		}
+		id, err := fileIDOf(path, info)
+		if err != nil {
+			return err
 		}
 
-		// Follow symlinks
-		if info.Mode()&os.ModeSymlink != 0 {
-			target, err := os.Readlink(path)
-			if err != nil {
-				// Skip broken symlinks
-				return nil
+		if info.IsDir() {
+			if s.visited[id] {
+				continue
 			}
-			// Make target absolute if relative
-			if !filepath.IsAbs(target) {
-				target = filepath.Join(filepath.Dir(path), target)
+			s.visited[id] = true
+			if err := s.walk(path, relPath); err != nil {
+				return err
 			}
-			files = append(files, target)
-		} else {
-			files = append(files, path)
+			continue
 		}
 
-		return nil
-	})
-
-	return files, err
+		if strings.Contains(relPath, ".bundle") || s.seen[id] {
+			continue
+		}
+		s.seen[id] = true
+		s.files = append(s.files, filepath.Join(s.root, relPath))
+	}
+	return nil
 }
@@ -0,0 +1,123 @@
+//go:build unix
+
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestScanWithSymlinks(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	outside := filepath.Join(base, "outside")
+	for name, content := range map[string]string{
+		"root/a.txt":         "a",
+		"root/sub/b.txt":     "b",
+		"outside/c.txt":      "c",
+		"outside/dir/d.txt":  "d",
+		"root/.bundle/x.txt": "x",
+	} {
+		path := filepath.Join(base, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	for link, target := range map[string]string{
+		"file-link":   "a.txt",                         // symlink to a file in the tree
+		"outside.txt": filepath.Join(outside, "c.txt"), // symlink to a file outside it
+		"dir-link":    filepath.Join(outside, "dir"),   // symlink to a directory
+		"broken":      "does-not-exist",                // broken symlink
+		"self":        "self",                          // self-referential loop
+		"sub/up":      "..",                            // loop back to the root
+	} {
+		if err := os.Symlink(target, filepath.Join(root, filepath.FromSlash(link))); err != nil {
+			t.Fatalf("symlink: %v", err)
+		}
+	}
+
+	files, err := ScanWithSymlinks(root)
+	if err != nil {
+		t.Fatalf("ScanWithSymlinks failed: %v", err)
+	}
+	var got []string
+	for _, file := range files {
+		rel, err := filepath.Rel(root, file)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			t.Errorf("path %q is not below %s", file, root)
+		}
+		got = append(got, filepath.ToSlash(rel))
+	}
+	sort.Strings(got)
+	// file-link is a.txt, listed once; sub/up leads back to the root
+	want := []string{"a.txt", "dir-link/d.txt", "outside.txt", "sub/b.txt"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ScanWithSymlinks() = %v, want %v", got, want)
+	}
+
+	// A root reached through a symlink gives the same result
+	alias := filepath.Join(base, "alias")
+	if err := os.Symlink(root, alias); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	again, err := ScanWithSymlinks(alias)
+	if err != nil {
+		t.Fatalf("ScanWithSymlinks failed: %v", err)
+	}
+	if len(again) != len(files) || !strings.HasPrefix(again[0], alias) {
+		t.Errorf("ScanWithSymlinks(alias) = %v, want %v below %s", again, files, alias)
+	}
+}
+
+func TestScanWithSymlinksExcluding(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "root")
+	outside := filepath.Join(base, "outside")
+	for name, content := range map[string]string{
+		"root/a.txt":          "a",
+		"root/a.tmp":          "tmp",
+		"root/" + IgnoreFile:  "cache/\n",
+		"outside/c.txt":       "c",
+		"outside/c.tmp":       "tmp",
+		"outside/cache/d.txt": "d",
+		"outside/keep/e.txt":  "e",
+	} {
+		path := filepath.Join(base, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "linked")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	files, err := ScanWithSymlinksExcluding(root, []string{"*.tmp", "linked/keep/"})
+	if err != nil {
+		t.Fatalf("ScanWithSymlinksExcluding failed: %v", err)
+	}
+	var got []string
+	for _, file := range files {
+		rel, _ := filepath.Rel(root, file)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	sort.Strings(got)
+	// The patterns apply below the link; cache/ comes from the ignore file,
+	// which is left out itself
+	want := []string{"a.txt", "linked/c.txt"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ScanWithSymlinksExcluding() = %v, want %v", got, want)
+	}
+
+	if _, err := ScanWithSymlinksExcluding(root, []string{"["}); err == nil {
+		t.Error("ScanWithSymlinksExcluding() with a malformed pattern succeeded")
+	}
+}
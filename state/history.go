@@ -0,0 +1,81 @@
+package state
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// defaultHistoryLength is the number of verifications kept in History
+// when `verify_history_length` is not configured.
+const defaultHistoryLength = 20
+
+// VerificationEvent is one verification of a bundle, kept in
+// State.History.
+//
+// Example JSON:
+//
+//	{"time": "2024-01-15T10:30:00Z", "verified": false, "corrupted": 2}
+type VerificationEvent struct {
+	Time      time.Time `json:"time"`      // When the verification ran
+	Verified  bool      `json:"verified"`  // Whether the bundle was intact
+	Corrupted int       `json:"corrupted"` // Number of missing or changed files
+}
+
+// HistoryLength returns the maximum number of verifications kept in
+// State.History.
+//
+// It reads the `verify_history_length` configuration key, defaulting to
+// 20 when unset or negative; 0 keeps no history.
+//
+// Returns:
+//   - int: maximum history length
+func HistoryLength() int {
+	if viper.IsSet("verify_history_length") {
+		if n := viper.GetInt("verify_history_length"); n >= 0 {
+			return n
+		}
+	}
+	return defaultHistoryLength
+}
+
+// RecordVerification updates the verification status and appends the
+// verification to History.
+//
+// Verified and LastChecked are set as by MarkVerified. The oldest events
+// are dropped once History exceeds HistoryLength. Call Save() to persist
+// the changes to disk.
+//
+// Example:
+//
+//	st, _ := state.Load("/path/to/bundle")
+//	st.RecordVerification(false, 2, time.Now())
+//	st.Save("/path/to/bundle")
+//
+// Parameters:
+//   - verified: true if integrity check passed, false otherwise
+//   - corrupted: number of missing or changed files found
+//   - timestamp: time of the verification check
+func (s *State) RecordVerification(verified bool, corrupted int, timestamp time.Time) {
+	s.Verified = verified
+	s.LastChecked = timestamp
+	s.History = append(s.History, VerificationEvent{Time: timestamp, Verified: verified, Corrupted: corrupted})
+	if limit := HistoryLength(); len(s.History) > limit {
+		s.History = append([]VerificationEvent{}, s.History[len(s.History)-limit:]...)
+	}
+}
+
+// RecentHistory returns the last n verifications, oldest first.
+//
+// Parameters:
+//   - n: maximum number of events; all events when negative
+//
+// Returns:
+//   - []VerificationEvent: the events, never nil
+func (s *State) RecentHistory(n int) []VerificationEvent {
+	events := s.History
+	if n >= 0 && len(events) > n {
+		events = events[len(events)-n:]
+	}
+	return append([]VerificationEvent{}, events...)
+}
@@ -0,0 +1,68 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestRecordVerification(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("verify_history_length", 3)
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".bundle"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	// STATE.json from before the history existed
+	data := []byte(`{"verified": true, "last_checked": "2024-01-15T10:30:00Z", "replicas": [], "size_bytes": 3}`)
+	if err := os.WriteFile(filepath.Join(dir, ".bundle", "STATE.json"), data, 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	st, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if st.History == nil || len(st.History) != 0 {
+		t.Fatalf("History = %v, want empty", st.History)
+	}
+
+	start := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		st.RecordVerification(i < 3, i, start.Add(time.Duration(i)*time.Hour))
+	}
+	if len(st.History) != 3 {
+		t.Fatalf("len(History) = %d, want 3", len(st.History))
+	}
+	if first := st.History[0]; first.Corrupted != 2 || !first.Verified {
+		t.Errorf("oldest kept event = %+v, want the third", first)
+	}
+	if st.Verified || !st.LastChecked.Equal(start.Add(4*time.Hour)) {
+		t.Errorf("Verified = %v, LastChecked = %v; want the last event", st.Verified, st.LastChecked)
+	}
+
+	if err := st.Save(dir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	recent := loaded.RecentHistory(2)
+	if len(recent) != 2 || recent[1].Corrupted != 4 || recent[1].Verified {
+		t.Errorf("RecentHistory(2) = %+v", recent)
+	}
+	if all := loaded.RecentHistory(-1); len(all) != 3 {
+		t.Errorf("RecentHistory(-1) = %d events, want 3", len(all))
+	}
+
+	// MarkVerified appends as well
+	loaded.MarkVerified(true, start.Add(5*time.Hour))
+	if last := loaded.History[len(loaded.History)-1]; !last.Verified || last.Corrupted != 0 {
+		t.Errorf("MarkVerified event = %+v", last)
+	}
+}
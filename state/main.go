@@ -40,6 +40,7 @@ import (
 //   - LastChecked: timestamp of last verification
 //   - Replicas: URIs of known bundle replicas
 //   - SizeBytes: total size of all files (excluding .bundle/)
+//   - History: recent verifications, oldest first, at most HistoryLength
 //
 // Example JSON:
 //
@@ -47,19 +48,24 @@ import (
 //	  "verified": true,
 //	  "last_checked": "2024-01-15T10:30:00Z",
 //	  "replicas": ["s3://bucket/path", "/mnt/backup/bundle"],
-//	  "size_bytes": 1024000
+//	  "size_bytes": 1024000,
+//	  "history": [
+//	    {"time": "2024-01-15T10:30:00Z", "verified": true, "corrupted": 0}
+//	  ]
 //	}
 type State struct {
-	Verified    bool      `json:"verified"`     // Last verification result
-	LastChecked time.Time `json:"last_checked"` // Last verification timestamp
-	Replicas    []string  `json:"replicas"`     // Known replica locations
-	SizeBytes   int64     `json:"size_bytes"`   // Total bundle size (excluding .bundle/)
+	Verified    bool                `json:"verified"`     // Last verification result
+	LastChecked time.Time           `json:"last_checked"` // Last verification timestamp
+	Replicas    []string            `json:"replicas"`     // Known replica locations
+	SizeBytes   int64               `json:"size_bytes"`   // Total bundle size (excluding .bundle/)
+	History     []VerificationEvent `json:"history"`      // Recent verifications
 }
 
 // Load reads state from .bundle/STATE.json.
 //
 // It parses the JSON file and returns a State struct. The file must exist
-// and contain valid JSON matching the State structure.
+// and contain valid JSON matching the State structure. Files written
+// before History existed load with an empty history.
 //
 // Example:
 //
@@ -88,6 +94,12 @@ func Load(bundlePath string) (*State, error) {
 	if err := json.Unmarshal(data, &state); err != nil {
 		return nil, err
 	}
+	if state.Replicas == nil {
+		state.Replicas = []string{}
+	}
+	if state.History == nil {
+		state.History = []VerificationEvent{}
+	}
 
 	return &state, nil
 }
@@ -128,8 +140,10 @@ func (s *State) Save(bundlePath string) error {
 
 // MarkVerified updates verification status and timestamp.
 //
-// It sets the Verified field and updates LastChecked to the provided timestamp.
-// Call Save() to persist the changes to disk.
+// It sets the Verified field and updates LastChecked to the provided
+// timestamp, and appends the verification to History without a count of
+// corrupted files (see RecordVerification). Call Save() to persist the
+// changes to disk.
 //
 // Example:
 //
@@ -141,8 +155,7 @@ func (s *State) Save(bundlePath string) error {
 //   - verified: true if integrity check passed, false otherwise
 //   - timestamp: time of the verification check
 func (s *State) MarkVerified(verified bool, timestamp time.Time) {
-	s.Verified = verified
-	s.LastChecked = timestamp
+	s.RecordVerification(verified, 0, timestamp)
 }
 
 // UpdateSize sets the total bundle size.
@@ -186,3 +199,31 @@ func (s *State) AddReplica(uri string) {
 	}
 	s.Replicas = append(s.Replicas, uri)
 }
+
+// RemoveReplica removes a replica location, e.g. when a backup location is
+// decommissioned.
+//
+// Removing a URI that is not listed is a no-op. Call Save() to persist the
+// changes.
+//
+// Example:
+//
+//	st, _ := state.Load("/path/to/bundle")
+//	if st.RemoveReplica("/mnt/backup/bundle") {
+//	    st.Save("/path/to/bundle")
+//	}
+//
+// Parameters:
+//   - uri: location identifier for the replica
+//
+// Returns:
+//   - bool: true if the replica was present and removed
+func (s *State) RemoveReplica(uri string) bool {
+	for i, existing := range s.Replicas {
+		if existing == uri {
+			s.Replicas = append(s.Replicas[:i], s.Replicas[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package state
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// replicaSchemes are the URI schemes accepted for replica locations.
+var replicaSchemes = map[string]bool{
+	"file":  true,
+	"s3":    true,
+	"http":  true,
+	"https": true,
+}
+
+// NormalizeReplicaURI checks a replica location and returns it in canonical
+// form.
+//
+// Surrounding whitespace is trimmed. A bare absolute path is cleaned, e.g.
+// "/mnt/backup//photos/" becomes "/mnt/backup/photos". Other values must
+// be a URI with a file, s3, http or https scheme followed by "://"; the
+// scheme is lowercased. s3, http and https URIs need a host or bucket,
+// file URIs an absolute path.
+//
+// Example:
+//
+//	uri, err := state.NormalizeReplicaURI(" S3://bucket/photos ")
+//	// uri = "s3://bucket/photos"
+//	_, err = state.NormalizeReplicaURI("s3:/bucket")
+//	// err: invalid replica URI 's3:/bucket': expected s3://
+//
+// Parameters:
+//   - uri: replica location as given by the user
+//
+// Returns:
+//   - string: the normalized location
+//   - error: if uri is empty, relative, or malformed
+func NormalizeReplicaURI(uri string) (string, error) {
+	uri = strings.TrimSpace(uri)
+	if uri == "" {
+		return "", fmt.Errorf("replica URI cannot be empty")
+	}
+	if strings.IndexFunc(uri, unicode.IsControl) >= 0 {
+		return "", fmt.Errorf("invalid replica URI %q: contains control characters", uri)
+	}
+
+	if strings.HasPrefix(uri, "/") {
+		return filepath.Clean(uri), nil
+	}
+
+	scheme, rest, ok := strings.Cut(uri, ":")
+	if !ok || scheme == "" {
+		return "", fmt.Errorf("invalid replica URI '%s': must be an absolute path or a file, s3, http or https URI", uri)
+	}
+	scheme = strings.ToLower(scheme)
+	if !replicaSchemes[scheme] {
+		return "", fmt.Errorf("invalid replica URI '%s': unsupported scheme '%s'", uri, scheme)
+	}
+	if !strings.HasPrefix(rest, "//") {
+		return "", fmt.Errorf("invalid replica URI '%s': expected %s://", uri, scheme)
+	}
+
+	normalized := scheme + ":" + rest
+	parsed, err := url.Parse(normalized)
+	if err != nil {
+		return "", fmt.Errorf("invalid replica URI '%s': %w", uri, err)
+	}
+	if scheme == "file" {
+		if !strings.HasPrefix(parsed.Path, "/") {
+			return "", fmt.Errorf("invalid replica URI '%s': file URI needs an absolute path", uri)
+		}
+	} else if parsed.Host == "" {
+		return "", fmt.Errorf("invalid replica URI '%s': missing host", uri)
+	}
+
+	return normalized, nil
+}
+
+// AddReplicaValidated normalizes uri with NormalizeReplicaURI and appends
+// it if not already present.
+//
+// Unlike AddReplica, malformed locations are rejected instead of stored.
+// Call Save() to persist the changes.
+//
+// Example:
+//
+//	st, _ := state.Load("/path/to/bundle")
+//	if err := st.AddReplicaValidated("s3://bucket/backup/bundle"); err != nil {
+//	    log.Fatal(err)
+//	}
+//	st.Save("/path/to/bundle")
+//
+// Parameters:
+//   - uri: location identifier for the replica
+//
+// Returns:
+//   - error: if uri is not a valid replica location
+func (s *State) AddReplicaValidated(uri string) error {
+	normalized, err := NormalizeReplicaURI(uri)
+	if err != nil {
+		return err
+	}
+	s.AddReplica(normalized)
+	return nil
+}
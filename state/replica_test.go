@@ -0,0 +1,109 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReplicaRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".bundle"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	st := &State{Replicas: []string{}, History: []VerificationEvent{}}
+	st.AddReplica("s3://bucket/path")
+	st.AddReplica("/mnt/backup/bundle")
+	st.AddReplica("s3://bucket/path")
+	if err := st.Save(dir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	want := []string{"s3://bucket/path", "/mnt/backup/bundle"}
+	if !reflect.DeepEqual(loaded.Replicas, want) {
+		t.Fatalf("Replicas = %v, want %v", loaded.Replicas, want)
+	}
+
+	if loaded.RemoveReplica("s3://elsewhere") {
+		t.Error("RemoveReplica of unknown URI returned true")
+	}
+	if !loaded.RemoveReplica("s3://bucket/path") {
+		t.Error("RemoveReplica of known URI returned false")
+	}
+	if err := loaded.Save(dir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	want = []string{"/mnt/backup/bundle"}
+	if !reflect.DeepEqual(loaded.Replicas, want) {
+		t.Fatalf("Replicas = %v, want %v", loaded.Replicas, want)
+	}
+
+	loaded.RemoveReplica("/mnt/backup/bundle")
+	if loaded.Replicas == nil || len(loaded.Replicas) != 0 {
+		t.Errorf("Replicas = %v, want empty", loaded.Replicas)
+	}
+}
+
+func TestNormalizeReplicaURI(t *testing.T) {
+	valid := map[string]string{
+		"s3://bucket/photos":     "s3://bucket/photos",
+		" S3://bucket/photos\n":  "s3://bucket/photos",
+		"https://host/backup":    "https://host/backup",
+		"http://host:8080/x":     "http://host:8080/x",
+		"file:///mnt/backup":     "file:///mnt/backup",
+		"/mnt/backup//photos/":   "/mnt/backup/photos",
+		"FILE://localhost/mnt/x": "file://localhost/mnt/x",
+	}
+	for in, want := range valid {
+		got, err := NormalizeReplicaURI(in)
+		if err != nil {
+			t.Errorf("NormalizeReplicaURI(%q) failed: %v", in, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("NormalizeReplicaURI(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"   ",
+		"s3:/bucket",
+		"s3://",
+		"https:///path",
+		"ftp://host/x",
+		"relative/path",
+		"file://relative",
+		"/mnt/a\x00b",
+	}
+	for _, in := range invalid {
+		if got, err := NormalizeReplicaURI(in); err == nil {
+			t.Errorf("NormalizeReplicaURI(%q) = %q, want error", in, got)
+		}
+	}
+
+	st := &State{Replicas: []string{}}
+	if err := st.AddReplicaValidated("s3:/bucket"); err == nil {
+		t.Error("AddReplicaValidated accepted a malformed URI")
+	}
+	if err := st.AddReplicaValidated(" s3://bucket "); err != nil {
+		t.Fatalf("AddReplicaValidated failed: %v", err)
+	}
+	if err := st.AddReplicaValidated("s3://bucket"); err != nil {
+		t.Fatalf("AddReplicaValidated failed: %v", err)
+	}
+	if !reflect.DeepEqual(st.Replicas, []string{"s3://bucket"}) {
+		t.Errorf("Replicas = %v, want [s3://bucket]", st.Replicas)
+	}
+}
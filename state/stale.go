@@ -0,0 +1,50 @@
+package state
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// defaultVerifyMaxAge is the age after which a verification is stale when
+// `verify.max_age` is not configured.
+const defaultVerifyMaxAge = 30 * 24 * time.Hour
+
+// VerifyMaxAge returns the age after which a bundle should be verified
+// again.
+//
+// It reads the `verify.max_age` configuration key (a duration such as
+// "720h"), defaulting to 30 days when unset or not positive.
+//
+// Returns:
+//   - time.Duration: maximum verification age
+func VerifyMaxAge() time.Duration {
+	if d := viper.GetDuration("verify.max_age"); d > 0 {
+		return d
+	}
+	return defaultVerifyMaxAge
+}
+
+// IsStale reports whether the bundle was last verified more than maxAge
+// ago.
+//
+// A bundle that was never verified is always stale.
+//
+// Example:
+//
+//	st, _ := state.Load("/path/to/bundle")
+//	if st.IsStale(state.VerifyMaxAge()) {
+//	    fmt.Println("Verification is overdue")
+//	}
+//
+// Parameters:
+//   - maxAge: maximum time since LastChecked
+//
+// Returns:
+//   - bool: true if LastChecked is zero or older than maxAge
+func (s *State) IsStale(maxAge time.Duration) bool {
+	if s.LastChecked.IsZero() {
+		return true
+	}
+	return time.Since(s.LastChecked) > maxAge
+}
@@ -0,0 +1,35 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestIsStale(t *testing.T) {
+	st := &State{}
+	if !st.IsStale(time.Hour) {
+		t.Error("never verified bundle is not stale")
+	}
+
+	st.LastChecked = time.Now().Add(-2 * time.Hour)
+	if !st.IsStale(time.Hour) {
+		t.Error("bundle checked 2h ago is not stale with a 1h max age")
+	}
+	if st.IsStale(3 * time.Hour) {
+		t.Error("bundle checked 2h ago is stale with a 3h max age")
+	}
+}
+
+func TestVerifyMaxAge(t *testing.T) {
+	defer viper.Reset()
+
+	if got := VerifyMaxAge(); got != defaultVerifyMaxAge {
+		t.Errorf("VerifyMaxAge() = %v, want %v", got, defaultVerifyMaxAge)
+	}
+	viper.Set("verify.max_age", "72h")
+	if got := VerifyMaxAge(); got != 72*time.Hour {
+		t.Errorf("VerifyMaxAge() = %v, want 72h", got)
+	}
+}
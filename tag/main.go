@@ -8,6 +8,10 @@
 //   - Must match pattern: ^[a-z0-9._-]{1,64}$
 //   - Automatically deduplicated
 //
+// With `tags.allow_unicode` set, letters and digits of any script are
+// allowed too; tags are then NFC-normalized and casefolded (see
+// AllowUnicode).
+//
 // Example usage:
 //
 //	// Load tags
@@ -48,6 +52,9 @@ var tagPattern = regexp.MustCompile(`^[a-z0-9._-]{1,64}$`)
 //   - Only lowercase letters, digits, dots, underscores, hyphens
 //   - No whitespace allowed
 //
+// When AllowUnicode is true, letters and digits of any script are allowed
+// and the tag is NFC-normalized and casefolded instead of lowercased.
+//
 // Example:
 //
 //	tag, ok := normalizeTag("Vacation")
@@ -76,6 +83,9 @@ func normalizeTag(s string) (string, bool) {
 	if strings.ContainsAny(t, " \t\n\r") {
 		return "", false
 	}
+	if AllowUnicode() {
+		return normalizeUnicodeTag(t)
+	}
 	// Validate allowed characters and max length
 	if !tagPattern.MatchString(t) {
 		return "", false
@@ -134,23 +144,36 @@ func Load(bundlePath string) (*Tags, error) {
 		return nil, err
 	}
 
-	lines := strings.Split(string(data), "\n")
+	tags, _, _ := parseTags(string(data))
+	return &Tags{Tags: tags}, nil
+}
+
+// parseTags parses TAGS.txt content into unique normalized tags.
+//
+// It also reports how many entries were rewritten by normalization (for
+// example lowercased) and how many were dropped as invalid or duplicate.
+// Blank lines are ignored and not counted.
+func parseTags(data string) ([]string, int, int) {
 	tags := []string{}
 	tagSet := make(map[string]bool)
-	for _, line := range lines {
+	changed, dropped := 0, 0
+	for _, line := range strings.Split(data, "\n") {
 		trimmed := strings.TrimSpace(line)
 		if trimmed == "" {
 			continue
 		}
-		if nt, ok := normalizeTag(trimmed); ok {
-			if !tagSet[nt] {
-				tags = append(tags, nt)
-				tagSet[nt] = true
-			}
+		nt, ok := normalizeTag(trimmed)
+		if !ok || tagSet[nt] {
+			dropped++
+			continue
 		}
+		if nt != line {
+			changed++
+		}
+		tags = append(tags, nt)
+		tagSet[nt] = true
 	}
-
-	return &Tags{Tags: tags}, nil
+	return tags, changed, dropped
 }
 
 // Save writes tags to .bundle/TAGS.txt in sorted order.
@@ -238,13 +261,16 @@ func (t *Tags) Add(newTags ...string) {
 // Example:
 //
 //	tags := &tag.Tags{Tags: []string{"travel", "photos", "vacation"}}
-//	tags.Remove("Photos", "VACATION")  // Normalized to lowercase
+//	removed := tags.Remove("Photos", "VACATION", "europe")  // Normalized to lowercase
 //	tags.Save("/path/to/bundle")
-//	// tags.Tags = ["travel"]
+//	// tags.Tags = ["travel"], removed = ["photos", "vacation"]
 //
 // Parameters:
 //   - removeTags: one or more tag strings to remove
-func (t *Tags) Remove(removeTags ...string) {
+//
+// Returns:
+//   - []string: sorted tags that were present and removed
+func (t *Tags) Remove(removeTags ...string) []string {
 	// Use struct{} for sets - more memory efficient
 	removeSet := make(map[string]struct{}, len(removeTags))
 	for _, tag := range removeTags {
@@ -255,12 +281,52 @@ func (t *Tags) Remove(removeTags ...string) {
 
 	// Pre-allocate with capacity hint to avoid reallocations
 	filtered := make([]string, 0, len(t.Tags))
+	removed := []string{}
 	for _, tag := range t.Tags {
-		if _, shouldRemove := removeSet[tag]; !shouldRemove {
+		if _, shouldRemove := removeSet[tag]; shouldRemove {
+			removed = append(removed, tag)
+		} else {
 			filtered = append(filtered, tag)
 		}
 	}
 	t.Tags = filtered
+	sort.Strings(removed)
+	return removed
+}
+
+// Has reports whether the collection contains tag.
+//
+// The tag is normalized first, so Has("Travel") is true when "travel" is
+// stored; invalid and empty tags are never present. The stored tags are
+// compared normalized as well, so a Tags value built by hand matches the
+// same way as one from Load.
+//
+// Example:
+//
+//	tags := &tag.Tags{Tags: []string{"travel", "photos"}}
+//	tags.Has("Travel")    // true
+//	tags.Has("vacation")  // false
+//	tags.Has("my tag")    // false (invalid)
+//
+// Parameters:
+//   - tag: raw tag string
+//
+// Returns:
+//   - bool: true if the normalized tag is present
+func (t *Tags) Has(tag string) bool {
+	nt, ok := normalizeTag(tag)
+	if !ok {
+		return false
+	}
+	for _, existing := range t.Tags {
+		if existing == nt {
+			return true
+		}
+		if ne, ok := normalizeTag(existing); ok && ne == nt {
+			return true
+		}
+	}
+	return false
 }
 
 // List returns sorted tag list.
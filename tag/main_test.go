@@ -5,6 +5,8 @@ import (
     "path/filepath"
     "strings"
     "testing"
+
+    "github.com/spf13/viper"
 )
 
 func TestNormalizeTag(t *testing.T) {
@@ -51,7 +53,10 @@ func TestAddListRemove(t *testing.T) {
     }
 
     // Remove with different case and whitespace
-    tgs.Remove(" PHOTOS ")
+    removed := tgs.Remove(" PHOTOS ", "absent")
+    if len(removed) != 1 || removed[0] != "photos" {
+        t.Fatalf("Remove returned %v, want [photos]", removed)
+    }
     got2 := tgs.List()
     want2 := []string{"travel", "upper"}
     if len(got2) != len(want2) {
@@ -150,3 +155,134 @@ func TestEdgeCases(t *testing.T) {
         t.Fatalf("Remove invalid changed tags: got=%v expected=%v", after, expected)
     }
 }
+
+func TestNormalize(t *testing.T) {
+    dir := t.TempDir()
+    bundleDir := filepath.Join(dir, ".bundle")
+    if err := os.MkdirAll(bundleDir, 0755); err != nil {
+        t.Fatalf("mkdir .bundle: %v", err)
+    }
+
+    // Photos and " travel " are rewritten; the invalid tag and duplicate are dropped
+    data := "Photos\n travel \nINVALID TAG\nphotos\nzoo\n\n"
+    tagsFile := filepath.Join(bundleDir, "TAGS.txt")
+    if err := os.WriteFile(tagsFile, []byte(data), 0644); err != nil {
+        t.Fatalf("write tags: %v", err)
+    }
+
+    result, err := Normalize(dir)
+    if err != nil {
+        t.Fatalf("Normalize failed: %v", err)
+    }
+    if result.Changed != 2 || result.Dropped != 2 {
+        t.Errorf("Normalize changed=%d dropped=%d, want 2 and 2", result.Changed, result.Dropped)
+    }
+
+    b, err := os.ReadFile(tagsFile)
+    if err != nil {
+        t.Fatalf("Read saved tags: %v", err)
+    }
+    if got, want := string(b), "photos\ntravel\nzoo\n"; got != want {
+        t.Errorf("TAGS.txt = %q, want %q", got, want)
+    }
+
+    // A canonical file is left unchanged
+    result, err = Normalize(dir)
+    if err != nil {
+        t.Fatalf("Normalize again failed: %v", err)
+    }
+    if result.Changed != 0 || result.Dropped != 0 {
+        t.Errorf("second Normalize changed=%d dropped=%d, want 0 and 0", result.Changed, result.Dropped)
+    }
+}
+
+func TestUnicodeTags(t *testing.T) {
+    defer viper.Reset()
+    viper.Set("tags.allow_unicode", true)
+
+    cases := []struct{
+        in string
+        want string
+        ok bool
+    }{
+        {"Café", "café", true},
+        {"café", "café", true},
+        {"東京", "東京", true},
+        {"Straße", "strasse", true},
+        {"ΣΟΦΊΑ", "σοφία", true},
+        {"good-tag.123", "good-tag.123", true},
+        {"two words", "", false},
+        {"🔥", "", false},
+        {strings.Repeat("é", 64), strings.Repeat("é", 64), true},
+        {strings.Repeat("é", 65), "", false},
+    }
+    for _, c := range cases {
+        got, ok := normalizeTag(c.in)
+        if ok != c.ok || got != c.want {
+            t.Fatalf("normalizeTag(%q) = %q, %v, want %q, %v", c.in, got, ok, c.want, c.ok)
+        }
+    }
+
+    dir := t.TempDir()
+    if err := os.MkdirAll(filepath.Join(dir, ".bundle"), 0755); err != nil {
+        t.Fatalf("mkdir .bundle: %v", err)
+    }
+    tgs := &Tags{Tags: []string{}}
+    tgs.Add("Café", "café", "東京", "travel")
+    if err := tgs.Save(dir); err != nil {
+        t.Fatalf("Save: %v", err)
+    }
+    loaded, err := Load(dir)
+    if err != nil {
+        t.Fatalf("Load: %v", err)
+    }
+    got := loaded.List()
+    want := []string{"café", "travel", "東京"}
+    if strings.Join(got, ",") != strings.Join(want, ",") {
+        t.Fatalf("round trip = %v, want %v", got, want)
+    }
+
+    // The default policy stays ASCII-only
+    viper.Set("tags.allow_unicode", false)
+    if _, ok := normalizeTag("café"); ok {
+        t.Fatalf("expected unicode tag to be invalid by default")
+    }
+}
+
+func TestHas(t *testing.T) {
+    tgs := &Tags{Tags: []string{"travel", "photos"}}
+
+    if !tgs.Has("Travel") || !tgs.Has(" photos ") {
+        t.Fatalf("Has should match stored tags case-insensitively")
+    }
+    if tgs.Has("vacation") {
+        t.Fatalf("Has(vacation) = true, want false")
+    }
+    if tgs.Has("") || tgs.Has("my tag") {
+        t.Fatalf("Has should be false for empty and invalid tags")
+    }
+
+    // Has follows changes to the collection
+    tgs.Add("Vacation")
+    if !tgs.Has("vacation") {
+        t.Fatalf("Has(vacation) = false after Add")
+    }
+    tgs.Remove("travel")
+    if tgs.Has("travel") {
+        t.Fatalf("Has(travel) = true after Remove")
+    }
+    tgs.Tags = []string{"europe"}
+    if !tgs.Has("europe") || tgs.Has("photos") {
+        t.Fatalf("Has did not follow a new Tags slice")
+    }
+    tgs.Tags[0] = "asia"
+    if !tgs.Has("asia") || tgs.Has("europe") {
+        t.Fatalf("Has did not follow an element changed in place")
+    }
+
+    // Stored tags need not be normalized
+    tgs.Tags = []string{"Asia"}
+    if !tgs.Has("asia") {
+        t.Fatalf("Has(asia) = false for stored Asia")
+    }
+}
@@ -0,0 +1,144 @@
+package tag
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Mapping maps bundle checksums to their tags.
+//
+// It is the interchange format of `bundle tag export` and `bundle tag
+// import`, written either as a JSON object or as CSV with a
+// "checksum,tags" header and the tags of a bundle separated by spaces.
+//
+// Example JSON:
+//
+//	{
+//	  "e3b0c442...": ["photos", "travel"],
+//	  "a1b2c3d4...": []
+//	}
+//
+// Example CSV:
+//
+//	checksum,tags
+//	e3b0c442...,photos travel
+//	a1b2c3d4...,
+type Mapping map[string][]string
+
+// Valid normalizes s and reports whether it is an acceptable tag.
+//
+// Example:
+//
+//	tag, ok := tag.Valid("  Photos ")  // "photos", true
+//	_, ok = tag.Valid("two words")     // "", false
+//
+// Parameters:
+//   - s: raw tag string
+//
+// Returns:
+//   - string: normalized tag
+//   - bool: true if s is a valid tag
+func Valid(s string) (string, bool) {
+	return normalizeTag(s)
+}
+
+// WriteMapping writes m to w as JSON, or as CSV when asCSV is set.
+//
+// CSV rows are sorted by checksum; tags are written as given.
+//
+// Parameters:
+//   - w: destination writer
+//   - m: mapping to write
+//   - asCSV: write CSV instead of JSON
+//
+// Returns:
+//   - error: if encoding or writing fails
+func WriteMapping(w io.Writer, m Mapping, asCSV bool) error {
+	if !asCSV {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(m)
+	}
+
+	checksums := make([]string, 0, len(m))
+	for checksum := range m {
+		checksums = append(checksums, checksum)
+	}
+	sort.Strings(checksums)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"checksum", "tags"}); err != nil {
+		return err
+	}
+	for _, checksum := range checksums {
+		if err := writer.Write([]string{checksum, strings.Join(m[checksum], " ")}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadMapping reads a mapping written by WriteMapping.
+//
+// The format is detected from the content: input starting with "{" is read
+// as JSON, anything else as CSV. A CSV header row is optional.
+//
+// Example:
+//
+//	file, _ := os.Open("tags.csv")
+//	defer file.Close()
+//	m, err := tag.ReadMapping(file)
+//
+// Parameters:
+//   - r: source reader
+//
+// Returns:
+//   - Mapping: checksums and their (unvalidated) tags
+//   - error: if the input cannot be parsed
+func ReadMapping(r io.Reader) (Mapping, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	m := Mapping{}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return m, nil
+	}
+	if data[0] == '{' {
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("invalid tag mapping json: %w", err)
+		}
+		return m, nil
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tag mapping csv: %w", err)
+	}
+	for i, row := range rows {
+		checksum := strings.TrimSpace(row[0])
+		if i == 0 && strings.EqualFold(checksum, "checksum") {
+			continue
+		}
+		if checksum == "" {
+			continue
+		}
+		if _, ok := m[checksum]; !ok {
+			m[checksum] = []string{}
+		}
+		if len(row) > 1 {
+			m[checksum] = append(m[checksum], strings.Fields(row[1])...)
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,24 @@
+package tag
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestMapping_RoundTrip(t *testing.T) {
+	m := Mapping{"aaa": {"photos", "travel"}, "bbb": {}}
+	for _, asCSV := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := WriteMapping(&buf, m, asCSV); err != nil {
+			t.Fatalf("WriteMapping(csv=%v) failed: %v", asCSV, err)
+		}
+		got, err := ReadMapping(&buf)
+		if err != nil {
+			t.Fatalf("ReadMapping(csv=%v) failed: %v", asCSV, err)
+		}
+		if !reflect.DeepEqual(got, m) {
+			t.Errorf("round trip (csv=%v) = %v, want %v", asCSV, got, m)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package tag
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// NormalizeResult reports what Normalize changed in TAGS.txt.
+type NormalizeResult struct {
+	Tags    []string `json:"tags"`    // Tags after normalization, sorted
+	Changed int      `json:"changed"` // Entries rewritten (case, whitespace)
+	Dropped int      `json:"dropped"` // Invalid or duplicate entries removed
+}
+
+// Normalize canonicalizes .bundle/TAGS.txt in place.
+//
+// Load already normalizes tags in memory, but a legacy or hand-edited file
+// keeps its mixed case, duplicates and invalid entries until the next Save.
+// Normalize loads the file and immediately saves it in canonical form
+// (lowercase, unique, sorted, one per line). A missing TAGS.txt is left
+// alone.
+//
+// Example:
+//
+//	result, err := tag.Normalize("/path/to/bundle")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("%d changed, %d dropped\n", result.Changed, result.Dropped)
+//
+// Parameters:
+//   - bundlePath: absolute or relative path to the bundle directory
+//
+// Returns:
+//   - *NormalizeResult: resulting tags and change counts
+//   - error: if TAGS.txt cannot be read or written
+func Normalize(bundlePath string) (*NormalizeResult, error) {
+	tagsFile := filepath.Join(bundlePath, ".bundle", "TAGS.txt")
+	data, err := os.ReadFile(tagsFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &NormalizeResult{Tags: []string{}}, nil
+		}
+		return nil, err
+	}
+
+	tags, changed, dropped := parseTags(string(data))
+	t := &Tags{Tags: tags}
+	if err := t.Save(bundlePath); err != nil {
+		return nil, err
+	}
+
+	return &NormalizeResult{Tags: t.List(), Changed: changed, Dropped: dropped}, nil
+}
@@ -0,0 +1,41 @@
+package tag
+
+import (
+	"regexp"
+
+	"github.com/spf13/viper"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// unicodeTagPattern is the tag pattern when Unicode tags are allowed: 1-64
+// letters, combining marks, digits, dots, underscores or hyphens from any
+// script.
+var unicodeTagPattern = regexp.MustCompile(`^[\p{L}\p{M}\p{N}._-]{1,64}$`)
+
+// AllowUnicode reports whether tags may contain non-ASCII letters and
+// digits, such as "café" or "東京".
+//
+// It reads the `tags.allow_unicode` configuration key and defaults to
+// false, keeping tags ASCII-only.
+//
+// Returns:
+//   - bool: true if Unicode tags are allowed
+func AllowUnicode() bool {
+	return viper.GetBool("tags.allow_unicode")
+}
+
+// normalizeUnicodeTag casefolds and validates a trimmed tag under the
+// Unicode policy.
+//
+// The tag is brought into NFC form before and after casefolding, so a
+// precomposed "é" and "e" followed by a combining accent give the same tag,
+// and "Straße" and "STRASSE" both become "strasse". The 64 character limit
+// counts characters, not bytes.
+func normalizeUnicodeTag(t string) (string, bool) {
+	t = norm.NFC.String(cases.Fold().String(norm.NFC.String(t)))
+	if !unicodeTagPattern.MatchString(t) {
+		return "", false
+	}
+	return t, true
+}
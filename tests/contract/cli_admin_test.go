@@ -0,0 +1,299 @@
+package contract_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// buildCLI builds the bundle binary into a temporary directory and returns
+// its path and the repository root.
+func buildCLI(t *testing.T) (string, string) {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "bundle-test-bin")
+	cwd, _ := os.Getwd()
+	repoRoot := filepath.Join(cwd, "..", "..")
+
+	build := exec.Command("go", "build", "-o", bin, filepath.Join(repoRoot, "cmd", "bundle"))
+	build.Stdout = os.Stdout
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		t.Fatalf("failed to build cli: %v", err)
+	}
+	return bin, repoRoot
+}
+
+// writeConfig points the CLI at a fresh home directory whose config.yaml
+// defines the given pools, name to root. It returns the home directory.
+func writeConfig(t *testing.T, pools map[string]string) string {
+	t.Helper()
+	home := filepath.Join(t.TempDir(), "home")
+	configDir := filepath.Join(home, ".config", "bundle")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("mkdir config: %v", err)
+	}
+	config := "pools:\n"
+	for name, root := range pools {
+		config += fmt.Sprintf("  %s:\n    root: %s\n", name, root)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("HOME", home)
+	return home
+}
+
+func TestDoctorCLI(t *testing.T) {
+	bin, repoRoot := buildCLI(t)
+	tmp := t.TempDir()
+
+	type doctorResp struct {
+		Status string `json:"status"`
+		Checks []struct {
+			Name   string `json:"name"`
+			Status string `json:"status"`
+		} `json:"checks"`
+		Settings struct {
+			Pools map[string]string `json:"pools"`
+		} `json:"settings"`
+	}
+	doctor := func(wantExit int) doctorResp {
+		t.Helper()
+		out, stderr, exit, _ := runCmd(bin, repoRoot, "doctor", "-j")
+		if exit != wantExit {
+			t.Fatalf("doctor exit = %d, want %d; out=%s errout=%s", exit, wantExit, out, stderr)
+		}
+		var resp doctorResp
+		if err := json.Unmarshal([]byte(extractJSON(out)), &resp); err != nil {
+			t.Fatalf("invalid json from doctor: %v out=%s errout=%s", err, out, stderr)
+		}
+		return resp
+	}
+	check := func(resp doctorResp, name string) string {
+		for _, c := range resp.Checks {
+			if c.Name == name {
+				return c.Status
+			}
+		}
+		return ""
+	}
+
+	// A writable default pool passes every check
+	poolRoot := filepath.Join(tmp, "pool")
+	if err := os.MkdirAll(poolRoot, 0755); err != nil {
+		t.Fatalf("mkdir pool: %v", err)
+	}
+	writeConfig(t, map[string]string{"default": poolRoot})
+	resp := doctor(0)
+	if resp.Status != "pass" || check(resp, "config file") != "pass" || check(resp, "pool default") != "pass" {
+		t.Fatalf("doctor with a valid config = %+v, want pass", resp)
+	}
+	if resp.Settings.Pools["default"] != poolRoot {
+		t.Fatalf("doctor settings pools = %v, want default at %s", resp.Settings.Pools, poolRoot)
+	}
+
+	// A missing pool root only warns, it is created on first import
+	writeConfig(t, map[string]string{"default": filepath.Join(tmp, "missing")})
+	if resp := doctor(0); resp.Status != "warn" || check(resp, "pool default") != "warn" {
+		t.Fatalf("doctor with a missing pool root = %+v, want warn", resp)
+	}
+
+	// A pool root that is a file fails and sets the exit code
+	file := filepath.Join(tmp, "file")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	writeConfig(t, map[string]string{"default": poolRoot, "broken": file})
+	if resp := doctor(1); resp.Status != "fail" || check(resp, "pool broken") != "fail" || check(resp, "pool default") != "pass" {
+		t.Fatalf("doctor with a file as pool root = %+v, want fail", resp)
+	}
+
+	// An unparseable config file fails
+	home := writeConfig(t, nil)
+	if err := os.WriteFile(filepath.Join(home, ".config", "bundle", "config.yaml"), []byte("pools: [\n"), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if resp := doctor(1); check(resp, "config file") != "fail" {
+		t.Fatalf("doctor with a broken config = %+v, want config file fail", resp)
+	}
+}
+
+func TestPoolStatsCLI_AllPools(t *testing.T) {
+	bin, repoRoot := buildCLI(t)
+	tmp := t.TempDir()
+
+	file := filepath.Join(tmp, "file")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	writeConfig(t, map[string]string{
+		"default": filepath.Join(tmp, "pool"),
+		"archive": filepath.Join(tmp, "archive"),
+		"broken":  file,
+	})
+
+	// One bundle in default, two in archive; one of those was never verified
+	imports := map[string]string{"a": "default", "bb": "archive", "ccc": "archive"}
+	for content, poolName := range imports {
+		dir := filepath.Join(tmp, "data-"+content)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte(content), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		for _, args := range [][]string{{"create", dir}, {"import", dir, "--pool", poolName, "--quiet"}} {
+			out, stderr, exit, err := runCmd(bin, repoRoot, args...)
+			if err != nil || exit != 0 {
+				t.Fatalf("%v failed: err=%v exit=%d out=%s errout=%s", args, err, exit, out, stderr)
+			}
+		}
+	}
+	matches, _ := filepath.Glob(filepath.Join(tmp, "archive", "*", ".bundle", "STATE.json"))
+	if len(matches) != 2 {
+		t.Fatalf("archive STATE.json files = %v, want 2", matches)
+	}
+	if err := os.Remove(matches[0]); err != nil {
+		t.Fatalf("remove state: %v", err)
+	}
+
+	out, stderr, exit, err := runCmd(bin, repoRoot, "pool", "stats", "-j")
+	if err != nil || exit != 0 {
+		t.Fatalf("pool stats -j failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+	}
+	type stats struct {
+		Name       string `json:"name"`
+		Error      string `json:"error"`
+		Bundles    int    `json:"bundles"`
+		SizeBytes  int64  `json:"size_bytes"`
+		Verified   int    `json:"verified"`
+		Unverified int    `json:"unverified"`
+	}
+	var resp struct {
+		Pools []stats `json:"pools"`
+		Total stats   `json:"total"`
+	}
+	if err := json.Unmarshal([]byte(extractJSON(out)), &resp); err != nil {
+		t.Fatalf("invalid json from pool stats: %v out=%s errout=%s", err, out, stderr)
+	}
+
+	// Pools are sorted by name; the broken one is reported but not counted
+	if len(resp.Pools) != 3 {
+		t.Fatalf("pool stats pools = %+v, want 3", resp.Pools)
+	}
+	archive, broken, def := resp.Pools[0], resp.Pools[1], resp.Pools[2]
+	if archive.Name != "archive" || archive.Bundles != 2 || archive.Verified != 1 || archive.Unverified != 1 || archive.SizeBytes != 5 {
+		t.Errorf("archive stats = %+v, want 2 bundles, 1 verified, 5 bytes", archive)
+	}
+	if broken.Name != "broken" || broken.Error == "" || broken.Bundles != 0 {
+		t.Errorf("broken stats = %+v, want an error", broken)
+	}
+	if def.Name != "default" || def.Bundles != 1 || def.Verified != 1 || def.SizeBytes != 1 {
+		t.Errorf("default stats = %+v, want 1 verified bundle of 1 byte", def)
+	}
+	if resp.Total.Bundles != 3 || resp.Total.Verified != 2 || resp.Total.Unverified != 1 || resp.Total.SizeBytes != 6 {
+		t.Errorf("total stats = %+v, want 3 bundles, 2 verified, 6 bytes", resp.Total)
+	}
+
+	// --pool limits the report to one pool
+	out, stderr, exit, err = runCmd(bin, repoRoot, "pool", "stats", "--pool", "archive", "-j")
+	if err != nil || exit != 0 {
+		t.Fatalf("pool stats --pool failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+	}
+	resp.Pools = nil
+	if err := json.Unmarshal([]byte(extractJSON(out)), &resp); err != nil {
+		t.Fatalf("invalid json from pool stats: %v out=%s errout=%s", err, out, stderr)
+	}
+	if len(resp.Pools) != 1 || resp.Total.Bundles != 2 {
+		t.Errorf("pool stats --pool archive = %+v, want only archive", resp)
+	}
+}
+
+func TestListCLI_IncludeMeta(t *testing.T) {
+	bin, repoRoot := buildCLI(t)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("abc"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	out, stderr, exit, err := runCmd(bin, repoRoot, "create", dir)
+	if err != nil || exit != 0 {
+		t.Fatalf("create failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+	}
+
+	type entry struct {
+		Path     string `json:"path"`
+		Checksum string `json:"checksum"`
+		Size     int64  `json:"size_bytes"`
+	}
+	var resp struct {
+		Files      []entry  `json:"files"`
+		TotalFiles int      `json:"total_files"`
+		MetaFiles  *[]entry `json:"meta_files"`
+	}
+
+	// Without the flag there are no metadata files
+	out, stderr, exit, err = runCmd(bin, repoRoot, "list", dir, "-j")
+	if err != nil || exit != 0 {
+		t.Fatalf("list -j failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+	}
+	if err := json.Unmarshal([]byte(extractJSON(out)), &resp); err != nil {
+		t.Fatalf("invalid json from list: %v out=%s errout=%s", err, out, stderr)
+	}
+	if resp.MetaFiles != nil {
+		t.Fatalf("list without --include-meta has meta_files: %v", *resp.MetaFiles)
+	}
+
+	// With it they are listed apart from the bundle files
+	out, stderr, exit, err = runCmd(bin, repoRoot, "list", dir, "--include-meta", "-j")
+	if err != nil || exit != 0 {
+		t.Fatalf("list --include-meta -j failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+	}
+	if err := json.Unmarshal([]byte(extractJSON(out)), &resp); err != nil {
+		t.Fatalf("invalid json from list: %v out=%s errout=%s", err, out, stderr)
+	}
+	if resp.TotalFiles != 1 || len(resp.Files) != 1 || resp.Files[0].Path != "a.txt" {
+		t.Fatalf("list --include-meta files = %+v, want only a.txt", resp.Files)
+	}
+	if resp.MetaFiles == nil {
+		t.Fatalf("list --include-meta has no meta_files")
+	}
+	metaFiles := map[string]entry{}
+	for _, e := range *resp.MetaFiles {
+		metaFiles[filepath.ToSlash(e.Path)] = e
+	}
+	for _, name := range []string{"META.json", "STATE.json", "TAGS.txt", "SHA256SUM.txt"} {
+		e, ok := metaFiles[".bundle/"+name]
+		if !ok {
+			t.Errorf("meta_files = %v, missing .bundle/%s", *resp.MetaFiles, name)
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, ".bundle", name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		sum := sha256.Sum256(data)
+		if e.Size != int64(len(data)) || e.Checksum != hex.EncodeToString(sum[:]) {
+			t.Errorf("meta_files %s = %+v, want size %d and checksum of the file", name, e, len(data))
+		}
+	}
+	for name := range metaFiles {
+		if strings.Contains(name, ".lock") {
+			t.Errorf("meta_files lists the lock file %s", name)
+		}
+	}
+
+	// The table output keeps them in a separate table
+	out, stderr, exit, err = runCmd(bin, repoRoot, "list", dir, "--include-meta")
+	if err != nil || exit != 0 {
+		t.Fatalf("list --include-meta failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+	}
+	if !strings.Contains(strings.ToUpper(out), "METADATA FILE") || !strings.Contains(out, "META.json") {
+		t.Fatalf("list --include-meta output lacks the metadata table: %s", out)
+	}
+}
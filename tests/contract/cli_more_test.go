@@ -9,7 +9,7 @@ import (
     "testing"
 )
 
-// This test covers create, info, verify, list, and rename in JSON and non-JSON modes.
+// This test covers create, info, verify (including --file), verify-file, list, walk, and rename in JSON and non-JSON modes.
 func TestCLI_More(t *testing.T) {
     tmp := t.TempDir()
     bin := filepath.Join(tmp, "bundle-test-bin")
@@ -71,6 +71,44 @@ func TestCLI_More(t *testing.T) {
     if verResp["status"] == nil {
         t.Fatalf("verify json missing status: %v", verResp)
     }
+    if verResp["schema_version"] != float64(1) {
+        t.Fatalf("verify json schema_version = %v, want 1", verResp["schema_version"])
+    }
+
+    // verify-file on a single file: match exits 0, mismatch exits 1
+    const abcSum = "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+    out, stderr, exit, err = runCmd(bin, repoRoot, "verify-file", f1, abcSum, "-j")
+    if err != nil || exit != 0 {
+        t.Fatalf("verify-file failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+    }
+    var vfResp map[string]interface{}
+    if err := json.Unmarshal([]byte(extractJSON(out)), &vfResp); err != nil {
+        t.Fatalf("invalid json from verify-file: %v out=%s errout=%s", err, out, stderr)
+    }
+    if vfResp["status"] != "valid" || vfResp["actual"] != abcSum {
+        t.Fatalf("verify-file json unexpected: %v", vfResp)
+    }
+    _, _, exit, _ = runCmd(bin, repoRoot, "verify-file", f1, strings.Repeat("0", 64))
+    if exit != 1 {
+        t.Fatalf("verify-file mismatch exit = %d, want 1", exit)
+    }
+
+    // verify --file checks one tracked file; untracked files exit 1
+    out, stderr, exit, err = runCmd(bin, repoRoot, "verify", dataDir, "--file", "x.txt", "-j")
+    if err != nil || exit != 0 {
+        t.Fatalf("verify --file failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+    }
+    var vsResp map[string]interface{}
+    if err := json.Unmarshal([]byte(extractJSON(out)), &vsResp); err != nil {
+        t.Fatalf("invalid json from verify --file: %v out=%s errout=%s", err, out, stderr)
+    }
+    if vsResp["status"] != "valid" || vsResp["file"] != "x.txt" {
+        t.Fatalf("verify --file json unexpected: %v", vsResp)
+    }
+    _, _, exit, _ = runCmd(bin, repoRoot, "verify", dataDir, "--file", "missing.txt")
+    if exit != 1 {
+        t.Fatalf("verify --file untracked exit = %d, want 1", exit)
+    }
 
     // List JSON
     out, stderr, exit, err = runCmd(bin, repoRoot, "list", dataDir, "-j")
@@ -85,6 +123,36 @@ func TestCLI_More(t *testing.T) {
         t.Fatalf("list json missing path: %v", listResp)
     }
 
+    // walk lists the files create would include, without .bundle/
+    out, stderr, exit, err = runCmd(bin, repoRoot, "walk", dataDir, "-j")
+    if err != nil || exit != 0 {
+        t.Fatalf("walk -j failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+    }
+    var walkResp struct {
+        Count int `json:"count"`
+        Files []struct {
+            Path string `json:"path"`
+            Size int64  `json:"size"`
+        } `json:"files"`
+    }
+    if err := json.Unmarshal([]byte(extractJSON(out)), &walkResp); err != nil {
+        t.Fatalf("invalid json from walk: %v out=%s errout=%s", err, out, stderr)
+    }
+    if walkResp.Count != 1 || len(walkResp.Files) != 1 || walkResp.Files[0].Path != "x.txt" || walkResp.Files[0].Size != 3 {
+        t.Fatalf("walk json unexpected: %+v", walkResp)
+    }
+    out, stderr, exit, err = runCmd(bin, repoRoot, "walk", dataDir, "--exclude", "*.txt", "-j")
+    if err != nil || exit != 0 {
+        t.Fatalf("walk --exclude failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+    }
+    walkResp.Files = nil
+    if err := json.Unmarshal([]byte(extractJSON(out)), &walkResp); err != nil {
+        t.Fatalf("invalid json from walk --exclude: %v out=%s errout=%s", err, out, stderr)
+    }
+    if walkResp.Count != 0 || len(walkResp.Files) != 0 {
+        t.Fatalf("walk --exclude json unexpected: %+v", walkResp)
+    }
+
     // Rename (JSON)
     out, stderr, exit, err = runCmd(bin, repoRoot, "rename", dataDir, "NewTitle", "-j")
     // Note: rename uses positional args, ensure exit 0
@@ -98,6 +166,99 @@ func TestCLI_More(t *testing.T) {
     if renResp["title"] != "NewTitle" {
         t.Fatalf("rename did not set title: %v", renResp)
     }
+
+    // Annotations survive a forced recreate
+    out, stderr, exit, err = runCmd(bin, repoRoot, "annotate", dataDir, "project=x")
+    if err != nil || exit != 0 {
+        t.Fatalf("annotate failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+    }
+    out, stderr, exit, err = runCmd(bin, repoRoot, "create", dataDir, "--force")
+    if err != nil || exit != 0 {
+        t.Fatalf("create --force failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+    }
+    out, stderr, exit, err = runCmd(bin, repoRoot, "info", dataDir, "-j")
+    if err != nil || exit != 0 {
+        t.Fatalf("info -j failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+    }
+    var annResp struct {
+        Annotations map[string]string `json:"annotations"`
+    }
+    if err := json.Unmarshal([]byte(extractJSON(out)), &annResp); err != nil {
+        t.Fatalf("invalid json from info: %v out=%s errout=%s", err, out, stderr)
+    }
+    if annResp.Annotations["project"] != "x" {
+        t.Fatalf("annotation lost on create --force: %v", annResp.Annotations)
+    }
+
+    // Create without --title defaults to the humanized directory name
+    untitledDir := filepath.Join(tmp, "my_data-set")
+    if err := os.MkdirAll(untitledDir, 0755); err != nil {
+        t.Fatalf("mkdir untitled: %v", err)
+    }
+    out, stderr, exit, err = runCmd(bin, repoRoot, "create", untitledDir, "-j")
+    if err != nil || exit != 0 {
+        t.Fatalf("create without title failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+    }
+    var createResp map[string]interface{}
+    if err := json.Unmarshal([]byte(extractJSON(out)), &createResp); err != nil {
+        t.Fatalf("invalid json from create: %v out=%s errout=%s", err, out, stderr)
+    }
+    if createResp["title"] != "My Data Set" {
+        t.Fatalf("create did not default title: %v", createResp)
+    }
+
+    // info only reports a future timestamp, repair --fix-timestamps re-stamps it
+    metaPath := filepath.Join(untitledDir, ".bundle", "META.json")
+    data, err := os.ReadFile(metaPath)
+    if err != nil {
+        t.Fatalf("read META.json: %v", err)
+    }
+    var meta map[string]interface{}
+    if err := json.Unmarshal(data, &meta); err != nil {
+        t.Fatalf("parse META.json: %v", err)
+    }
+    meta["created_at"] = "2099-01-01T00:00:00Z"
+    if data, err = json.Marshal(meta); err != nil {
+        t.Fatalf("marshal META.json: %v", err)
+    }
+    if err := os.WriteFile(metaPath, data, 0644); err != nil {
+        t.Fatalf("write META.json: %v", err)
+    }
+    var futureResp struct {
+        FutureTimestamps []map[string]interface{} `json:"future_timestamps"`
+    }
+    out, stderr, exit, err = runCmd(bin, repoRoot, "info", untitledDir, "-j")
+    if err != nil || exit != 0 {
+        t.Fatalf("info -j failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+    }
+    if err := json.Unmarshal([]byte(extractJSON(out)), &futureResp); err != nil {
+        t.Fatalf("invalid json from info: %v out=%s errout=%s", err, out, stderr)
+    }
+    if len(futureResp.FutureTimestamps) != 1 {
+        t.Fatalf("info future_timestamps = %v, want created_at", futureResp.FutureTimestamps)
+    }
+    if after, _ := os.ReadFile(metaPath); string(after) != string(data) {
+        t.Fatalf("info rewrote META.json")
+    }
+    out, stderr, exit, err = runCmd(bin, repoRoot, "repair", untitledDir, "--fix-timestamps", "-j")
+    if err != nil || exit != 0 {
+        t.Fatalf("repair --fix-timestamps failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+    }
+    var repairResp struct {
+        Status          string                   `json:"status"`
+        TimestampsFixed []map[string]interface{} `json:"timestamps_fixed"`
+    }
+    if err := json.Unmarshal([]byte(extractJSON(out)), &repairResp); err != nil {
+        t.Fatalf("invalid json from repair: %v out=%s errout=%s", err, out, stderr)
+    }
+    if repairResp.Status != "repaired" || len(repairResp.TimestampsFixed) != 1 {
+        t.Fatalf("repair --fix-timestamps json unexpected: %+v", repairResp)
+    }
+    futureResp.FutureTimestamps = nil
+    out, _, _, _ = runCmd(bin, repoRoot, "info", untitledDir, "-j")
+    if err := json.Unmarshal([]byte(extractJSON(out)), &futureResp); err != nil || len(futureResp.FutureTimestamps) != 0 {
+        t.Fatalf("future timestamps left after repair: %v (%v)", futureResp.FutureTimestamps, err)
+    }
 }
 
 // extractJSON finds the first '{' and returns substring from there, or the original string if not found.
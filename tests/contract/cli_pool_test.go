@@ -0,0 +1,158 @@
+package contract_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPoolCLI covers the pool command group against a temporary pool.
+func TestPoolCLI(t *testing.T) {
+	tmp := t.TempDir()
+	bin := filepath.Join(tmp, "bundle-test-bin")
+	cwd, _ := os.Getwd()
+	repoRoot := filepath.Join(cwd, "..", "..")
+	cmdPath := filepath.Join(repoRoot, "cmd", "bundle")
+
+	build := exec.Command("go", "build", "-o", bin, cmdPath)
+	build.Stdout = os.Stdout
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		t.Fatalf("failed to build cli: %v", err)
+	}
+
+	// Point the CLI at a config with a single pool
+	home := filepath.Join(tmp, "home")
+	poolRoot := filepath.Join(tmp, "pool")
+	configDir := filepath.Join(home, ".config", "bundle")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("mkdir config: %v", err)
+	}
+	config := fmt.Sprintf("pools:\n  default:\n    root: %s\n", poolRoot)
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("HOME", home)
+
+	dataDir := filepath.Join(tmp, "data")
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		t.Fatalf("mkdir data: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, "a.txt"), []byte("pooled"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	for _, args := range [][]string{{"create", dataDir}, {"import", dataDir, "--quiet"}} {
+		out, stderr, exit, err := runCmd(bin, repoRoot, args...)
+		if err != nil || exit != 0 {
+			t.Fatalf("%v failed: err=%v exit=%d out=%s errout=%s", args, err, exit, out, stderr)
+		}
+	}
+
+	// pool stats
+	out, stderr, exit, err := runCmd(bin, repoRoot, "pool", "stats", "-j")
+	if err != nil || exit != 0 {
+		t.Fatalf("pool stats -j failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+	}
+	var statsResp struct {
+		Total struct {
+			Bundles int `json:"bundles"`
+		} `json:"total"`
+	}
+	if err := json.Unmarshal([]byte(extractJSON(out)), &statsResp); err != nil {
+		t.Fatalf("invalid json from pool stats: %v out=%s errout=%s", err, out, stderr)
+	}
+	if statsResp.Total.Bundles != 1 {
+		t.Fatalf("pool stats bundles = %d, want 1", statsResp.Total.Bundles)
+	}
+
+	// tag export takes the global --output format and --output-file
+	exportPath := filepath.Join(tmp, "tags.csv")
+	out, stderr, exit, err = runCmd(bin, tmp, "tag", "export", "--output", "csv", "--output-file", exportPath)
+	if err != nil || exit != 0 {
+		t.Fatalf("tag export failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+	}
+	if data, err := os.ReadFile(exportPath); err != nil || !strings.HasPrefix(string(data), "checksum,tags\n") {
+		t.Fatalf("tag export wrote %q, %v; want CSV", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "csv")); !os.IsNotExist(err) {
+		t.Fatalf("tag export --output csv created a file named csv: %v", err)
+	}
+
+	// pool gc leaves the complete bundle alone
+	out, stderr, exit, err = runCmd(bin, repoRoot, "pool", "gc", "--dry-run", "-j")
+	if err != nil || exit != 0 {
+		t.Fatalf("pool gc -j failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+	}
+	var gcResp struct {
+		Status  string   `json:"status"`
+		Removed []string `json:"removed"`
+	}
+	if err := json.Unmarshal([]byte(extractJSON(out)), &gcResp); err != nil {
+		t.Fatalf("invalid json from pool gc: %v out=%s errout=%s", err, out, stderr)
+	}
+	if gcResp.Status != "dry_run" || len(gcResp.Removed) != 0 {
+		t.Fatalf("pool gc = %+v, want dry_run with nothing removed", gcResp)
+	}
+
+	// Removing an interrupted import needs --yes without a terminal
+	partial := filepath.Join(poolRoot, strings.Repeat("0", 64)+".partial")
+	if err := os.MkdirAll(partial, 0755); err != nil {
+		t.Fatalf("mkdir partial: %v", err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(partial, old, old); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	_, _, exit, _ = runCmd(bin, repoRoot, "pool", "gc")
+	if exit != 1 {
+		t.Fatalf("pool gc without --yes exit = %d, want 1", exit)
+	}
+	if _, err := os.Stat(partial); err != nil {
+		t.Fatalf("pool gc without --yes removed the partial import: %v", err)
+	}
+	out, stderr, exit, err = runCmd(bin, repoRoot, "pool", "gc", "--yes", "-j")
+	if err != nil || exit != 0 {
+		t.Fatalf("pool gc --yes -j failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+	}
+	gcResp.Removed = nil
+	if err := json.Unmarshal([]byte(extractJSON(out)), &gcResp); err != nil {
+		t.Fatalf("invalid json from pool gc: %v out=%s errout=%s", err, out, stderr)
+	}
+	if gcResp.Status != "collected" || len(gcResp.Removed) != 1 || gcResp.Removed[0] != partial {
+		t.Fatalf("pool gc = %+v, want %s removed", gcResp, partial)
+	}
+
+	// pool verify
+	out, stderr, exit, err = runCmd(bin, repoRoot, "pool", "verify", "-j")
+	if err != nil || exit != 0 {
+		t.Fatalf("pool verify -j failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+	}
+	var verifyResp struct {
+		Status  string `json:"status"`
+		Checked int    `json:"checked"`
+	}
+	if err := json.Unmarshal([]byte(extractJSON(out)), &verifyResp); err != nil {
+		t.Fatalf("invalid json from pool verify: %v out=%s errout=%s", err, out, stderr)
+	}
+	if verifyResp.Status != "valid" || verifyResp.Checked != 1 {
+		t.Fatalf("pool verify = %+v, want 1 valid bundle", verifyResp)
+	}
+
+	// verify-pool is the same command at the top level
+	out, stderr, exit, err = runCmd(bin, repoRoot, "verify-pool", "--pool", "default", "-j")
+	if err != nil || exit != 0 {
+		t.Fatalf("verify-pool -j failed: err=%v exit=%d out=%s errout=%s", err, exit, out, stderr)
+	}
+	verifyResp.Checked = 0
+	if err := json.Unmarshal([]byte(extractJSON(out)), &verifyResp); err != nil {
+		t.Fatalf("invalid json from verify-pool: %v out=%s errout=%s", err, out, stderr)
+	}
+	if verifyResp.Status != "valid" || verifyResp.Checked != 1 {
+		t.Fatalf("verify-pool = %+v, want 1 valid bundle", verifyResp)
+	}
+}
@@ -131,6 +131,20 @@ func TestTagCLI_AddListRemove_JSON(t *testing.T) {
     if remResp["status"] != "removed" {
         t.Fatalf("unexpected remove status: %v", remResp["status"])
     }
+    // tags is the set left after removal, like tag add returns the resulting set
+    remTags, ok := remResp["tags"].([]interface{})
+    if !ok || len(remTags) != len(ltags)-1 {
+        t.Fatalf("remove tags = %v, want %d remaining tags", remResp["tags"], len(ltags)-1)
+    }
+    for _, tg := range remTags {
+        if tg == "photos" {
+            t.Fatalf("removed tag still in remove response: %v", remTags)
+        }
+    }
+    removed, ok := remResp["removed"].([]interface{})
+    if !ok || len(removed) != 1 || removed[0] != "photos" {
+        t.Fatalf("remove removed = %v, want [photos]", remResp["removed"])
+    }
 
     // Error case: non-existent path should exit 1
     _, _, exit, _ = runCmd(bin, repoRoot, "tag", "add", "/nonexistent/path/hopefully", "x")
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// AssumeYes answers every Confirm prompt with yes.
+//
+// It is bound to the global --yes/-y flag so destructive commands can be
+// scripted.
+var AssumeYes bool
+
+// Confirm asks the user to confirm an action.
+//
+// When AssumeYes is set it returns true without prompting. Otherwise the
+// prompt is written to stderr and an answer read from stdin; only "y" or
+// "yes" (any case) confirm. When stdin is not a terminal there is nobody to
+// answer, so Confirm refuses, explains that --yes is required and returns
+// false.
+//
+// Example:
+//
+//	if !utils.Confirm("Remove 3 bundles from pool default?") {
+//	    os.Exit(1)
+//	}
+//
+// Parameters:
+//   - prompt: question to ask; " [y/N]: " is appended
+//
+// Returns:
+//   - bool: true if the action is confirmed
+func Confirm(prompt string) bool {
+	return confirm(os.Stdin, os.Stderr, IsTerminal(os.Stdin), prompt)
+}
+
+// confirm implements Confirm for arbitrary input and output.
+func confirm(in io.Reader, out io.Writer, interactive bool, prompt string) bool {
+	if AssumeYes {
+		return true
+	}
+	if !interactive {
+		fmt.Fprintf(out, "%s\nRefusing: stdin is not a terminal; rerun with --yes to confirm\n", prompt)
+		return false
+	}
+
+	fmt.Fprintf(out, "%s [y/N]: ", prompt)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Fprintln(out)
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether file is a character device such as a TTY.
+//
+// Example:
+//
+//	if utils.IsTerminal(os.Stdout) {
+//	    fmt.Print("\rworking...")
+//	}
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		interactive bool
+		assumeYes   bool
+		want        bool
+		wantOutput  string
+	}{
+		{"yes", "y\n", true, false, true, "[y/N]"},
+		{"full yes", "YES\n", true, false, true, "[y/N]"},
+		{"no", "n\n", true, false, false, "[y/N]"},
+		{"empty answer", "\n", true, false, false, "[y/N]"},
+		{"eof", "", true, false, false, "[y/N]"},
+		{"no trailing newline", "yes", true, false, true, "[y/N]"},
+		{"other", "sure\n", true, false, false, "[y/N]"},
+		{"not a terminal", "y\n", false, false, false, "--yes"},
+		{"assume yes", "", false, true, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			AssumeYes = tt.assumeYes
+			defer func() { AssumeYes = false }()
+
+			var out bytes.Buffer
+			got := confirm(strings.NewReader(tt.input), &out, tt.interactive, "Proceed?")
+			if got != tt.want {
+				t.Errorf("confirm() = %v, want %v", got, tt.want)
+			}
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("confirm() output = %q, want it to contain %q", out.String(), tt.wantOutput)
+			}
+		})
+	}
+}
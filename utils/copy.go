@@ -0,0 +1,471 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// OverwritePolicy controls whether copy operations replace files that
+// already exist at the destination.
+type OverwritePolicy string
+
+const (
+	// OverwriteNever keeps existing destination files (the default).
+	OverwriteNever OverwritePolicy = "never"
+
+	// OverwriteOlder replaces destination files older than the source.
+	OverwriteOlder OverwritePolicy = "older"
+
+	// OverwriteAlways replaces existing destination files.
+	OverwriteAlways OverwritePolicy = "always"
+)
+
+// CopyStats counts what happened to each file during a copy.
+type CopyStats struct {
+	Copied      int `json:"copied"`      // Files that did not exist at the destination
+	Overwritten int `json:"overwritten"` // Existing files that were replaced
+	Skipped     int `json:"skipped"`     // Existing files that were kept
+	Linked      int `json:"linked"`      // Files hard-linked instead of copied
+}
+
+// CopyOptions controls CopyTree.
+//
+// The zero value copies every file and directory with its permission bits,
+// keeps existing destination files, recreates symlinks as symlinks and
+// does not preserve modification times.
+//
+// Hardlink selects regular files to hard-link instead of copy; with
+// FollowSymlinks, a symlink is replaced by a link to its target. Linking
+// falls back to copying when it fails, e.g. across devices. Linked files
+// share their content, and their times, with the source; they are not
+// passed to Verify.
+//
+// Skip is called with the path of every entry relative to the source
+// directory; entries it returns true for are left out, directories with
+// everything below them.
+type CopyOptions struct {
+	Overwrite      OverwritePolicy             // Existing destination files (OverwriteNever when empty)
+	FollowSymlinks bool                        // Copy symlink targets instead of recreating the links
+	PreserveTimes  bool                        // Copy modification times of files and directories
+	Verify         func(src, dst string) error // Called after each copied file; an error aborts the copy
+	Hardlink       func(src string) bool       // Hard-link the regular files it returns true for
+	Skip           func(rel string) bool       // Leave out the entries it returns true for
+	Stats          *CopyStats                  // Counts copied, overwritten and skipped files when set
+}
+
+var (
+	// largeFileThreshold is the size from which files are copied in chunks
+	// with periodic fsync and can be resumed after an interruption.
+	largeFileThreshold int64 = 64 << 20
+
+	// copyChunkSize is the number of bytes copied between two fsync calls.
+	copyChunkSize int64 = 8 << 20
+)
+
+// ParseOverwritePolicy converts a flag value to an OverwritePolicy.
+//
+// An empty string yields OverwriteNever.
+//
+// Example:
+//
+//	policy, err := utils.ParseOverwritePolicy("older")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - s: one of "never", "older" or "always"
+//
+// Returns:
+//   - OverwritePolicy: the parsed policy
+//   - error: if s is not a known policy
+func ParseOverwritePolicy(s string) (OverwritePolicy, error) {
+	switch OverwritePolicy(s) {
+	case "", OverwriteNever:
+		return OverwriteNever, nil
+	case OverwriteOlder, OverwriteAlways:
+		return OverwritePolicy(s), nil
+	}
+	return "", fmt.Errorf("invalid overwrite policy '%s': must be never, older or always", s)
+}
+
+// check reports whether dst exists and, if so, whether the policy allows
+// replacing it with src.
+func (p OverwritePolicy) check(src, dst string) (bool, bool, error) {
+	dstInfo, err := os.Lstat(dst)
+	if os.IsNotExist(err) {
+		return false, true, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+
+	switch p {
+	case OverwriteAlways:
+		return true, true, nil
+	case OverwriteOlder:
+		srcInfo, err := os.Stat(src)
+		if err != nil {
+			return true, false, err
+		}
+		return true, dstInfo.ModTime().Before(srcInfo.ModTime()), nil
+	}
+	return true, false, nil
+}
+
+// CopyTree recursively copies the directory src to dst.
+//
+// Directories are created with the permission bits of their source and
+// files are written with theirs. Files at or above 64 MiB are copied in
+// fsync'ed chunks; an interrupted copy of such a file is resumed on the
+// next run and checked against the source checksum, so copying into a
+// staging directory and retrying is safe. Existing destination files are
+// handled according to opts.Overwrite. A symlink already present below dst
+// where a file or directory is to be written is never followed: CopyTree
+// fails with ErrInvalidPath rather than write outside dst.
+//
+// Example:
+//
+//	stats := &utils.CopyStats{}
+//	err := utils.CopyTree("/path/to/bundle", "/mnt/pool/abc123", utils.CopyOptions{
+//	    Overwrite:     utils.OverwriteOlder,
+//	    PreserveTimes: true,
+//	    Stats:         stats,
+//	})
+//	fmt.Printf("%d copied, %d skipped\n", stats.Copied, stats.Skipped)
+//
+// Parameters:
+//   - src: source directory
+//   - dst: destination directory, created if missing
+//   - opts: copy options
+//
+// Returns:
+//   - error: the first failure to read, write or verify a file
+func CopyTree(src, dst string, opts CopyOptions) error {
+	if opts.Stats == nil {
+		opts.Stats = &CopyStats{}
+	}
+	return copyDir(src, dst, "", opts)
+}
+
+// copyDir implements CopyTree for one directory level; rel is the path of
+// src relative to the directory CopyTree was called with.
+func copyDir(src, dst, rel string, opts CopyOptions) error {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if rel != "" {
+		if err := refuseSymlink(dst); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(dst, srcInfo.Mode().Perm()); err != nil {
+		return WrapPathError(dst, err)
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		srcPath := filepath.Join(src, entry.Name())
+		dstPath := filepath.Join(dst, entry.Name())
+		entryRel := filepath.Join(rel, entry.Name())
+		if opts.Skip != nil && opts.Skip(entryRel) {
+			log.Debugf("Skipping %s", srcPath)
+			continue
+		}
+
+		// linkPath is the file a hard link points at: the target of a
+		// followed symlink, since os.Link would link the symlink itself
+		linkPath := srcPath
+		isDir := entry.IsDir()
+		isLink := entry.Type()&os.ModeSymlink != 0
+		if isLink && opts.FollowSymlinks {
+			info, err := os.Stat(srcPath)
+			if err != nil {
+				return err
+			}
+			if linkPath, err = filepath.EvalSymlinks(srcPath); err != nil {
+				return err
+			}
+			isDir, isLink = info.IsDir(), false
+		}
+
+		if isDir {
+			if err := copyDir(srcPath, dstPath, entryRel, opts); err != nil {
+				return err
+			}
+			continue
+		}
+
+		exists, replace, err := opts.Overwrite.check(srcPath, dstPath)
+		if err != nil {
+			return err
+		}
+		if exists && !replace {
+			log.Debugf("Skipping existing file: %s", dstPath)
+			opts.Stats.Skipped++
+			continue
+		}
+
+		if !isLink && opts.Hardlink != nil && opts.Hardlink(srcPath) {
+			if linkFile(linkPath, dstPath) {
+				opts.Stats.Linked++
+				continue
+			}
+		}
+
+		if isLink {
+			err = copySymlink(srcPath, dstPath)
+		} else {
+			err = copyFile(srcPath, dstPath)
+		}
+		if err != nil {
+			return err
+		}
+		if opts.PreserveTimes && !isLink {
+			if err := copyModTime(srcPath, dstPath); err != nil {
+				return err
+			}
+		}
+		if opts.Verify != nil && !isLink {
+			if err := opts.Verify(srcPath, dstPath); err != nil {
+				return err
+			}
+		}
+		if exists {
+			opts.Stats.Overwritten++
+		} else {
+			opts.Stats.Copied++
+		}
+	}
+
+	if opts.PreserveTimes {
+		return copyModTime(src, dst)
+	}
+	return nil
+}
+
+// copyModTime sets the access and modification times of dst to those of src.
+func copyModTime(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// linkFile hard-links src to dst, replacing an existing dst. It reports
+// false when the link cannot be made, so the caller can copy instead.
+func linkFile(src, dst string) bool {
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		log.Debugf("Cannot replace %s with a link: %v", dst, err)
+		return false
+	}
+	if err := os.Link(src, dst); err != nil {
+		log.Debugf("Cannot link %s, copying instead: %v", src, err)
+		return false
+	}
+	return true
+}
+
+// copySymlink recreates the symlink src at dst with the same target.
+func copySymlink(src, dst string) error {
+	target, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return WrapPathError(dst, os.Symlink(target, dst))
+}
+
+// refuseSymlink returns an error if path is a symlink, so a copy never
+// writes through a link planted in the destination.
+func refuseSymlink(path string) error {
+	info, err := os.Lstat(path)
+	if err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("%w: refusing to write through symlink %s", ErrInvalidPath, path)
+	}
+	return nil
+}
+
+// CopyFile copies the regular file src to dst with its permission bits,
+// as CopyTree does for each file; an existing dst is replaced, unless it
+// is a symlink, which is refused with ErrInvalidPath.
+//
+// Example:
+//
+//	err := utils.CopyFile("/tmp/notes.txt", "/path/to/bundle/notes.txt")
+//
+// Parameters:
+//   - src: source file
+//   - dst: destination file
+//
+// Returns:
+//   - error: if src cannot be read, dst is a symlink or cannot be written
+func CopyFile(src, dst string) error {
+	return copyFile(src, dst)
+}
+
+// copyFile copies a single file.
+//
+// Files at or above largeFileThreshold are handed to copyFileChunked so an
+// interrupted copy can be resumed; smaller files use a single io.Copy.
+func copyFile(src, dst string) error {
+	if err := refuseSymlink(dst); err != nil {
+		return err
+	}
+
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return WrapPathError(src, err)
+	}
+	defer srcFile.Close()
+
+	srcInfo, err := srcFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	if srcInfo.Size() >= largeFileThreshold {
+		return copyFileChunked(srcFile, srcInfo, dst)
+	}
+
+	dstFile, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, srcInfo.Mode())
+	if err != nil {
+		return WrapPathError(dst, err)
+	}
+	defer dstFile.Close()
+
+	_, err = io.Copy(dstFile, srcFile)
+	return err
+}
+
+// copyFileChunked copies a large file in chunks, resuming a partial copy.
+//
+// When dst already exists and is not larger than the source, copying
+// continues at the current size of dst. Each chunk is synced to disk so a
+// crash loses at most one chunk. After the copy the SHA256 checksums of
+// source and destination are compared; a mismatch on a resumed copy
+// triggers one full copy from scratch before giving up.
+//
+// Parameters:
+//   - srcFile: opened source file
+//   - srcInfo: file info of the source file
+//   - dst: destination file path
+//
+// Returns:
+//   - error: if copying fails or the checksums do not match
+func copyFileChunked(srcFile *os.File, srcInfo os.FileInfo, dst string) error {
+	resumed, err := copyChunks(srcFile, srcInfo, dst, false)
+	if err != nil {
+		return err
+	}
+
+	srcSum, err := fileSHA256(srcFile.Name())
+	if err != nil {
+		return err
+	}
+	dstSum, err := fileSHA256(dst)
+	if err != nil {
+		return err
+	}
+	if srcSum == dstSum {
+		return nil
+	}
+
+	if !resumed {
+		return fmt.Errorf("checksum mismatch after copying %s", srcFile.Name())
+	}
+
+	log.Debugf("Resumed copy of %s is corrupt, copying from scratch", srcFile.Name())
+	if _, err := copyChunks(srcFile, srcInfo, dst, true); err != nil {
+		return err
+	}
+	if dstSum, err = fileSHA256(dst); err != nil {
+		return err
+	}
+	if srcSum != dstSum {
+		return fmt.Errorf("checksum mismatch after copying %s", srcFile.Name())
+	}
+	return nil
+}
+
+// copyChunks performs the actual chunked copy and reports whether it
+// continued an existing partial file. With restart set, any existing
+// destination content is discarded first.
+func copyChunks(srcFile *os.File, srcInfo os.FileInfo, dst string, restart bool) (bool, error) {
+	dstFile, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY, srcInfo.Mode())
+	if err != nil {
+		return false, WrapPathError(dst, err)
+	}
+	defer dstFile.Close()
+
+	dstInfo, err := dstFile.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	offset := dstInfo.Size()
+	if restart || offset > srcInfo.Size() {
+		offset = 0
+	}
+	if err := dstFile.Truncate(offset); err != nil {
+		return false, err
+	}
+	resumed := offset > 0
+	if resumed {
+		log.Debugf("Resuming copy of %s at %d of %d bytes", srcFile.Name(), offset, srcInfo.Size())
+	}
+
+	if _, err := srcFile.Seek(offset, io.SeekStart); err != nil {
+		return resumed, err
+	}
+	if _, err := dstFile.Seek(offset, io.SeekStart); err != nil {
+		return resumed, err
+	}
+
+	for offset < srcInfo.Size() {
+		n, err := io.CopyN(dstFile, srcFile, copyChunkSize)
+		offset += n
+		if err != nil && err != io.EOF {
+			return resumed, err
+		}
+		if err := dstFile.Sync(); err != nil {
+			return resumed, err
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return resumed, nil
+}
+
+// fileSHA256 returns the hex SHA256 of the file at path. It mirrors
+// checksum.ComputeFileSHA256, which utils cannot import.
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,293 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCopyFileChunked_Resume(t *testing.T) {
+	oldThreshold, oldChunk := largeFileThreshold, copyChunkSize
+	largeFileThreshold, copyChunkSize = 16, 7
+	defer func() { largeFileThreshold, copyChunkSize = oldThreshold, oldChunk }()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.bin")
+	dst := filepath.Join(dir, "dst.bin")
+	data := bytes.Repeat([]byte("0123456789"), 10)
+	if err := os.WriteFile(src, data, 0644); err != nil {
+		t.Fatalf("write src: %v", err)
+	}
+
+	// Simulate an interrupted copy
+	if err := os.WriteFile(dst, data[:33], 0644); err != nil {
+		t.Fatalf("write partial dst: %v", err)
+	}
+	if err := copyFile(src, dst); err != nil {
+		t.Fatalf("copyFile resume failed: %v", err)
+	}
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("read dst: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("resumed copy differs from source")
+	}
+
+	// A corrupt partial copy must be detected and recopied
+	if err := os.WriteFile(dst, []byte("garbage-garbage-garbage"), 0644); err != nil {
+		t.Fatalf("write corrupt dst: %v", err)
+	}
+	if err := copyFile(src, dst); err != nil {
+		t.Fatalf("copyFile after corruption failed: %v", err)
+	}
+	got, _ = os.ReadFile(dst)
+	if !bytes.Equal(got, data) {
+		t.Fatalf("copy after corruption differs from source")
+	}
+}
+
+func TestCopyTree_OverwritePolicy(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "a.txt"), []byte("new"), 0644); err != nil {
+		t.Fatalf("write src: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "b.txt"), []byte("b"), 0644); err != nil {
+		t.Fatalf("write src: %v", err)
+	}
+
+	tests := []struct {
+		policy OverwritePolicy
+		want   string
+		stats  CopyStats
+	}{
+		{OverwriteNever, "old", CopyStats{Copied: 1, Skipped: 1}},
+		{OverwriteOlder, "new", CopyStats{Copied: 1, Overwritten: 1}},
+		{OverwriteAlways, "new", CopyStats{Copied: 1, Overwritten: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			dst := t.TempDir()
+			existing := filepath.Join(dst, "a.txt")
+			if err := os.WriteFile(existing, []byte("old"), 0644); err != nil {
+				t.Fatalf("write dst: %v", err)
+			}
+			past := time.Now().Add(-time.Hour)
+			if err := os.Chtimes(existing, past, past); err != nil {
+				t.Fatalf("chtimes: %v", err)
+			}
+
+			stats := &CopyStats{}
+			if err := CopyTree(src, dst, CopyOptions{Overwrite: tt.policy, Stats: stats}); err != nil {
+				t.Fatalf("CopyTree failed: %v", err)
+			}
+			got, _ := os.ReadFile(existing)
+			if string(got) != tt.want {
+				t.Errorf("a.txt = %q, want %q", got, tt.want)
+			}
+			if *stats != tt.stats {
+				t.Errorf("stats = %+v, want %+v", *stats, tt.stats)
+			}
+		})
+	}
+}
+
+func TestCopyTree_SymlinkInDest(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for _, name := range []string{"a.txt", "sub/c.txt"} {
+		if err := os.WriteFile(filepath.Join(src, filepath.FromSlash(name)), []byte("new"), 0644); err != nil {
+			t.Fatalf("write src: %v", err)
+		}
+	}
+	outside := t.TempDir()
+	victim := filepath.Join(outside, "victim.txt")
+	if err := os.WriteFile(victim, []byte("old"), 0644); err != nil {
+		t.Fatalf("write victim: %v", err)
+	}
+
+	// A file symlink in the destination is not written through
+	dst := t.TempDir()
+	if err := os.Symlink(victim, filepath.Join(dst, "a.txt")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	if err := CopyTree(src, dst, CopyOptions{Overwrite: OverwriteAlways}); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("CopyTree over a file symlink: err = %v, want ErrInvalidPath", err)
+	}
+	if err := CopyFile(filepath.Join(src, "a.txt"), filepath.Join(dst, "a.txt")); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("CopyFile to a symlink: err = %v, want ErrInvalidPath", err)
+	}
+	if got, _ := os.ReadFile(victim); string(got) != "old" {
+		t.Errorf("victim.txt = %q, want it untouched", got)
+	}
+
+	// Nor is a directory symlink descended into
+	dst = t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(dst, "sub")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	if err := CopyTree(src, dst, CopyOptions{}); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("CopyTree into a directory symlink: err = %v, want ErrInvalidPath", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "c.txt")); !os.IsNotExist(err) {
+		t.Errorf("c.txt was written outside the destination: %v", err)
+	}
+}
+
+func TestParseOverwritePolicy(t *testing.T) {
+	if p, err := ParseOverwritePolicy(""); err != nil || p != OverwriteNever {
+		t.Errorf("ParseOverwritePolicy(\"\") = %q, %v; want never", p, err)
+	}
+	if _, err := ParseOverwritePolicy("sometimes"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
+
+func TestCopyTree_Options(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "a.txt"), []byte("a"), 0640); err != nil {
+		t.Fatalf("write src: %v", err)
+	}
+	if err := os.Symlink("a.txt", filepath.Join(src, "link")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(src, "a.txt"), mtime, mtime); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	// Default: symlinks are recreated, permissions kept
+	dst := filepath.Join(t.TempDir(), "out")
+	if err := CopyTree(src, dst, CopyOptions{}); err != nil {
+		t.Fatalf("CopyTree failed: %v", err)
+	}
+	if target, err := os.Readlink(filepath.Join(dst, "link")); err != nil || target != "a.txt" {
+		t.Errorf("link = %q, %v; want symlink to a.txt", target, err)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "a.txt")); err != nil || info.Mode().Perm() != 0640 {
+		t.Errorf("a.txt mode = %v, %v; want 0640", info.Mode().Perm(), err)
+	}
+
+	// FollowSymlinks and PreserveTimes
+	dst = filepath.Join(t.TempDir(), "out")
+	var verified []string
+	opts := CopyOptions{
+		FollowSymlinks: true,
+		PreserveTimes:  true,
+		Verify: func(s, d string) error {
+			verified = append(verified, filepath.Base(d))
+			return nil
+		},
+	}
+	if err := CopyTree(src, dst, opts); err != nil {
+		t.Fatalf("CopyTree failed: %v", err)
+	}
+	info, err := os.Lstat(filepath.Join(dst, "link"))
+	if err != nil || !info.Mode().IsRegular() {
+		t.Errorf("link should be copied as a regular file")
+	}
+	if info, err := os.Stat(filepath.Join(dst, "a.txt")); err != nil || !info.ModTime().Equal(mtime) {
+		t.Errorf("a.txt mtime not preserved")
+	}
+	if len(verified) != 2 {
+		t.Errorf("Verify called for %v, want both files", verified)
+	}
+
+	// Skip gets paths relative to src and leaves out whole directories
+	if err := os.MkdirAll(filepath.Join(src, "skip", "deep"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "skip", "deep", "b.txt"), []byte("b"), 0644); err != nil {
+		t.Fatalf("write src: %v", err)
+	}
+	dst = filepath.Join(t.TempDir(), "out")
+	var seen []string
+	skipOpts := CopyOptions{Skip: func(rel string) bool {
+		seen = append(seen, rel)
+		return rel == "skip" || rel == "link"
+	}}
+	if err := CopyTree(src, dst, skipOpts); err != nil {
+		t.Fatalf("CopyTree failed: %v", err)
+	}
+	for _, name := range []string{"skip", "link"} {
+		if _, err := os.Lstat(filepath.Join(dst, name)); !os.IsNotExist(err) {
+			t.Errorf("%s copied despite Skip: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dst, "a.txt")); err != nil {
+		t.Errorf("a.txt not copied: %v", err)
+	}
+	if len(seen) != 3 {
+		t.Errorf("Skip called for %v, want a.txt, link and skip only", seen)
+	}
+	if err := os.RemoveAll(filepath.Join(src, "skip")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	// A failing Verify hook aborts the copy
+	errBad := errors.New("bad copy")
+	opts.Verify = func(s, d string) error { return errBad }
+	err = CopyTree(src, filepath.Join(t.TempDir(), "out"), opts)
+	if !errors.Is(err, errBad) {
+		t.Errorf("CopyTree error = %v, want %v", err, errBad)
+	}
+}
+
+func TestCopyTree_Hardlink(t *testing.T) {
+	src := t.TempDir()
+	for _, name := range []string{"data.bin", "meta.json"} {
+		if err := os.WriteFile(filepath.Join(src, name), []byte(name), 0644); err != nil {
+			t.Fatalf("write src: %v", err)
+		}
+	}
+
+	dst := filepath.Join(t.TempDir(), "out")
+	stats := &CopyStats{}
+	opts := CopyOptions{
+		Hardlink: func(path string) bool { return filepath.Base(path) == "data.bin" },
+		Stats:    stats,
+	}
+	if err := CopyTree(src, dst, opts); err != nil {
+		t.Fatalf("CopyTree failed: %v", err)
+	}
+	if stats.Linked != 1 || stats.Copied != 1 {
+		t.Errorf("stats = %+v, want 1 linked and 1 copied", stats)
+	}
+
+	same := func(name string) bool {
+		a, errA := os.Stat(filepath.Join(src, name))
+		b, errB := os.Stat(filepath.Join(dst, name))
+		return errA == nil && errB == nil && os.SameFile(a, b)
+	}
+	if !same("data.bin") {
+		t.Error("data.bin was not hard-linked")
+	}
+	if same("meta.json") {
+		t.Error("meta.json was hard-linked, want a copy")
+	}
+
+	// A followed symlink is replaced by a link to its target, not to the
+	// symlink itself
+	if err := os.Symlink("data.bin", filepath.Join(src, "link.bin")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	dst = filepath.Join(t.TempDir(), "out")
+	opts = CopyOptions{
+		FollowSymlinks: true,
+		Hardlink:       func(path string) bool { return filepath.Ext(path) == ".bin" },
+	}
+	if err := CopyTree(src, dst, opts); err != nil {
+		t.Fatalf("CopyTree failed: %v", err)
+	}
+	target, errA := os.Stat(filepath.Join(src, "data.bin"))
+	linked, errB := os.Lstat(filepath.Join(dst, "link.bin"))
+	if errA != nil || errB != nil || !linked.Mode().IsRegular() || !os.SameFile(target, linked) {
+		t.Errorf("link.bin = %v, %v; want a hard link to data.bin", linked, errB)
+	}
+}
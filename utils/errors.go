@@ -36,4 +36,19 @@ var (
 
 	// ErrIncompleteBundle indicates bundle is missing required metadata files
 	ErrIncompleteBundle = errors.New("bundle is incomplete (missing required files)")
+
+	// ErrAlreadyABundle indicates create was run on a directory that is already a bundle
+	ErrAlreadyABundle = errors.New("directory is already a bundle")
+
+	// ErrFileNotTracked indicates a path that has no record in the bundle manifest
+	ErrFileNotTracked = errors.New("file is not tracked in the bundle")
+
+	// ErrBundleNotFound indicates no bundle in the pool matches a checksum or prefix
+	ErrBundleNotFound = errors.New("bundle not found in pool")
+
+	// ErrAmbiguousPrefix indicates a checksum prefix that matches several bundles
+	ErrAmbiguousPrefix = errors.New("checksum prefix matches more than one bundle")
+
+	// ErrAlgorithmMismatch indicates bundles hashed with different checksum algorithms
+	ErrAlgorithmMismatch = errors.New("bundles use different checksum algorithms")
 )
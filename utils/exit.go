@@ -53,7 +53,12 @@ func ExitCodeFromError(err error) int {
 		errors.Is(err, ErrInvalidPath) ||
 		errors.Is(err, ErrBundleLocked) ||
 		errors.Is(err, ErrCorruptedBundle) ||
-		errors.Is(err, ErrIncompleteBundle) {
+		errors.Is(err, ErrIncompleteBundle) ||
+		errors.Is(err, ErrAlreadyABundle) ||
+		errors.Is(err, ErrFileNotTracked) ||
+		errors.Is(err, ErrBundleNotFound) ||
+		errors.Is(err, ErrAmbiguousPrefix) ||
+		errors.Is(err, ErrAlgorithmMismatch) {
 		return 1
 	}
 
@@ -69,3 +74,90 @@ func ExitCodeFromError(err error) int {
 	// Default to system error for unknown errors
 	return 2
 }
+
+// Stable error codes for machine-readable error output. Scripts can branch
+// on these instead of on message text.
+const (
+	ErrorCodeUsage           = "usage"              // Invalid command line
+	ErrorCodeNotABundle      = "not_a_bundle"       // ErrNotABundle
+	ErrorCodeInvalidPath     = "invalid_path"       // ErrInvalidPath
+	ErrorCodeLocked          = "locked"             // ErrBundleLocked
+	ErrorCodeCorrupted       = "corrupted"          // ErrCorruptedBundle
+	ErrorCodeIncomplete      = "incomplete_bundle"  // ErrIncompleteBundle
+	ErrorCodeAlreadyABundle  = "already_a_bundle"   // ErrAlreadyABundle
+	ErrorCodeFileNotTracked  = "file_not_tracked"   // ErrFileNotTracked
+	ErrorCodeBundleNotFound  = "bundle_not_found"   // ErrBundleNotFound
+	ErrorCodeAmbiguousPrefix = "ambiguous_prefix"   // ErrAmbiguousPrefix
+	ErrorCodeAlgorithm       = "algorithm_mismatch" // ErrAlgorithmMismatch
+	ErrorCodePermission      = "permission_denied"  // os.ErrPermission
+	ErrorCodeNotExist        = "not_found"          // os.ErrNotExist
+	ErrorCodeUser            = "user_error"         // Other user errors (exit code 1)
+	ErrorCodeIO              = "io_error"           // Other system errors (exit code 2)
+)
+
+// errorCodes maps the error sentinels to their stable error code, checked
+// in order.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrNotABundle, ErrorCodeNotABundle},
+	{ErrInvalidPath, ErrorCodeInvalidPath},
+	{ErrBundleLocked, ErrorCodeLocked},
+	{ErrCorruptedBundle, ErrorCodeCorrupted},
+	{ErrIncompleteBundle, ErrorCodeIncomplete},
+	{ErrAlreadyABundle, ErrorCodeAlreadyABundle},
+	{ErrFileNotTracked, ErrorCodeFileNotTracked},
+	{ErrBundleNotFound, ErrorCodeBundleNotFound},
+	{ErrAmbiguousPrefix, ErrorCodeAmbiguousPrefix},
+	{ErrAlgorithmMismatch, ErrorCodeAlgorithm},
+	{os.ErrPermission, ErrorCodePermission},
+	{os.ErrNotExist, ErrorCodeNotExist},
+}
+
+// ErrorCode maps an error to its stable machine-readable code.
+//
+// Errors wrapping one of the sentinels above get that sentinel's code;
+// other errors get the generic code of their exit code (see
+// ExitErrorCode).
+//
+// Example:
+//
+//	err := fmt.Errorf("open: %w", utils.ErrBundleLocked)
+//	utils.ErrorCode(err)  // "locked"
+//
+// Parameters:
+//   - err: error to classify
+//
+// Returns:
+//   - string: error code, empty for a nil error
+func ErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, entry := range errorCodes {
+		if errors.Is(err, entry.err) {
+			return entry.code
+		}
+	}
+	return ExitErrorCode(ExitCodeFromError(err))
+}
+
+// ExitErrorCode returns the generic error code for an exit code:
+// ErrorCodeUser for 1 and ErrorCodeIO for any other non-zero code.
+//
+// Parameters:
+//   - code: CLI exit code
+//
+// Returns:
+//   - string: error code, empty for 0
+func ExitErrorCode(code int) string {
+	switch code {
+	case 0:
+		return ""
+	case 1:
+		return ErrorCodeUser
+	default:
+		return ErrorCodeIO
+	}
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
+	"os"
 	"testing"
 )
 
@@ -16,6 +19,11 @@ func TestExitCodeFromError(t *testing.T) {
 		{"user error - bundle locked", ErrBundleLocked, 1},
 		{"user error - corrupted", ErrCorruptedBundle, 1},
 		{"user error - incomplete", ErrIncompleteBundle, 1},
+		{"user error - already a bundle", ErrAlreadyABundle, 1},
+		{"user error - file not tracked", ErrFileNotTracked, 1},
+		{"user error - bundle not found", ErrBundleNotFound, 1},
+		{"user error - ambiguous prefix", ErrAmbiguousPrefix, 1},
+		{"user error - algorithm mismatch", ErrAlgorithmMismatch, 1},
 	}
 
 	for _, tt := range tests {
@@ -27,3 +35,34 @@ func TestExitCodeFromError(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil error", nil, ""},
+		{"not a bundle", ErrNotABundle, ErrorCodeNotABundle},
+		{"wrapped lock", fmt.Errorf("acquire: %w", ErrBundleLocked), ErrorCodeLocked},
+		{"incomplete", fmt.Errorf("%w: META.json: bad", ErrIncompleteBundle), ErrorCodeIncomplete},
+		{"algorithm mismatch", fmt.Errorf("%w: sha256 and sha512", ErrAlgorithmMismatch), ErrorCodeAlgorithm},
+		{"permission", &os.PathError{Op: "open", Path: "x", Err: os.ErrPermission}, ErrorCodePermission},
+		{"other", errors.New("disk full"), ErrorCodeIO},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorCode(tt.err); got != tt.want {
+				t.Errorf("ErrorCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := ExitErrorCode(1); got != ErrorCodeUser {
+		t.Errorf("ExitErrorCode(1) = %q, want %q", got, ErrorCodeUser)
+	}
+	if got := ExitErrorCode(2); got != ErrorCodeIO {
+		t.Errorf("ExitErrorCode(2) = %q, want %q", got, ErrorCodeIO)
+	}
+}
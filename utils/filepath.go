@@ -24,9 +24,12 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"syscall"
 )
 
 const bundleMetadataDir = ".bundle"
@@ -55,6 +58,37 @@ func IsBundleDir(path string) bool {
 	return err == nil
 }
 
+// CheckWritable verifies that files can be created in dir.
+//
+// It creates and removes a temporary file, so it reflects ownership, mode
+// bits, ACLs and read-only mounts alike. Use it before expensive work whose
+// result must be saved in dir. Permission problems and read-only file
+// systems are reported wrapped in os.ErrPermission (exit code 2); other
+// errors, such as dir not existing, are returned unchanged.
+//
+// Example:
+//
+//	if err := utils.CheckWritable("/path/to/bundle"); err != nil {
+//	    return err
+//	}
+//
+// Parameters:
+//   - dir: directory that must be writable
+//
+// Returns:
+//   - error: nil if a file could be created and removed in dir
+func CheckWritable(dir string) error {
+	file, err := os.CreateTemp(dir, ".bundle-write-test-*")
+	if err != nil {
+		if errors.Is(err, os.ErrPermission) || errors.Is(err, syscall.EROFS) {
+			return fmt.Errorf("%w: cannot write to %s: %v", os.ErrPermission, dir, err)
+		}
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
 // GetBundleMetadataDir returns the absolute path to the .bundle subdirectory.
 //
 // It constructs the path to the .bundle/ directory but does not verify
@@ -124,3 +158,66 @@ func NormalizePath(path string) (string, error) {
 	}
 	return filepath.Clean(absPath), nil
 }
+
+// IsWithin reports whether path is dir or lies below it.
+//
+// Both paths are made absolute and their symlinks resolved before they are
+// compared, so a link into dir is seen as inside it. path need not exist:
+// its deepest existing ancestor is resolved and the rest appended.
+//
+// Example:
+//
+//	inside, err := utils.IsWithin("/data/photos/backup", "/data/photos")
+//	// inside = true
+//
+// Parameters:
+//   - path: path to check, which may not exist yet
+//   - dir: existing directory
+//
+// Returns:
+//   - bool: true if path is dir or below it
+//   - error: if either path cannot be resolved
+func IsWithin(path, dir string) (bool, error) {
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return false, err
+	}
+	if realDir, err = filepath.Abs(realDir); err != nil {
+		return false, err
+	}
+	realPath, err := resolveExisting(path)
+	if err != nil {
+		return false, err
+	}
+
+	rel, err := filepath.Rel(realDir, realPath)
+	if err != nil {
+		return false, nil
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)), nil
+}
+
+// resolveExisting returns the absolute path with symlinks resolved for
+// path, resolving its deepest existing ancestor when path does not exist.
+func resolveExisting(path string) (string, error) {
+	abs, err := NormalizePath(path)
+	if err != nil {
+		return "", err
+	}
+	rest := ""
+	for {
+		real, err := filepath.EvalSymlinks(abs)
+		if err == nil {
+			return filepath.Join(real, rest), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return filepath.Join(abs, rest), nil
+		}
+		rest = filepath.Join(filepath.Base(abs), rest)
+		abs = parent
+	}
+}
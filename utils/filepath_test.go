@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"errors"
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -59,3 +61,66 @@ func TestNormalizePath(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckWritable(t *testing.T) {
+	dir := t.TempDir()
+	if err := CheckWritable(dir); err != nil {
+		t.Fatalf("CheckWritable() on writable dir error = %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("CheckWritable() left %d files behind", len(entries))
+	}
+
+	if err := CheckWritable(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("CheckWritable() on missing dir error = %v, want not exist", err)
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("permission checks do not apply to root")
+	}
+	readOnly := filepath.Join(dir, "ro")
+	if err := os.Mkdir(readOnly, 0555); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	err := CheckWritable(readOnly)
+	if !errors.Is(err, os.ErrPermission) {
+		t.Errorf("CheckWritable() on read-only dir error = %v, want ErrPermission", err)
+	}
+	if code := ExitCodeFromError(err); code != 2 {
+		t.Errorf("exit code = %d, want 2", code)
+	}
+}
+
+func TestIsWithin(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "photos")
+	if err := os.MkdirAll(filepath.Join(dir, "2024"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	link := filepath.Join(root, "link")
+	if err := os.Symlink(dir, link); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"same directory", dir, true},
+		{"existing subdirectory", filepath.Join(dir, "2024"), true},
+		{"missing subdirectory", filepath.Join(dir, "backup", "clone"), true},
+		{"through a symlink", filepath.Join(link, "clone"), true},
+		{"sibling with common prefix", filepath.Join(root, "photos-backup"), false},
+		{"parent", root, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsWithin(tt.path, dir)
+			if err != nil || got != tt.want {
+				t.Errorf("IsWithin(%s) = %v, %v; want %v", tt.path, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -25,17 +25,33 @@
 package utils
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"reflect"
+	"strings"
+	"text/template"
 
 	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
 )
 
+// SchemaVersion is the version of the CLI's JSON output shapes.
+//
+// It is injected as "schema_version" into every object written by
+// OutputJSON. It is bumped when a field is removed, renamed or changes type;
+// adding fields does not change it.
+const SchemaVersion = 1
+
 // OutputJSON writes data as JSON to stdout.
 //
-// It serializes the data with 2-space indentation for readability.
+// It serializes the data with 2-space indentation for readability. When data
+// encodes to a JSON object a "schema_version" field holding SchemaVersion is
+// added as its first key, so consumers can detect output shape changes.
 //
 // Example:
 //
@@ -51,9 +67,10 @@ import (
 // Output:
 //
 //	{
-//	  "status": "created",
+//	  "schema_version": 1,
+//	  "files": 42,
 //	  "path": "/path/to/bundle",
-//	  "files": 42
+//	  "status": "created"
 //	}
 //
 // Parameters:
@@ -62,9 +79,184 @@ import (
 // Returns:
 //   - error: if JSON encoding fails or write to stdout fails
 func OutputJSON(data interface{}) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(data)
+	return OutputJSONTo(os.Stdout, data)
+}
+
+// OutputJSONTo writes data as JSON to w, in the format of OutputJSON.
+//
+// Example:
+//
+//	var buf bytes.Buffer
+//	if err := utils.OutputJSONTo(&buf, data); err != nil {
+//	    return err
+//	}
+//
+// Parameters:
+//   - w: destination writer
+//   - data: any JSON-serializable value
+//
+// Returns:
+//   - error: if JSON encoding fails or write to w fails
+func OutputJSONTo(w io.Writer, data interface{}) error {
+	raw, err := marshalVersioned(data)
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, raw, "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	_, err = out.WriteTo(w)
+	return err
+}
+
+// marshalVersioned encodes data as compact JSON with the "schema_version"
+// field spliced into objects, keeping the original key order.
+func marshalVersioned(data interface{}) ([]byte, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) > 0 && raw[0] == '{' {
+		version := fmt.Sprintf(`{"schema_version":%d`, SchemaVersion)
+		if string(raw) == "{}" {
+			raw = []byte(version + "}")
+		} else {
+			raw = append([]byte(version+","), raw[1:]...)
+		}
+	}
+	return raw, nil
+}
+
+// OutputYAML writes data as YAML to stdout.
+//
+// The document has the same shape as the OutputJSON output: field names
+// come from the json struct tags, keys keep their order and objects get the
+// "schema_version" field, so consumers can switch formats freely.
+//
+// Example:
+//
+//	data := map[string]interface{}{"status": "created", "files": 42}
+//	if err := utils.OutputYAML(data); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Output:
+//
+//	schema_version: 1
+//	files: 42
+//	status: created
+//
+// Parameters:
+//   - data: any JSON-serializable value
+//
+// Returns:
+//   - error: if encoding fails or write to stdout fails
+func OutputYAML(data interface{}) error {
+	return OutputYAMLTo(os.Stdout, data)
+}
+
+// OutputYAMLTo writes data as YAML to w, in the format of OutputYAML.
+//
+// The JSON encoding is parsed as YAML, which JSON is a subset of, and
+// re-emitted in block style.
+//
+// Parameters:
+//   - w: destination writer
+//   - data: any JSON-serializable value
+//
+// Returns:
+//   - error: if encoding fails or write to w fails
+func OutputYAMLTo(w io.Writer, data interface{}) error {
+	raw, err := marshalVersioned(data)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+	clearYAMLStyle(&doc)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// clearYAMLStyle resets the flow and quoting styles parsed from JSON, so
+// the encoder picks block style and only quotes where YAML needs it.
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
+
+// JSONError is the error object written by OutputJSONError.
+type JSONError struct {
+	Code    string `json:"code"`    // Stable error code, e.g. "not_a_bundle"
+	Message string `json:"message"` // Human-readable message
+}
+
+// OutputJSONError writes a failed command's error as JSON to stdout.
+//
+// The object has a single "error" key (plus "schema_version", as for
+// OutputJSON), so JSON consumers get a parseable result when a command
+// fails. The error code is the generic code of the exit code; use
+// OutputJSONErrorCode to pass a specific code such as ErrorCode(err).
+//
+// Example:
+//
+//	utils.OutputJSONError(2, "failed to save metadata: disk full")
+//
+// Output:
+//
+//	{
+//	  "schema_version": 1,
+//	  "error": {
+//	    "code": "io_error",
+//	    "message": "failed to save metadata: disk full"
+//	  }
+//	}
+//
+// Parameters:
+//   - code: exit code the command is about to exit with
+//   - message: human-readable message
+//
+// Returns:
+//   - error: if JSON encoding fails or write to stdout fails
+func OutputJSONError(code int, message string) error {
+	return OutputJSONErrorCode(ExitErrorCode(code), message)
+}
+
+// OutputJSONErrorCode writes an error with the given stable error code as
+// JSON to stdout, in the format of OutputJSONError.
+//
+// Example:
+//
+//	utils.OutputJSONErrorCode(utils.ErrorCode(err), err.Error())
+//
+// Parameters:
+//   - code: stable error code, e.g. utils.ErrorCodeLocked
+//   - message: human-readable message
+//
+// Returns:
+//   - error: if JSON encoding fails or write to stdout fails
+func OutputJSONErrorCode(code string, message string) error {
+	return OutputJSONErrorTo(os.Stdout, code, message)
+}
+
+// OutputJSONErrorTo writes an error with the given stable error code as
+// JSON to w, in the format of OutputJSONError.
+func OutputJSONErrorTo(w io.Writer, code string, message string) error {
+	return OutputJSONTo(w, map[string]JSONError{"error": {Code: code, Message: message}})
 }
 
 // OutputTable creates a table writer configured for bundle output.
@@ -89,6 +281,167 @@ func OutputTable(writer io.Writer) *tablewriter.Table {
 	return tablewriter.NewWriter(writer)
 }
 
+// tableRenderer is the part of *tablewriter.Table used by WriteTable.
+type tableRenderer interface {
+	Header(elements ...any)
+	Append(rows ...interface{}) error
+	Render() error
+}
+
+// newTableRenderer creates the table used by WriteTable; tests replace it.
+var newTableRenderer = func(w io.Writer) tableRenderer {
+	return OutputTable(w)
+}
+
+// WriteTable renders header and rows as a table to w.
+//
+// The table is rendered into memory first. If appending a row or rendering
+// fails, a warning is logged and the data is written to w as plain
+// tab-separated lines instead, so the output never silently disappears.
+//
+// Example:
+//
+//	err := utils.WriteTable(os.Stdout,
+//	    []string{"File", "Size"},
+//	    [][]string{{"file1.txt", "1.0 KB"}, {"file2.pdf", "2.0 KB"}})
+//
+// Parameters:
+//   - w: destination writer (typically os.Stdout)
+//   - header: column names
+//   - rows: table rows, one value per column
+//
+// Returns:
+//   - error: if writing to w fails
+func WriteTable(w io.Writer, header []string, rows [][]string) error {
+	var buf bytes.Buffer
+	if err := renderTable(newTableRenderer(&buf), header, rows); err != nil {
+		log.Warnf("Table output failed (%v), falling back to plain output", err)
+		return writeTSV(w, header, rows)
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// renderTable fills and renders table.
+func renderTable(table tableRenderer, header []string, rows [][]string) error {
+	cells := make([]any, len(header))
+	for i, h := range header {
+		cells[i] = h
+	}
+	table.Header(cells...)
+	for _, row := range rows {
+		if err := table.Append(row); err != nil {
+			return err
+		}
+	}
+	return table.Render()
+}
+
+// writeTSV writes header and rows as tab-separated lines.
+func writeTSV(w io.Writer, header []string, rows [][]string) error {
+	if _, err := fmt.Fprintln(w, strings.Join(header, "\t")); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OutputCSV writes headers and rows to w as CSV (RFC 4180).
+//
+// Fields containing commas, quotes or newlines are quoted, with embedded
+// quotes doubled, so the output imports cleanly into spreadsheets.
+//
+// Example:
+//
+//	err := utils.OutputCSV([]string{"path", "size"}, rows, os.Stdout)
+//
+// Output:
+//
+//	path,size
+//	"notes, 2024.txt",512
+//
+// Parameters:
+//   - headers: column names; nil or empty omits the header row
+//   - rows: one record per row, one value per column
+//   - w: destination writer (typically os.Stdout)
+//
+// Returns:
+//   - error: if writing to w fails
+func OutputCSV(headers []string, rows [][]string, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if len(headers) > 0 {
+		if err := cw.Write(headers); err != nil {
+			return err
+		}
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// OutputTemplate renders data with a Go text/template to stdout.
+//
+// This provides docker/kubectl-style --format output. When data is a slice
+// the template is applied to each element in turn; otherwise it is applied
+// to data once. A newline is written after each rendering. The template
+// function "json" renders its argument as compact JSON.
+//
+// Example:
+//
+//	err := utils.OutputTemplate("{{.Checksum}} {{.Title}}", bundles)
+//
+// Output:
+//
+//	e3b0c442...  Vacation Photos
+//	a1b2c3d4...  Tax Records
+//
+// Parameters:
+//   - tmpl: Go template text
+//   - data: a struct, or a slice of structs, to render
+//
+// Returns:
+//   - error: if the template cannot be parsed or executed
+func OutputTemplate(tmpl string, data interface{}) error {
+	return OutputTemplateTo(os.Stdout, tmpl, data)
+}
+
+// OutputTemplateTo renders data with a Go text/template to w, as
+// OutputTemplate does for stdout.
+func OutputTemplateTo(w io.Writer, tmpl string, data interface{}) error {
+	t, err := template.New("format").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("invalid format template: %w", err)
+	}
+
+	items := []interface{}{data}
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice {
+		items = make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).Interface()
+		}
+	}
+
+	for _, item := range items {
+		if err := t.Execute(w, item); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ErrorMessage writes an error message to stderr.
 //
 // It formats the message with "Error: " prefix and writes to stderr.
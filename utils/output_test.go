@@ -2,6 +2,9 @@ package utils
 
 import (
 	"bytes"
+	"errors"
+	"io"
+	"strings"
 	"testing"
 )
 
@@ -17,6 +20,35 @@ func TestOutputJSON(t *testing.T) {
 	}
 }
 
+func TestOutputJSONTo_SchemaVersion(t *testing.T) {
+	type result struct {
+		Path  string `json:"path"`
+		Files int    `json:"files"`
+	}
+
+	tests := []struct {
+		name string
+		data interface{}
+		want string
+	}{
+		{"map", map[string]interface{}{"status": "ok"}, "{\n  \"schema_version\": 1,\n  \"status\": \"ok\"\n}\n"},
+		{"struct keeps order", result{"/b", 2}, "{\n  \"schema_version\": 1,\n  \"path\": \"/b\",\n  \"files\": 2\n}\n"},
+		{"empty object", map[string]interface{}{}, "{\n  \"schema_version\": 1\n}\n"},
+		{"array unchanged", []int{1, 2}, "[\n  1,\n  2\n]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := OutputJSONTo(&buf, tt.data); err != nil {
+				t.Fatalf("OutputJSONTo() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("OutputJSONTo() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
 func TestOutputTable(t *testing.T) {
 	var buf bytes.Buffer
 	table := OutputTable(&buf)
@@ -25,3 +57,140 @@ func TestOutputTable(t *testing.T) {
 		t.Error("OutputTable() returned nil")
 	}
 }
+
+// failingTable is a tableRenderer whose Render always fails.
+type failingTable struct{}
+
+func (failingTable) Header(elements ...any)           {}
+func (failingTable) Append(rows ...interface{}) error { return nil }
+func (failingTable) Render() error                    { return errors.New("render failed") }
+
+func TestWriteTable(t *testing.T) {
+	header := []string{"File", "Size"}
+	rows := [][]string{{"a.txt", "1 B"}, {"b.txt", "2 B"}}
+
+	var buf bytes.Buffer
+	if err := WriteTable(&buf, header, rows); err != nil {
+		t.Fatalf("WriteTable() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "a.txt") || !strings.Contains(buf.String(), "b.txt") {
+		t.Errorf("WriteTable() = %q, want both rows", buf.String())
+	}
+
+	// A failing table falls back to tab-separated output
+	saved := newTableRenderer
+	newTableRenderer = func(io.Writer) tableRenderer { return failingTable{} }
+	defer func() { newTableRenderer = saved }()
+
+	buf.Reset()
+	if err := WriteTable(&buf, header, rows); err != nil {
+		t.Fatalf("WriteTable() fallback error = %v", err)
+	}
+	want := "File\tSize\na.txt\t1 B\nb.txt\t2 B\n"
+	if buf.String() != want {
+		t.Errorf("WriteTable() fallback = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestOutputTemplateTo(t *testing.T) {
+	type item struct {
+		Name string
+		Size int
+	}
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		data    interface{}
+		want    string
+		wantErr bool
+	}{
+		{"struct", "{{.Name}}={{.Size}}", item{"a", 1}, "a=1\n", false},
+		{"slice", "{{.Name}}", []item{{"a", 1}, {"b", 2}}, "a\nb\n", false},
+		{"empty slice", "{{.Name}}", []item{}, "", false},
+		{"json func", "{{json .}}", item{"a", 1}, "{\"Name\":\"a\",\"Size\":1}\n", false},
+		{"parse error", "{{.Name", item{}, "", true},
+		{"unknown field", "{{.Missing}}", item{}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := OutputTemplateTo(&buf, tt.tmpl, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("OutputTemplateTo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && buf.String() != tt.want {
+				t.Errorf("OutputTemplateTo() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputJSONErrorTo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := OutputJSONErrorTo(&buf, ErrorCodeLocked, "bundle is locked"); err != nil {
+		t.Fatalf("OutputJSONErrorTo() error = %v", err)
+	}
+	want := `{
+  "schema_version": 1,
+  "error": {
+    "code": "locked",
+    "message": "bundle is locked"
+  }
+}
+`
+	if buf.String() != want {
+		t.Errorf("OutputJSONErrorTo() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestOutputYAMLTo(t *testing.T) {
+	type result struct {
+		Path     string   `json:"path"`
+		Files    int      `json:"files"`
+		Year     string   `json:"year"`
+		Tags     []string `json:"tags"`
+		Verified *bool    `json:"verified"`
+	}
+
+	var buf bytes.Buffer
+	data := result{Path: "/data/photos", Files: 2, Year: "2024", Tags: []string{}}
+	if err := OutputYAMLTo(&buf, data); err != nil {
+		t.Fatalf("OutputYAMLTo() error = %v", err)
+	}
+	want := `schema_version: 1
+path: /data/photos
+files: 2
+year: "2024"
+tags: []
+verified: null
+`
+	if buf.String() != want {
+		t.Errorf("OutputYAMLTo() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestOutputCSV(t *testing.T) {
+	rows := [][]string{
+		{"notes, 2024.txt", "512"},
+		{`say "hi".txt`, "3"},
+	}
+
+	var buf bytes.Buffer
+	if err := OutputCSV([]string{"path", "size"}, rows, &buf); err != nil {
+		t.Fatalf("OutputCSV() error = %v", err)
+	}
+	want := "path,size\n\"notes, 2024.txt\",512\n\"say \"\"hi\"\".txt\",3\n"
+	if buf.String() != want {
+		t.Errorf("OutputCSV() = %q, want %q", buf.String(), want)
+	}
+
+	// Without headers only the rows are written
+	buf.Reset()
+	if err := OutputCSV(nil, rows[1:], &buf); err != nil {
+		t.Fatalf("OutputCSV() error = %v", err)
+	}
+	if want := "\"say \"\"hi\"\".txt\",3\n"; buf.String() != want {
+		t.Errorf("OutputCSV() without header = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,153 @@
+// Package utils provides utility functions for CLI operations, error handling,
+// and output formatting.
+//
+// Worker pool helpers bound the parallelism of every concurrent operation
+// (checksum computation, verification, pool scans) by a single setting so
+// operators have one knob to tune I/O pressure.
+//
+// Example usage:
+//
+//	err := utils.ParallelFor(len(files), utils.MaxConcurrency(), func(i int) error {
+//	    return process(files[i])
+//	})
+package utils
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// defaultConcurrencyCap bounds the default worker count on machines with
+// many CPUs, where disk I/O rather than hashing is the bottleneck.
+const defaultConcurrencyCap = 8
+
+// MaxConcurrency returns the maximum number of parallel workers.
+//
+// It reads the `max_concurrency` configuration key. When the key is unset or
+// not positive, it defaults to the number of CPUs capped at 8.
+//
+// Example:
+//
+//	workers := utils.MaxConcurrency()
+//
+// Returns:
+//   - int: number of workers, always >= 1
+func MaxConcurrency() int {
+	if n := viper.GetInt("max_concurrency"); n > 0 {
+		return n
+	}
+	n := runtime.NumCPU()
+	if n > defaultConcurrencyCap {
+		n = defaultConcurrencyCap
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// IOConcurrency returns the maximum number of files read concurrently.
+//
+// It reads the `io_concurrency` configuration key and defaults to
+// MaxConcurrency. On high-latency storage (NFS, SMB) reads mostly wait, so a
+// value well above the number of CPUs keeps more requests in flight while
+// HashConcurrency still bounds CPU use.
+//
+// Example:
+//
+//	err := utils.ParallelFor(len(paths), utils.IOConcurrency(), readFile)
+//
+// Returns:
+//   - int: number of readers, always >= 1
+func IOConcurrency() int {
+	if n := viper.GetInt("io_concurrency"); n > 0 {
+		return n
+	}
+	return MaxConcurrency()
+}
+
+// HashConcurrency returns the maximum number of files hashed concurrently.
+//
+// It reads the `hash_concurrency` configuration key and defaults to
+// MaxConcurrency. Only the CPU-bound hashing is limited; reads waiting on
+// storage do not count against it.
+//
+// Returns:
+//   - int: number of hashers, always >= 1
+func HashConcurrency() int {
+	if n := viper.GetInt("hash_concurrency"); n > 0 {
+		return n
+	}
+	return MaxConcurrency()
+}
+
+// ParallelFor calls fn for every index in [0, n) using at most workers
+// goroutines.
+//
+// When fn returns an error no new indexes are started and the first error
+// is returned once running calls have finished. Results should be written
+// by index so callers keep a deterministic order.
+//
+// Example:
+//
+//	sums := make([]string, len(paths))
+//	err := utils.ParallelFor(len(paths), utils.MaxConcurrency(), func(i int) error {
+//	    sum, err := checksum.ComputeFileSHA256(paths[i])
+//	    sums[i] = sum
+//	    return err
+//	})
+//
+// Parameters:
+//   - n: number of work items
+//   - workers: maximum number of concurrent calls (values < 1 mean 1)
+//   - fn: work function called with the item index
+//
+// Returns:
+//   - error: the first error returned by fn, or nil
+func ParallelFor(n, workers int, fn func(i int) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	indexes := make(chan int)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := fn(i); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return firstErr
+}
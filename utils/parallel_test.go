@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestParallelFor(t *testing.T) {
+	results := make([]int, 100)
+	var running, peak int32
+
+	err := ParallelFor(len(results), 3, func(i int) error {
+		cur := atomic.AddInt32(&running, 1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if cur <= old || atomic.CompareAndSwapInt32(&peak, old, cur) {
+				break
+			}
+		}
+		results[i] = i * 2
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ParallelFor() error = %v", err)
+	}
+	if peak > 3 {
+		t.Errorf("peak concurrency = %d, want <= 3", peak)
+	}
+	for i, v := range results {
+		if v != i*2 {
+			t.Fatalf("results[%d] = %d, want %d", i, v, i*2)
+		}
+	}
+}
+
+func TestParallelFor_Error(t *testing.T) {
+	want := errors.New("boom")
+	err := ParallelFor(10, 2, func(i int) error {
+		if i == 4 {
+			return want
+		}
+		return nil
+	})
+	if !errors.Is(err, want) {
+		t.Errorf("ParallelFor() error = %v, want %v", err, want)
+	}
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	defer viper.Set("max_concurrency", nil)
+
+	viper.Set("max_concurrency", 3)
+	if got := MaxConcurrency(); got != 3 {
+		t.Errorf("MaxConcurrency() = %d, want 3", got)
+	}
+
+	viper.Set("max_concurrency", 0)
+	if got := MaxConcurrency(); got < 1 || got > defaultConcurrencyCap {
+		t.Errorf("MaxConcurrency() default = %d, want 1..%d", got, defaultConcurrencyCap)
+	}
+}
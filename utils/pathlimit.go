@@ -0,0 +1,131 @@
+// Package utils provides utility functions for CLI operations, error handling,
+// and output formatting.
+//
+// Path length checks catch paths that exceed filesystem limits before they
+// cause opaque failures deep inside checksum computation or pool copies.
+//
+// Example usage:
+//
+//	if err := utils.CheckPathLength(path); err != nil {
+//	    log.Warnf("skipping: %v", err)
+//	}
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"syscall"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// defaultMaxPathLength is PATH_MAX on Linux
+	defaultMaxPathLength = 4096
+
+	// defaultMaxNameLength is NAME_MAX on most filesystems
+	defaultMaxNameLength = 255
+)
+
+// PathTooLongError reports a path that exceeds a length limit.
+//
+// It is returned by CheckPathLength when a configured limit is exceeded and
+// by WrapPathError when the operating system rejects a path as too long.
+//
+// Example:
+//
+//	var tooLong *utils.PathTooLongError
+//	if errors.As(err, &tooLong) {
+//	    fmt.Println("offending path:", tooLong.Path)
+//	}
+type PathTooLongError struct {
+	Path  string // The offending path
+	Limit int    // Exceeded limit in bytes, 0 when reported by the OS
+	Err   error  // Underlying OS error, if any
+}
+
+// Error implements the error interface.
+func (e *PathTooLongError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("path too long: %s: %v", e.Path, e.Err)
+	}
+	return fmt.Sprintf("path too long: %s (limit %d bytes)", e.Path, e.Limit)
+}
+
+// Unwrap returns the underlying OS error.
+func (e *PathTooLongError) Unwrap() error {
+	return e.Err
+}
+
+// PathLimits returns the configured path length limits.
+//
+// It reads the `max_path_length` and `max_name_length` configuration keys,
+// defaulting to 4096 and 255 bytes when unset or not positive.
+//
+// Returns:
+//   - int: maximum length of a full path in bytes
+//   - int: maximum length of a single path component in bytes
+func PathLimits() (int, int) {
+	maxPath := viper.GetInt("max_path_length")
+	if maxPath <= 0 {
+		maxPath = defaultMaxPathLength
+	}
+	maxName := viper.GetInt("max_name_length")
+	if maxName <= 0 {
+		maxName = defaultMaxNameLength
+	}
+	return maxPath, maxName
+}
+
+// CheckPathLength checks path against the configured length limits.
+//
+// The full path, as given, and its final component are checked; relative
+// paths are not resolved first. When walking a tree every directory is
+// visited, so checking the final component covers each level once.
+//
+// Example:
+//
+//	if err := utils.CheckPathLength("/data/photos/2024/img.jpg"); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Parameters:
+//   - path: the path to check
+//
+// Returns:
+//   - error: *PathTooLongError if a limit is exceeded, nil otherwise
+func CheckPathLength(path string) error {
+	maxPath, maxName := PathLimits()
+	if len(path) > maxPath {
+		return &PathTooLongError{Path: path, Limit: maxPath}
+	}
+	if len(filepath.Base(path)) > maxName {
+		return &PathTooLongError{Path: path, Limit: maxName}
+	}
+	return nil
+}
+
+// WrapPathError annotates OS "file name too long" errors with the path.
+//
+// Other errors, including nil, are returned unchanged.
+//
+// Example:
+//
+//	file, err := os.Open(path)
+//	if err != nil {
+//	    return utils.WrapPathError(path, err)
+//	}
+//
+// Parameters:
+//   - path: the path the failed operation was applied to
+//   - err: the error returned by the operation
+//
+// Returns:
+//   - error: *PathTooLongError wrapping err, or err itself
+func WrapPathError(path string, err error) error {
+	if err != nil && errors.Is(err, syscall.ENAMETOOLONG) {
+		return &PathTooLongError{Path: path, Err: err}
+	}
+	return err
+}
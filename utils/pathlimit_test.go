@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestCheckPathLength(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("max_path_length", 40)
+	viper.Set("max_name_length", 10)
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"short", "dir/file.txt", false},
+		{"long component", "dir/averyverylongname.txt", true},
+		{"long path", strings.Repeat("abcdefgh/", 5), true},
+		{"at limit", "0123456789/0123456789", false},
+		{"long parent", "averyverylongname/x", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPathLength(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckPathLength(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			var tooLong *PathTooLongError
+			if err != nil && (!errors.As(err, &tooLong) || tooLong.Path != tt.path) {
+				t.Errorf("CheckPathLength(%q) error = %#v, want *PathTooLongError for the path", tt.path, err)
+			}
+		})
+	}
+}
+
+func TestPathLimitsDefaults(t *testing.T) {
+	viper.Reset()
+	maxPath, maxName := PathLimits()
+	if maxPath != defaultMaxPathLength || maxName != defaultMaxNameLength {
+		t.Errorf("PathLimits() = %d, %d; want %d, %d", maxPath, maxName, defaultMaxPathLength, defaultMaxNameLength)
+	}
+}
+
+func TestWrapPathError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), strings.Repeat("x", 300))
+	_, err := os.Open(path)
+	if err == nil {
+		t.Skip("filesystem accepts 300 byte names")
+	}
+
+	wrapped := WrapPathError(path, err)
+	var tooLong *PathTooLongError
+	if !errors.As(wrapped, &tooLong) {
+		t.Fatalf("WrapPathError() = %v, want *PathTooLongError", wrapped)
+	}
+	if tooLong.Path != path || !errors.Is(wrapped, err) {
+		t.Errorf("WrapPathError() lost path or cause: %v", wrapped)
+	}
+
+	if WrapPathError(path, nil) != nil {
+		t.Errorf("WrapPathError(nil) should be nil")
+	}
+	if other := errors.New("other"); WrapPathError(path, other) != other {
+		t.Errorf("WrapPathError() should not wrap unrelated errors")
+	}
+}